@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+// BlockUser hides the caller's tweets from the user and drops the follows between them
+func (s *Server) BlockUser(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username == authHeader.Username {
+		c.JSON(http.StatusBadRequest, ErrResponse("you can't block yourself"))
+		return
+	}
+
+	_, err := s.transaction.GetUser(c, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	err = s.transaction.BlockTx(c, database.BlockTxParams{
+		BlockerUsername: authHeader.Username,
+		BlockedUsername: uri.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	//blocking drops the follows both ways
+	s.homes.unfollow(authHeader.Username, uri.Username)
+	s.homes.unfollow(uri.Username, authHeader.Username)
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("%v has succesfully been blocked", uri.Username),
+	})
+}
+
+func (s *Server) UnblockUser(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	unblocked, err := s.transaction.DeleteBlock(c, database.DeleteBlockParams{
+		BlockerUsername: authHeader.Username,
+		BlockedUsername: uri.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	//blocking drops the follows both ways
+	s.homes.unfollow(authHeader.Username, uri.Username)
+	s.homes.unfollow(uri.Username, authHeader.Username)
+	if unblocked == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("%v isn't blocked", uri.Username)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("%v has succesfully been unblocked", uri.Username),
+	})
+}
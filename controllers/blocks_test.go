@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockUser(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	testcases := []struct {
+		name          string
+		method        string
+		target        string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Block OK",
+			method: http.MethodPost,
+			target: other.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.BlockTxParams{BlockerUsername: user.Username, BlockedUsername: other.Username}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				transaction.EXPECT().BlockTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Block yourself",
+			method: http.MethodPost,
+			target: user.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().BlockTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Block unknown user",
+			method: http.MethodPost,
+			target: other.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(database.Users{}, sql.ErrNoRows)
+				transaction.EXPECT().BlockTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Block internal server error",
+			method: http.MethodPost,
+			target: other.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				transaction.EXPECT().BlockTx(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "Unblock OK",
+			method: http.MethodDelete,
+			target: other.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.DeleteBlockParams{BlockerUsername: user.Username, BlockedUsername: other.Username}
+				transaction.EXPECT().DeleteBlock(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Unblock not blocked",
+			method: http.MethodDelete,
+			target: other.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().DeleteBlock(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(testcase.method, fmt.Sprintf("/users/%v/block", testcase.target), nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
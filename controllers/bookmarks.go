@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+// timelineResponse is a page of tweets, next_cursor and prev_cursor are passed back
+// as cursor to get the older and newer pages
+type timelineResponse struct {
+	Tweets     []tweetResponse `json:"tweets"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	PrevCursor string          `json:"prev_cursor,omitempty"`
+}
+
+func (s *Server) AddBookmark(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	//only tweets the caller may read can be bookmarked
+	_, _, status, err := s.visibleTweet(c, uri.ID, authHeader.Username)
+	if err != nil {
+		c.JSON(status, ErrResponse(err.Error()))
+		return
+	}
+
+	//bookmarking twice is a no-op
+	_, err = s.transaction.CreateBookmark(c, database.CreateBookmarkParams{
+		Username: authHeader.Username,
+		TweetID:  uri.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Tweet with ID %v has succesfully been bookmarked", uri.ID),
+	})
+}
+
+func (s *Server) RemoveBookmark(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	deleted, err := s.transaction.DeleteBookmark(c, database.DeleteBookmarkParams{
+		Username: authHeader.Username,
+		TweetID:  uri.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("tweet %v isn't bookmarked", uri.ID)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Bookmark on tweet with ID %v has succesfully been removed", uri.ID),
+	})
+}
+
+// ListBookmarks returns the newest bookmarks first
+func (s *Server) ListBookmarks(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	list := "bookmarks:" + authHeader.Username
+
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	rows, err := s.transaction.ListBookmarks(c, database.ListBookmarksParams{
+		Username: authHeader.Username,
+		AfterID:  p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse:  p.Reverse,
+		PageSize: p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	tweets := make([]database.Tweets, len(rows))
+	keys := make([]int64, len(rows))
+	for i, row := range rows {
+		tweets[i] = database.Tweets{
+			ID:          row.ID,
+			Tweet:       row.Tweet,
+			Username:    row.Username,
+			Likes:       row.Likes,
+			CreatedAt:   row.CreatedAt,
+			EditedAt:    row.EditedAt,
+			InReplyToID: row.InReplyToID,
+			RetweetOfID: row.RetweetOfID,
+		}
+		keys[i] = row.BookmarkID
+	}
+	//bookmarks of tweets hidden since stay, they're left out of the page
+	tweets, err = s.visibleTweets(c, tweets, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := timelineResponse{}
+	resp.Tweets, err = s.tweetResponses(c, tweets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
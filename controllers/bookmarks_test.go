@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarks(t *testing.T) {
+	user, _ := randomUser(t)
+	tweet := randomTweets(user)
+
+	row := database.ListBookmarksRow{
+		BookmarkID: 7,
+		ID:         tweet.ID,
+		Tweet:      tweet.Tweet,
+		Username:   tweet.Username,
+		Likes:      tweet.Likes,
+		CreatedAt:  tweet.CreatedAt,
+	}
+
+	protected, _ := randomUser(t)
+	protected.Protected = true
+	hiddenTweet := randomTweets(protected)
+	hiddenRow := database.ListBookmarksRow{
+		BookmarkID: 8,
+		ID:         hiddenTweet.ID,
+		Tweet:      hiddenTweet.Tweet,
+		Username:   hiddenTweet.Username,
+		CreatedAt:  hiddenTweet.CreatedAt,
+	}
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		cursor        *pageCursor
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Add OK",
+			method: http.MethodPost,
+			url:    "/tweets/1/bookmark",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.CreateBookmarkParams{Username: user.Username, TweetID: tweet.ID}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				transaction.EXPECT().CreateBookmark(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Add hidden tweet",
+			method: http.MethodPost,
+			url:    "/tweets/1/bookmark",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(hiddenTweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(protected.Username)).Times(1).Return(protected, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().CreateBookmark(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "List hides tweets hidden since",
+			method: http.MethodGet,
+			url:    "/bookmarks?limit=1",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListBookmarks(gomock.Any(), gomock.Any()).Times(1).Return([]database.ListBookmarksRow{hiddenRow}, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(protected.Username)).Times(1).Return(protected, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Empty(t, resp.Tweets)
+				//the page still moves on past the hidden bookmark
+				require.Equal(t, pageCursor{List: "bookmarks:" + user.Username, ID: 8}, cursorPayload(t, resp.NextCursor))
+			},
+		},
+		{
+			name:   "Add tweet not found",
+			method: http.MethodPost,
+			url:    "/tweets/1/bookmark",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+				transaction.EXPECT().CreateBookmark(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Remove OK",
+			method: http.MethodDelete,
+			url:    "/tweets/1/bookmark",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.DeleteBookmarkParams{Username: user.Username, TweetID: tweet.ID}
+				transaction.EXPECT().DeleteBookmark(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Remove not bookmarked",
+			method: http.MethodDelete,
+			url:    "/tweets/1/bookmark",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().DeleteBookmark(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "List first page",
+			method: http.MethodGet,
+			url:    "/bookmarks?limit=1",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListBookmarksParams{Username: user.Username, BeforeID: math.MaxInt64, PageSize: 1}
+				transaction.EXPECT().ListBookmarks(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.ListBookmarksRow{row}, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{tweet.ID})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Eq([]int64{tweet.ID})).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{tweet.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 1)
+				require.True(t, resp.Tweets[0].Bookmarked)
+				require.Equal(t, pageCursor{List: "bookmarks:" + user.Username, ID: 7}, cursorPayload(t, resp.NextCursor))
+				require.Empty(t, resp.PrevCursor)
+			},
+		},
+		{
+			name:   "List last page",
+			method: http.MethodGet,
+			url:    "/bookmarks",
+			cursor: &pageCursor{List: "bookmarks:" + user.Username, ID: 7},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListBookmarksParams{Username: user.Username, BeforeID: 7, PageSize: defaultPageLimit}
+				transaction.EXPECT().ListBookmarks(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.ListBookmarksRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Empty(t, resp.Tweets)
+				require.Empty(t, resp.NextCursor)
+				require.Equal(t, pageCursor{List: "bookmarks:" + user.Username, ID: 6, Reverse: true}, cursorPayload(t, resp.PrevCursor))
+			},
+		},
+		{
+			name:   "List previous page",
+			method: http.MethodGet,
+			url:    "/bookmarks?limit=1",
+			cursor: &pageCursor{List: "bookmarks:" + user.Username, ID: 3, Reverse: true},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListBookmarksParams{Username: user.Username, AfterID: 3, BeforeID: math.MaxInt64, Reverse: true, PageSize: 1}
+				transaction.EXPECT().ListBookmarks(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.ListBookmarksRow{row}, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{tweet.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 1)
+				require.Equal(t, pageCursor{List: "bookmarks:" + user.Username, ID: 7}, cursorPayload(t, resp.NextCursor))
+				require.Equal(t, pageCursor{List: "bookmarks:" + user.Username, ID: 7, Reverse: true}, cursorPayload(t, resp.PrevCursor))
+			},
+		},
+		{
+			name:   "List cursor of another list",
+			method: http.MethodGet,
+			url:    "/bookmarks",
+			cursor: &pageCursor{List: "bookmarks:someone", ID: 7},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListBookmarks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "List bad limit",
+			method: http.MethodGet,
+			url:    "/bookmarks?limit=1000",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListBookmarks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Unauthorized",
+			method: http.MethodGet,
+			url:    "/bookmarks",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListBookmarks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(testcase.method, withCursor(server, testcase.url, testcase.cursor), nil)
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
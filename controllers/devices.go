@@ -0,0 +1,523 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/e2ee"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+// SignedPrekeyRequest and the other requests below take keys and ciphertext as
+// base64 strings, like encoding/json does for []byte
+type SignedPrekeyRequest struct {
+	KeyID     int64  `json:"key_id" binding:"min=0"`
+	PublicKey []byte `json:"public_key" binding:"required"`
+	Signature []byte `json:"signature" binding:"required"`
+}
+
+type PrekeyRequest struct {
+	KeyID     int64  `json:"key_id" binding:"min=0"`
+	PublicKey []byte `json:"public_key" binding:"required"`
+}
+
+type RegisterDeviceRequest struct {
+	Name           string              `json:"name" binding:"required,max=50"`
+	IdentityKey    []byte              `json:"identity_key" binding:"required"`
+	SigningKey     []byte              `json:"signing_key" binding:"required"`
+	SignedPrekey   SignedPrekeyRequest `json:"signed_prekey" binding:"required"`
+	OneTimePrekeys []PrekeyRequest     `json:"one_time_prekeys" binding:"max=100,dive"`
+}
+
+type UploadPrekeysRequest struct {
+	Prekeys []PrekeyRequest `json:"prekeys" binding:"required,min=1,max=100,dive"`
+}
+
+type EnvelopeRequest struct {
+	DeviceID   int64  `json:"device_id" binding:"required,min=1"`
+	Ciphertext []byte `json:"ciphertext" binding:"required,max=65536"`
+}
+
+// SendEnvelopesRequest carries one envelope per device the message is encrypted to,
+// the recipient's devices and the sender's other ones
+type SendEnvelopesRequest struct {
+	SenderDeviceID int64             `json:"sender_device_id" binding:"required,min=1"`
+	Envelopes      []EnvelopeRequest `json:"envelopes" binding:"required,min=1,max=20,dive"`
+}
+
+type ListEnvelopesRequest struct {
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type AckEnvelopesRequest struct {
+	IDs []int64 `json:"ids" binding:"required,min=1,max=100"`
+}
+
+type deviceURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type deviceResponse struct {
+	ID           int64                 `json:"id"`
+	Name         string                `json:"name"`
+	IdentityKey  []byte                `json:"identity_key"`
+	SigningKey   []byte                `json:"signing_key"`
+	SignedPrekey database.SignedPrekey `json:"signed_prekey"`
+	PrekeyCount  int64                 `json:"prekey_count"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+func newDeviceResponse(device database.Devices, prekeyCount int64) deviceResponse {
+	return deviceResponse{
+		ID:           device.ID,
+		Name:         device.Name,
+		IdentityKey:  device.IdentityKey,
+		SigningKey:   device.SigningKey,
+		SignedPrekey: signedPrekeyOf(device),
+		PrekeyCount:  prekeyCount,
+		CreatedAt:    device.CreatedAt,
+		UpdatedAt:    device.UpdatedAt,
+	}
+}
+
+func signedPrekeyOf(device database.Devices) database.SignedPrekey {
+	return database.SignedPrekey{
+		KeyID:     device.SignedPrekeyID,
+		PublicKey: device.SignedPrekey,
+		Signature: device.SignedPrekeySignature,
+	}
+}
+
+// prekeyBundleResponse leaves the one-time prekey out once the device ran out of them
+type prekeyBundleResponse struct {
+	DeviceID      int64                 `json:"device_id"`
+	IdentityKey   []byte                `json:"identity_key"`
+	SigningKey    []byte                `json:"signing_key"`
+	SignedPrekey  database.SignedPrekey `json:"signed_prekey"`
+	OneTimePrekey *database.Prekey      `json:"one_time_prekey,omitempty"`
+}
+
+type envelopeResponse struct {
+	ID             int64     `json:"id"`
+	DeviceID       int64     `json:"device_id"`
+	SenderUsername string    `json:"sender_username"`
+	SenderDeviceID int64     `json:"sender_device_id"`
+	Ciphertext     []byte    `json:"ciphertext"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newEnvelopeResponse(envelope database.Envelopes) envelopeResponse {
+	return envelopeResponse{
+		ID:             envelope.ID,
+		DeviceID:       envelope.RecipientDeviceID,
+		SenderUsername: envelope.SenderUsername,
+		SenderDeviceID: envelope.SenderDeviceID,
+		Ciphertext:     envelope.Ciphertext,
+		CreatedAt:      envelope.CreatedAt,
+	}
+}
+
+func toPrekeys(reqs []PrekeyRequest) ([]database.Prekey, error) {
+	prekeys := make([]database.Prekey, len(reqs))
+	for i, req := range reqs {
+		if err := e2ee.ValidateKey(req.PublicKey); err != nil {
+			return nil, err
+		}
+		prekeys[i] = database.Prekey{KeyID: req.KeyID, PublicKey: req.PublicKey}
+	}
+	return prekeys, nil
+}
+
+func toSignedPrekey(signingKey []byte, req SignedPrekeyRequest) (database.SignedPrekey, error) {
+	if err := e2ee.VerifySignedPrekey(signingKey, req.PublicKey, req.Signature); err != nil {
+		return database.SignedPrekey{}, err
+	}
+	return database.SignedPrekey{KeyID: req.KeyID, PublicKey: req.PublicKey, Signature: req.Signature}, nil
+}
+
+// ownDevice is the caller's device addressed by the :id param, it writes the error
+// response and returns false otherwise
+func (s *Server) ownDevice(c *gin.Context, id int64) (database.Devices, bool) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	device, err := s.transaction.GetDevice(c, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return device, false
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return device, false
+	}
+	if device.Username != authHeader.Username {
+		c.JSON(http.StatusNotFound, ErrResponse(sql.ErrNoRows.Error()))
+		return device, false
+	}
+	return device, true
+}
+
+// RegisterDevice publishes the public keys of one of the caller's devices. The
+// signed prekey has to verify against the device's signing key.
+func (s *Server) RegisterDevice(c *gin.Context) {
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if err := e2ee.ValidateKey(req.IdentityKey); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	signedPrekey, err := toSignedPrekey(req.SigningKey, req.SignedPrekey)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	prekeys, err := toPrekeys(req.OneTimePrekeys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	registered, err := s.transaction.RegisterDeviceTx(c, database.RegisterDeviceTxParams{
+		Username:       authHeader.Username,
+		Name:           req.Name,
+		IdentityKey:    req.IdentityKey,
+		SigningKey:     req.SigningKey,
+		SignedPrekey:   signedPrekey,
+		OneTimePrekeys: prekeys,
+	})
+	if err != nil {
+		if err == database.ErrTooManyDevices {
+			c.JSON(http.StatusConflict, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newDeviceResponse(registered.Device, registered.PrekeyCount))
+}
+
+// ListDevices lists the caller's devices with how many one-time prekeys each has
+// left, so clients know when to upload more
+func (s *Server) ListDevices(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	devices, err := s.transaction.ListDevices(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	ids := make([]int64, len(devices))
+	for i, device := range devices {
+		ids[i] = device.ID
+	}
+	counts, err := s.transaction.CountOneTimePrekeys(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	prekeyCounts := make(map[int64]int64, len(counts))
+	for _, count := range counts {
+		prekeyCounts[count.DeviceID] = count.Count
+	}
+
+	resp := make([]deviceResponse, len(devices))
+	for i, device := range devices {
+		resp[i] = newDeviceResponse(device, prekeyCounts[device.ID])
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteDevice takes a device out of the directory along with its prekeys and the
+// envelopes it hadn't picked up
+func (s *Server) DeleteDevice(c *gin.Context) {
+	var uri deviceURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	deleted, err := s.transaction.DeleteDevice(c, database.DeleteDeviceParams{
+		ID:       uri.ID,
+		Username: authHeader.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(sql.ErrNoRows.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Device with ID %v has succesfully been deleted", uri.ID),
+	})
+}
+
+// RotateSignedPrekey replaces the signed prekey of one of the caller's devices
+func (s *Server) RotateSignedPrekey(c *gin.Context) {
+	var uri deviceURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req SignedPrekeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	device, ok := s.ownDevice(c, uri.ID)
+	if !ok {
+		return
+	}
+	signedPrekey, err := toSignedPrekey(device.SigningKey, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	device, err = s.transaction.UpdateSignedPrekey(c, database.UpdateSignedPrekeyParams{
+		ID:                    device.ID,
+		Username:              device.Username,
+		SignedPrekeyID:        signedPrekey.KeyID,
+		SignedPrekey:          signedPrekey.PublicKey,
+		SignedPrekeySignature: signedPrekey.Signature,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	counts, err := s.transaction.CountOneTimePrekeys(c, []int64{device.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	var prekeyCount int64
+	if len(counts) > 0 {
+		prekeyCount = counts[0].Count
+	}
+
+	c.JSON(http.StatusOK, newDeviceResponse(device, prekeyCount))
+}
+
+// UploadPrekeys tops up the one-time prekeys of one of the caller's devices
+func (s *Server) UploadPrekeys(c *gin.Context) {
+	var uri deviceURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req UploadPrekeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	prekeys, err := toPrekeys(req.Prekeys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	added, err := s.transaction.AddPrekeysTx(c, database.AddPrekeysTxParams{
+		DeviceID: uri.ID,
+		Username: authHeader.Username,
+		Prekeys:  prekeys,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newDeviceResponse(added.Device, added.PrekeyCount))
+}
+
+var errPrekeyBundleLimit = errors.New("too many prekey bundle requests for this user, try again later")
+
+// GetPrekeyBundles hands out a prekey bundle for every device of the user to start
+// encrypted sessions with. Each call uses up one-time prekeys, so it's only open to
+// users allowed to message the user and rate limited per requester and user.
+func (s *Server) GetPrekeyBundles(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if !s.prekeyBundles.Allow(authHeader.Username + "\x00" + uri.Username) {
+		c.JSON(http.StatusTooManyRequests, ErrResponse(errPrekeyBundleLimit.Error()))
+		return
+	}
+
+	bundles, err := s.transaction.ClaimPrekeyBundlesTx(c, database.ClaimPrekeyBundlesTxParams{
+		Requester: authHeader.Username,
+		Username:  uri.Username,
+	})
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+		case database.ErrDirectMessageBlocked, database.ErrDirectMessagesClosed:
+			c.JSON(http.StatusForbidden, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	resp := make([]prekeyBundleResponse, len(bundles))
+	for i, bundle := range bundles {
+		resp[i] = prekeyBundleResponse{
+			DeviceID:     bundle.Device.ID,
+			IdentityKey:  bundle.Device.IdentityKey,
+			SigningKey:   bundle.Device.SigningKey,
+			SignedPrekey: signedPrekeyOf(bundle.Device),
+		}
+		if bundle.OneTimePrekey != nil {
+			resp[i].OneTimePrekey = &database.Prekey{
+				KeyID:     bundle.OneTimePrekey.KeyID,
+				PublicKey: bundle.OneTimePrekey.PublicKey,
+			}
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SendEnvelopes relays ciphertext to the devices it's encrypted to and pushes it to
+// their owners right away. Envelopes wait for devices that aren't connected until
+// they're acknowledged.
+func (s *Server) SendEnvelopes(c *gin.Context) {
+	var req SendEnvelopesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := database.SendEnvelopesTxParams{
+		Sender:         authHeader.Username,
+		SenderDeviceID: req.SenderDeviceID,
+		Envelopes:      make([]database.EnvelopeInput, len(req.Envelopes)),
+	}
+	for i, envelope := range req.Envelopes {
+		arg.Envelopes[i] = database.EnvelopeInput{DeviceID: envelope.DeviceID, Ciphertext: envelope.Ciphertext}
+	}
+
+	sent, err := s.transaction.SendEnvelopesTx(c, arg)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+		case database.ErrDirectMessageBlocked, database.ErrDirectMessagesClosed:
+			c.JSON(http.StatusForbidden, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	resp := make([]envelopeResponse, len(sent))
+	for i, envelope := range sent {
+		resp[i] = newEnvelopeResponse(envelope.Envelope)
+		s.hub.Publish(dmsTopic(envelope.Recipient), eventEnvelope, resp[i])
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListEnvelopes returns the oldest envelopes waiting for one of the caller's
+// devices. They stay until acknowledged.
+func (s *Server) ListEnvelopes(c *gin.Context) {
+	var uri deviceURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req ListEnvelopesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPageLimit
+	}
+
+	device, ok := s.ownDevice(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	envelopes, err := s.transaction.ListPendingEnvelopes(c, database.ListPendingEnvelopesParams{
+		RecipientDeviceID: device.ID,
+		Limit:             req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := make([]envelopeResponse, len(envelopes))
+	for i, envelope := range envelopes {
+		resp[i] = newEnvelopeResponse(envelope)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AckEnvelopes deletes the envelopes a device has decrypted and stored
+func (s *Server) AckEnvelopes(c *gin.Context) {
+	var uri deviceURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req AckEnvelopesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	device, ok := s.ownDevice(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	deleted, err := s.transaction.DeleteEnvelopes(c, database.DeleteEnvelopesParams{
+		RecipientDeviceID: device.ID,
+		Ids:               req.IDs,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("%v envelopes have succesfully been acknowledged", deleted),
+	})
+}
@@ -0,0 +1,415 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+)
+
+func randomX25519Key(t *testing.T) []byte {
+	private := make([]byte, curve25519.ScalarSize)
+	_, err := rand.Read(private)
+	require.NoError(t, err)
+
+	public, err := curve25519.X25519(private, curve25519.Basepoint)
+	require.NoError(t, err)
+	return public
+}
+
+func TestRegisterDevice(t *testing.T) {
+	user, _ := randomUser(t)
+
+	signingKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	identityKey := randomX25519Key(t)
+	signedPrekey := randomX25519Key(t)
+	oneTimePrekey := randomX25519Key(t)
+	signature := ed25519.Sign(privateKey, signedPrekey)
+
+	request := func(signature []byte) gin.H {
+		return gin.H{
+			"name":         "phone",
+			"identity_key": identityKey,
+			"signing_key":  []byte(signingKey),
+			"signed_prekey": gin.H{
+				"key_id":     1,
+				"public_key": signedPrekey,
+				"signature":  signature,
+			},
+			"one_time_prekeys": []gin.H{{"key_id": 1, "public_key": oneTimePrekey}},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: request(signature),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.RegisterDeviceTxParams{
+					Username:       user.Username,
+					Name:           "phone",
+					IdentityKey:    identityKey,
+					SigningKey:     signingKey,
+					SignedPrekey:   database.SignedPrekey{KeyID: 1, PublicKey: signedPrekey, Signature: signature},
+					OneTimePrekeys: []database.Prekey{{KeyID: 1, PublicKey: oneTimePrekey}},
+				}
+				transaction.EXPECT().RegisterDeviceTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.DeviceTxResult{
+					Device: database.Devices{
+						ID:                    3,
+						Username:              user.Username,
+						Name:                  "phone",
+						IdentityKey:           identityKey,
+						SigningKey:            signingKey,
+						SignedPrekeyID:        1,
+						SignedPrekey:          signedPrekey,
+						SignedPrekeySignature: signature,
+					},
+					PrekeyCount: 1,
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp deviceResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, int64(3), resp.ID)
+				require.Equal(t, identityKey, resp.IdentityKey)
+				require.Equal(t, signature, resp.SignedPrekey.Signature)
+				require.Equal(t, int64(1), resp.PrekeyCount)
+			},
+		},
+		{
+			name: "Signature of another key",
+			body: request(ed25519.Sign(privateKey, identityKey)),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().RegisterDeviceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Low order identity key",
+			body: func() gin.H {
+				body := request(signature)
+				body["identity_key"] = make([]byte, curve25519.PointSize)
+				return body
+			}(),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().RegisterDeviceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Too many devices",
+			body: request(signature),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().RegisterDeviceTx(gomock.Any(), gomock.Any()).Times(1).Return(database.DeviceTxResult{}, database.ErrTooManyDevices)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/devices", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestGetPrekeyBundles(t *testing.T) {
+	user, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+
+	device := database.Devices{
+		ID:                    8,
+		Username:              recipient.Username,
+		IdentityKey:           randomX25519Key(t),
+		SigningKey:            make([]byte, ed25519.PublicKeySize),
+		SignedPrekeyID:        2,
+		SignedPrekey:          randomX25519Key(t),
+		SignedPrekeySignature: []byte("signature"),
+	}
+	prekey := database.OneTimePrekeys{DeviceID: device.ID, KeyID: 11, PublicKey: randomX25519Key(t)}
+
+	testcases := []struct {
+		name          string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ClaimPrekeyBundlesTxParams{Requester: user.Username, Username: recipient.Username}
+				transaction.EXPECT().ClaimPrekeyBundlesTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.PrekeyBundle{
+					{Device: device, OneTimePrekey: &prekey},
+					{Device: database.Devices{ID: 9, Username: recipient.Username}},
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp []prekeyBundleResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp, 2)
+				require.Equal(t, device.SignedPrekey, resp[0].SignedPrekey.PublicKey)
+				require.Equal(t, prekey.KeyID, resp[0].OneTimePrekey.KeyID)
+				require.Equal(t, prekey.PublicKey, resp[0].OneTimePrekey.PublicKey)
+				// the second device ran out of one-time prekeys
+				require.Nil(t, resp[1].OneTimePrekey)
+			},
+		},
+		{
+			name: "Blocked",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ClaimPrekeyBundlesTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, database.ErrDirectMessageBlocked)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "User not found",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ClaimPrekeyBundlesTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/users/"+recipient.Username+"/prekey_bundles", nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestGetPrekeyBundlesRateLimit(t *testing.T) {
+	user, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+
+	server := NewTestServer(t, transaction)
+	limit := server.config.Prekey_Bundle_Limit
+	transaction.EXPECT().ClaimPrekeyBundlesTx(gomock.Any(), gomock.Eq(database.ClaimPrekeyBundlesTxParams{Requester: user.Username, Username: recipient.Username})).
+		Times(limit).Return([]database.PrekeyBundle{}, nil)
+	transaction.EXPECT().ClaimPrekeyBundlesTx(gomock.Any(), gomock.Eq(database.ClaimPrekeyBundlesTxParams{Requester: other.Username, Username: recipient.Username})).
+		Times(1).Return([]database.PrekeyBundle{}, nil)
+
+	fetch := func(requester string) int {
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/users/"+recipient.Username+"/prekey_bundles", nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, requester, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	for i := 0; i < limit; i++ {
+		require.Equal(t, http.StatusOK, fetch(user.Username))
+	}
+	// the limit doesn't claim more prekeys
+	require.Equal(t, http.StatusTooManyRequests, fetch(user.Username))
+
+	// other requesters keep their own count
+	require.Equal(t, http.StatusOK, fetch(other.Username))
+}
+
+func TestSendEnvelopes(t *testing.T) {
+	user, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+	ciphertext := []byte{0x01, 0xfe, 0x42}
+
+	testcases := []struct {
+		name          string
+		body          gin.H
+		pushed        bool
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			body:   gin.H{"sender_device_id": 2, "envelopes": []gin.H{{"device_id": 8, "ciphertext": ciphertext}}},
+			pushed: true,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.SendEnvelopesTxParams{
+					Sender:         user.Username,
+					SenderDeviceID: 2,
+					Envelopes:      []database.EnvelopeInput{{DeviceID: 8, Ciphertext: ciphertext}},
+				}
+				transaction.EXPECT().SendEnvelopesTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.SentEnvelope{{
+					Envelope: database.Envelopes{
+						ID:                40,
+						RecipientDeviceID: 8,
+						SenderUsername:    user.Username,
+						SenderDeviceID:    2,
+						Ciphertext:        ciphertext,
+					},
+					Recipient: recipient.Username,
+				}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp []envelopeResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp, 1)
+				require.Equal(t, ciphertext, resp[0].Ciphertext)
+			},
+		},
+		{
+			name: "No envelopes",
+			body: gin.H{"sender_device_id": 2, "envelopes": []gin.H{}},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SendEnvelopesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DMs closed",
+			body: gin.H{"sender_device_id": 2, "envelopes": []gin.H{{"device_id": 8, "ciphertext": ciphertext}}},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SendEnvelopesTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, database.ErrDirectMessagesClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		sub := server.hub.Subscribe([]string{dmsTopic(recipient.Username)}, 0)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/envelopes", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+
+		select {
+		case event := <-sub.Events():
+			require.True(t, testcase.pushed, testcase.name)
+			require.Equal(t, eventEnvelope, event.Type)
+			require.Equal(t, int64(8), event.Data.(envelopeResponse).DeviceID)
+		default:
+			require.False(t, testcase.pushed, testcase.name)
+		}
+		sub.Close()
+	}
+}
+
+func TestAckEnvelopes(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testcases := []struct {
+		name          string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(8))).Times(1).Return(database.Devices{ID: 8, Username: user.Username}, nil)
+				arg := database.DeleteEnvelopesParams{RecipientDeviceID: 8, Ids: []int64{40, 41}}
+				transaction.EXPECT().DeleteEnvelopes(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Someone else's device",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(8))).Times(1).Return(database.Devices{ID: 8, Username: "someone"}, nil)
+				transaction.EXPECT().DeleteEnvelopes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(gin.H{"ids": []int64{40, 41}})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/devices/8/envelopes/ack", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
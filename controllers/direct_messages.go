@@ -0,0 +1,406 @@
+package controllers
+
+import (
+	"database/sql"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required,max=1000"`
+}
+
+type MarkConversationReadRequest struct {
+	// MessageID is the last message read, the latest one when it's left out
+	MessageID int64 `json:"message_id" binding:"omitempty,min=1"`
+}
+
+type DMSettingsRequest struct {
+	AllowFrom string `json:"allow_from" binding:"required,oneof=everyone following"`
+}
+
+type conversationURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type messageResponse struct {
+	ID             int64     `json:"id"`
+	ConversationID int64     `json:"conversation_id"`
+	Sender         string    `json:"sender"`
+	Body           string    `json:"body"`
+	Kind           string    `json:"kind"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newMessageResponse(message database.Messages) messageResponse {
+	return messageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		Sender:         message.SenderUsername,
+		Body:           message.Body,
+		Kind:           message.Kind,
+		CreatedAt:      message.CreatedAt,
+	}
+}
+
+// readReceipt is how far a participant has read, it's also pushed on dmsTopic when
+// it moves
+type readReceipt struct {
+	ConversationID    int64  `json:"conversation_id"`
+	Username          string `json:"username"`
+	LastReadMessageID int64  `json:"last_read_message_id"`
+}
+
+// participantResponse is a participant's role, only meaningful in groups, and read receipt
+type participantResponse struct {
+	Username          string `json:"username"`
+	Role              string `json:"role"`
+	LastReadMessageID int64  `json:"last_read_message_id"`
+}
+
+type conversationResponse struct {
+	ID           int64                 `json:"id"`
+	IsGroup      bool                  `json:"is_group"`
+	Title        string                `json:"title,omitempty"`
+	Participants []participantResponse `json:"participants"`
+	LastMessage  messageResponse       `json:"last_message"`
+	UnreadCount  int64                 `json:"unread_count"`
+}
+
+type conversationsResponse struct {
+	Conversations []conversationResponse `json:"conversations"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+	PrevCursor    string                 `json:"prev_cursor,omitempty"`
+}
+
+type messagesResponse struct {
+	Messages     []messageResponse     `json:"messages"`
+	Participants []participantResponse `json:"participants"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+	PrevCursor   string                `json:"prev_cursor,omitempty"`
+}
+
+type dmSettingsResponse struct {
+	AllowFrom string `json:"allow_from"`
+}
+
+func newParticipantResponse(participant database.ConversationParticipants) participantResponse {
+	return participantResponse{
+		Username:          participant.Username,
+		Role:              participant.Role,
+		LastReadMessageID: participant.LastReadMessageID,
+	}
+}
+
+func newReadReceipt(participant database.ConversationParticipants) readReceipt {
+	return readReceipt{
+		ConversationID:    participant.ConversationID,
+		Username:          participant.Username,
+		LastReadMessageID: participant.LastReadMessageID,
+	}
+}
+
+// sendMessage writes the response of SendDirectMessageTx and pushes the message to
+// every participant, the sender's other sessions included
+func (s *Server) sendMessage(c *gin.Context, arg database.SendDirectMessageTxParams) {
+	sent, err := s.transaction.SendDirectMessageTx(c, arg)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+		case database.ErrDirectMessageBlocked, database.ErrDirectMessagesClosed:
+			c.JSON(http.StatusForbidden, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	resp := newMessageResponse(sent.Message)
+	for _, username := range sent.Participants {
+		s.hub.Publish(dmsTopic(username), eventMessage, resp)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SendDirectMessage writes to a user, starting the conversation with them if there's
+// none yet
+func (s *Server) SendDirectMessage(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username == authHeader.Username {
+		c.JSON(http.StatusBadRequest, ErrResponse("you can't message yourself"))
+		return
+	}
+
+	_, err := s.transaction.GetUser(c, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	s.sendMessage(c, database.SendDirectMessageTxParams{
+		Sender:    authHeader.Username,
+		Recipient: uri.Username,
+		Body:      req.Body,
+	})
+}
+
+// ReplyInConversation writes to a conversation the caller is in
+func (s *Server) ReplyInConversation(c *gin.Context) {
+	var uri conversationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	s.sendMessage(c, database.SendDirectMessageTxParams{
+		Sender:         authHeader.Username,
+		ConversationID: uri.ID,
+		Body:           req.Body,
+	})
+}
+
+// ListConversations returns the caller's conversations, the one with the latest
+// message first
+func (s *Server) ListConversations(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	list := "conversations:" + authHeader.Username
+
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	conversations, err := s.transaction.ListConversations(c, database.ListConversationsParams{
+		Username: authHeader.Username,
+		AfterID:  p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse:  p.Reverse,
+		PageSize: p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := conversationsResponse{Conversations: make([]conversationResponse, len(conversations))}
+	ids := make([]int64, len(conversations))
+	keys := make([]int64, len(conversations))
+	for i, conversation := range conversations {
+		resp.Conversations[i] = conversationResponse{
+			ID:           conversation.ID,
+			IsGroup:      conversation.IsGroup,
+			Title:        conversation.Title.String,
+			Participants: []participantResponse{},
+			LastMessage: messageResponse{
+				ID:             conversation.LastMessageID,
+				ConversationID: conversation.ID,
+				Sender:         conversation.SenderUsername,
+				Body:           conversation.Body,
+				Kind:           conversation.Kind,
+				CreatedAt:      conversation.CreatedAt,
+			},
+			UnreadCount: conversation.UnreadCount,
+		}
+		ids[i] = conversation.ID
+		keys[i] = conversation.LastMessageID
+	}
+
+	if len(ids) > 0 {
+		participants, err := s.transaction.ListConversationParticipants(c, ids)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+			return
+		}
+		byConversation := map[int64][]participantResponse{}
+		for _, participant := range participants {
+			byConversation[participant.ConversationID] = append(byConversation[participant.ConversationID], newParticipantResponse(participant))
+		}
+		for i := range resp.Conversations {
+			if receipts, ok := byConversation[resp.Conversations[i].ID]; ok {
+				resp.Conversations[i].Participants = receipts
+			}
+		}
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) GetUnreadConversationCount(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	count, err := s.transaction.CountUnreadConversations(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// ListMessages returns the messages of a conversation newest first, with how far
+// each participant has read
+func (s *Server) ListMessages(c *gin.Context) {
+	var uri conversationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	list := "messages:" + strconv.FormatInt(uri.ID, 10)
+
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	participants, err := s.transaction.ListConversationParticipants(c, []int64{uri.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	resp := messagesResponse{Participants: make([]participantResponse, len(participants))}
+	member := false
+	for i, participant := range participants {
+		resp.Participants[i] = newParticipantResponse(participant)
+		member = member || participant.Username == authHeader.Username
+	}
+	if !member {
+		c.JSON(http.StatusNotFound, ErrResponse(sql.ErrNoRows.Error()))
+		return
+	}
+
+	messages, err := s.transaction.ListMessages(c, database.ListMessagesParams{
+		ConversationID: uri.ID,
+		AfterID:        p.AfterID,
+		BeforeID:       p.BeforeID,
+		Reverse:        p.Reverse,
+		PageSize:       p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp.Messages = make([]messageResponse, len(messages))
+	keys := make([]int64, len(messages))
+	for i, message := range messages {
+		resp.Messages[i] = newMessageResponse(message)
+		keys[i] = message.ID
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MarkConversationRead moves the caller's read receipt and pushes it to the other
+// participants. Receipts never move back.
+func (s *Server) MarkConversationRead(c *gin.Context) {
+	var uri conversationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req MarkConversationReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength != 0 {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if req.MessageID == 0 {
+		req.MessageID = math.MaxInt64
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	participant, err := s.transaction.MarkConversationRead(c, database.MarkConversationReadParams{
+		MessageID:      req.MessageID,
+		ConversationID: uri.ID,
+		Username:       authHeader.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	receipt := newReadReceipt(participant)
+	participants, err := s.transaction.ListConversationParticipants(c, []int64{uri.ID})
+	if err == nil {
+		for _, other := range participants {
+			s.hub.Publish(dmsTopic(other.Username), eventMessageRead, receipt)
+		}
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
+
+func (s *Server) GetDMSettings(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	settings, err := database.LoadDMSettings(c, s.transaction, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dmSettingsResponse{AllowFrom: settings.AllowFrom})
+}
+
+// UpdateDMSettings sets who can start a conversation with the caller, everyone or the
+// people they follow
+func (s *Server) UpdateDMSettings(c *gin.Context) {
+	var req DMSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	settings, err := s.transaction.UpsertDMSettings(c, database.UpsertDMSettingsParams{
+		Username:  authHeader.Username,
+		AllowFrom: req.AllowFrom,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dmSettingsResponse{AllowFrom: settings.AllowFrom})
+}
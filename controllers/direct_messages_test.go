@@ -0,0 +1,439 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendDirectMessage(t *testing.T) {
+	sender, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+
+	message := database.Messages{
+		ID:             12,
+		ConversationID: 3,
+		SenderUsername: sender.Username,
+		Body:           "hi there",
+		CreatedAt:      time.Now().UTC().Truncate(time.Second),
+	}
+
+	testcases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		pushed        bool
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: recipient.Username,
+			body:     gin.H{"body": message.Body},
+			pushed:   true,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				arg := database.SendDirectMessageTxParams{Sender: sender.Username, Recipient: recipient.Username, Body: message.Body}
+				transaction.EXPECT().SendDirectMessageTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(database.SendDirectMessageTxResult{Message: message, Participants: []string{recipient.Username, sender.Username}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp messageResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, newMessageResponse(message), resp)
+			},
+		},
+		{
+			name:     "Blocked",
+			username: recipient.Username,
+			body:     gin.H{"body": message.Body},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(recipient, nil)
+				transaction.EXPECT().SendDirectMessageTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.SendDirectMessageTxResult{}, database.ErrDirectMessageBlocked)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Following only",
+			username: recipient.Username,
+			body:     gin.H{"body": message.Body},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(recipient, nil)
+				transaction.EXPECT().SendDirectMessageTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.SendDirectMessageTxResult{}, database.ErrDirectMessagesClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Yourself",
+			username: sender.Username,
+			body:     gin.H{"body": message.Body},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SendDirectMessageTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Empty message",
+			username: recipient.Username,
+			body:     gin.H{"body": ""},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SendDirectMessageTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "User Not Found",
+			username: recipient.Username,
+			body:     gin.H{"body": message.Body},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(database.Users{}, sql.ErrNoRows)
+				transaction.EXPECT().SendDirectMessageTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		sub := server.hub.Subscribe([]string{dmsTopic(recipient.Username)}, 0)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/dms/"+testcase.username, bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, sender.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+
+		select {
+		case event := <-sub.Events():
+			require.True(t, testcase.pushed, testcase.name)
+			require.Equal(t, eventMessage, event.Type)
+			require.Equal(t, newMessageResponse(message), event.Data)
+		default:
+			require.False(t, testcase.pushed, testcase.name)
+		}
+		sub.Close()
+	}
+}
+
+func TestConversations(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	row := database.ListConversationsRow{
+		ID:                3,
+		LastMessageID:     20,
+		LastReadMessageID: 18,
+		SenderUsername:    other.Username,
+		Body:              "you there?",
+		CreatedAt:         time.Now().UTC().Truncate(time.Second),
+		UnreadCount:       2,
+	}
+	participants := []database.ConversationParticipants{
+		{ConversationID: 3, Username: user.Username, LastReadMessageID: 18},
+		{ConversationID: 3, Username: other.Username, LastReadMessageID: 20},
+	}
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		cursor        *pageCursor
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List first page",
+			method: http.MethodGet,
+			url:    "/api/v1/conversations?limit=1",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListConversationsParams{Username: user.Username, BeforeID: math.MaxInt64, PageSize: 1}
+				transaction.EXPECT().ListConversations(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.ListConversationsRow{row}, nil)
+				transaction.EXPECT().ListConversationParticipants(gomock.Any(), gomock.Eq([]int64{row.ID})).Times(1).Return(participants, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp conversationsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Conversations, 1)
+				require.Equal(t, int64(2), resp.Conversations[0].UnreadCount)
+				require.Equal(t, row.Body, resp.Conversations[0].LastMessage.Body)
+				require.Equal(t, []participantResponse{newParticipantResponse(participants[0]), newParticipantResponse(participants[1])}, resp.Conversations[0].Participants)
+				require.Equal(t, pageCursor{List: "conversations:" + user.Username, ID: row.LastMessageID}, cursorPayload(t, resp.NextCursor))
+			},
+		},
+		{
+			name:   "List empty",
+			method: http.MethodGet,
+			url:    "/api/v1/conversations",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListConversations(gomock.Any(), gomock.Any()).Times(1).Return([]database.ListConversationsRow{}, nil)
+				transaction.EXPECT().ListConversationParticipants(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Messages",
+			method: http.MethodGet,
+			url:    "/api/v1/conversations/3/messages",
+			cursor: &pageCursor{List: "messages:3", ID: 20},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListConversationParticipants(gomock.Any(), gomock.Eq([]int64{3})).Times(1).Return(participants, nil)
+				arg := database.ListMessagesParams{ConversationID: 3, BeforeID: 20, PageSize: defaultPageLimit}
+				transaction.EXPECT().ListMessages(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return([]database.Messages{{ID: 19, ConversationID: 3, SenderUsername: other.Username, Body: "hello"}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp messagesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Messages, 1)
+				require.Len(t, resp.Participants, 2)
+			},
+		},
+		{
+			name:   "Messages of someone else's conversation",
+			method: http.MethodGet,
+			url:    "/api/v1/conversations/3/messages",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListConversationParticipants(gomock.Any(), gomock.Any()).Times(1).Return(participants[1:], nil)
+				transaction.EXPECT().ListMessages(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Reply outside the conversation",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations/3/messages",
+			body:   gin.H{"body": "hi"},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.SendDirectMessageTxParams{Sender: user.Username, ConversationID: 3, Body: "hi"}
+				transaction.EXPECT().SendDirectMessageTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.SendDirectMessageTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Mark read up to the latest",
+			method: http.MethodPut,
+			url:    "/api/v1/conversations/3/read",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.MarkConversationReadParams{MessageID: math.MaxInt64, ConversationID: 3, Username: user.Username}
+				transaction.EXPECT().MarkConversationRead(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(database.ConversationParticipants{ConversationID: 3, Username: user.Username, LastReadMessageID: 20}, nil)
+				transaction.EXPECT().ListConversationParticipants(gomock.Any(), gomock.Any()).Times(1).Return(participants, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp readReceipt
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, int64(20), resp.LastReadMessageID)
+			},
+		},
+		{
+			name:   "Mark read up to a message",
+			method: http.MethodPut,
+			url:    "/api/v1/conversations/3/read",
+			body:   gin.H{"message_id": 19},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.MarkConversationReadParams{MessageID: 19, ConversationID: 3, Username: user.Username}
+				transaction.EXPECT().MarkConversationRead(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(database.ConversationParticipants{ConversationID: 3, Username: user.Username, LastReadMessageID: 19}, nil)
+				transaction.EXPECT().ListConversationParticipants(gomock.Any(), gomock.Any()).Times(1).Return(participants, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Mark read outside the conversation",
+			method: http.MethodPut,
+			url:    "/api/v1/conversations/3/read",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().MarkConversationRead(gomock.Any(), gomock.Any()).Times(1).Return(database.ConversationParticipants{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Unread count",
+			method: http.MethodGet,
+			url:    "/api/v1/conversations/unread_count",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CountUnreadConversations(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(4), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"unread_count":4}`, recorder.Body.String())
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		var data []byte
+		if testcase.body != nil {
+			var err error
+			data, err = json.Marshal(testcase.body)
+			require.NoError(t, err)
+		}
+		req, err := http.NewRequest(testcase.method, withCursor(server, testcase.url, testcase.cursor), bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestMarkConversationReadPushesReceipt(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+
+	receipt := database.ConversationParticipants{ConversationID: 3, Username: user.Username, LastReadMessageID: 20}
+	transaction.EXPECT().MarkConversationRead(gomock.Any(), gomock.Any()).Times(1).Return(receipt, nil)
+	transaction.EXPECT().ListConversationParticipants(gomock.Any(), gomock.Eq([]int64{3})).Times(1).
+		Return([]database.ConversationParticipants{receipt, {ConversationID: 3, Username: other.Username}}, nil)
+
+	server := NewTestServer(t, transaction)
+	sub := server.hub.Subscribe([]string{dmsTopic(other.Username)}, 0)
+	defer sub.Close()
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/conversations/%d/read", receipt.ConversationID), nil)
+	require.NoError(t, err)
+	AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	select {
+	case event := <-sub.Events():
+		require.Equal(t, eventMessageRead, event.Type)
+		require.Equal(t, newReadReceipt(receipt), event.Data)
+	default:
+		t.Fatal("read receipt wasn't pushed")
+	}
+}
+
+func TestDMSettings(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testcases := []struct {
+		name          string
+		method        string
+		body          gin.H
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Get default",
+			method: http.MethodGet,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetDMSettings(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(database.DmSettings{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"allow_from":"everyone"}`, recorder.Body.String())
+			},
+		},
+		{
+			name:   "Update OK",
+			method: http.MethodPut,
+			body:   gin.H{"allow_from": database.DMAllowFollowing},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.UpsertDMSettingsParams{Username: user.Username, AllowFrom: database.DMAllowFollowing}
+				transaction.EXPECT().UpsertDMSettings(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(database.DmSettings{Username: user.Username, AllowFrom: database.DMAllowFollowing}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"allow_from":"following"}`, recorder.Body.String())
+			},
+		},
+		{
+			name:   "Update unknown setting",
+			method: http.MethodPut,
+			body:   gin.H{"allow_from": "nobody"},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().UpsertDMSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(testcase.method, "/api/v1/dms/settings", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
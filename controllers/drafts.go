@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// drafts can be saved half written, CreateTweetRequest only applies when publishing
+type DraftRequest struct {
+	Tweet    string  `json:"tweet"`
+	MediaIDs []int64 `json:"media_ids" binding:"max=4"`
+}
+
+type draftURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type draftResponse struct {
+	ID        int64     `json:"id"`
+	Tweet     string    `json:"tweet"`
+	MediaIDs  []int64   `json:"media_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newDraftResponse(draft database.Drafts) draftResponse {
+	return draftResponse{
+		ID:        draft.ID,
+		Tweet:     draft.Tweet,
+		MediaIDs:  draft.MediaIds,
+		CreatedAt: draft.CreatedAt,
+		UpdatedAt: draft.UpdatedAt,
+	}
+}
+
+func (req DraftRequest) mediaIDs() []int64 {
+	if req.MediaIDs == nil {
+		return []int64{}
+	}
+	return req.MediaIDs
+}
+
+func (s *Server) CreateDraft(c *gin.Context) {
+	var req DraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	draft, err := s.transaction.CreateDraft(c, database.CreateDraftParams{
+		Username: authHeader.Username,
+		Tweet:    req.Tweet,
+		MediaIds: req.mediaIDs(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newDraftResponse(draft))
+}
+
+func (s *Server) UpdateDraft(c *gin.Context) {
+	var uri draftURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	var req DraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	draft, err := s.transaction.UpdateDraft(c, database.UpdateDraftParams{
+		Tweet:    req.Tweet,
+		MediaIds: req.mediaIDs(),
+		ID:       uri.ID,
+		Username: authHeader.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newDraftResponse(draft))
+}
+
+func (s *Server) ListDrafts(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	drafts, err := s.transaction.ListDrafts(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := []draftResponse{}
+	for _, draft := range drafts {
+		resp = append(resp, newDraftResponse(draft))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) DeleteDraft(c *gin.Context) {
+	var uri draftURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	deleted, err := s.transaction.DeleteDraft(c, database.DeleteDraftParams{
+		ID:       uri.ID,
+		Username: authHeader.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("draft %v not found", uri.ID)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Draft with ID %v has succesfully been deleted", uri.ID),
+	})
+}
+
+func (s *Server) PublishDraft(c *gin.Context) {
+	var uri draftURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	//same rules as POST /tweet, checked on the locked draft inside the transaction
+	var validationErr error
+	created, err := s.transaction.PublishDraftTx(c, database.PublishDraftTxParams{
+		ID:       uri.ID,
+		Username: authHeader.Username,
+		Validate: func(draft database.Drafts) error {
+			validationErr = binding.Validator.ValidateStruct(&CreateTweetRequest{
+				Tweet:    draft.Tweet,
+				MediaIDs: draft.MediaIds,
+			})
+			return validationErr
+		},
+	})
+	if err != nil {
+		switch {
+		case validationErr != nil, err == database.ErrMediaUnavailable:
+			c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		case err == sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, s.publishTweet(c, created))
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomDraft(user database.Users) database.Drafts {
+	return database.Drafts{
+		ID:        1,
+		Username:  user.Username,
+		Tweet:     util.GetRandomString(15),
+		MediaIds:  []int64{},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// publishWith mimics PublishDraftTx, the draft is only published when it passes validation
+func publishWith(draft database.Drafts, tweet database.Tweets) func(context.Context, database.PublishDraftTxParams) (database.CreateTweetTxResult, error) {
+	return func(_ context.Context, arg database.PublishDraftTxParams) (database.CreateTweetTxResult, error) {
+		if err := arg.Validate(draft); err != nil {
+			return database.CreateTweetTxResult{}, err
+		}
+		return database.CreateTweetTxResult{Tweet: tweet, Media: []database.Media{}}, nil
+	}
+}
+
+func TestDrafts(t *testing.T) {
+	user, _ := randomUser(t)
+	draft := randomDraft(user)
+	tweet := randomTweets(user)
+
+	emptyDraft := draft
+	emptyDraft.Tweet = ""
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Create empty draft",
+			method: http.MethodPost,
+			url:    "/drafts",
+			body:   gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.CreateDraftParams{
+					Username: user.Username,
+					MediaIds: []int64{},
+				}
+				transaction.EXPECT().CreateDraft(gomock.Any(), gomock.Eq(arg)).Times(1).Return(emptyDraft, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Create too many media",
+			method: http.MethodPost,
+			url:    "/drafts",
+			body:   gin.H{"media_ids": []int64{1, 2, 3, 4, 5}},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateDraft(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Update OK",
+			method: http.MethodPut,
+			url:    "/drafts/1",
+			body:   gin.H{"tweet": draft.Tweet},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.UpdateDraftParams{
+					Tweet:    draft.Tweet,
+					MediaIds: []int64{},
+					ID:       1,
+					Username: user.Username,
+				}
+				transaction.EXPECT().UpdateDraft(gomock.Any(), gomock.Eq(arg)).Times(1).Return(draft, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Update not found",
+			method: http.MethodPut,
+			url:    "/drafts/1",
+			body:   gin.H{"tweet": draft.Tweet},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().UpdateDraft(gomock.Any(), gomock.Any()).Times(1).Return(database.Drafts{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "List OK",
+			method: http.MethodGet,
+			url:    "/drafts",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListDrafts(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.Drafts{draft, emptyDraft}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp []draftResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp, 2)
+			},
+		},
+		{
+			name:   "Delete not found",
+			method: http.MethodDelete,
+			url:    "/drafts/1",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.DeleteDraftParams{ID: 1, Username: user.Username}
+				transaction.EXPECT().DeleteDraft(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Publish OK",
+			method: http.MethodPost,
+			url:    "/drafts/1/publish",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().PublishDraftTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(publishWith(draft, tweet))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp tweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, tweet.ID, resp.ID)
+			},
+		},
+		{
+			name:   "Publish empty draft",
+			method: http.MethodPost,
+			url:    "/drafts/1/publish",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().PublishDraftTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(publishWith(emptyDraft, tweet))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Publish not found",
+			method: http.MethodPost,
+			url:    "/drafts/1/publish",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().PublishDraftTx(gomock.Any(), gomock.Any()).Times(1).Return(database.CreateTweetTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		body := bytes.NewReader(nil)
+		if testcase.body != nil {
+			data, err := json.Marshal(testcase.body)
+			require.NoError(t, err)
+			body = bytes.NewReader(data)
+		}
+
+		req, err := http.NewRequest(testcase.method, testcase.url, body)
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+// ListFollowRequests lists the users waiting for the caller to approve their follow,
+// newest first
+func (s *Server) ListFollowRequests(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	list := "follow_requests:" + authHeader.Username
+
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	requests, err := s.transaction.ListFollowRequests(c, database.ListFollowRequestsParams{
+		Username: authHeader.Username,
+		AfterID:  p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse:  p.Reverse,
+		PageSize: p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := usersPageResponse{Users: []userSummaryResponse{}}
+	keys := make([]int64, len(requests))
+	for i, request := range requests {
+		resp.Users = append(resp.Users, userSummaryResponse{
+			Username:       request.Username,
+			Name:           request.Name,
+			FollowersCount: request.FollowersCount.Int32,
+			FollowingCount: request.FollowingCount.Int32,
+		})
+		keys[i] = request.RequestID
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ApproveFollowRequest lets the requester follow the caller
+func (s *Server) ApproveFollowRequest(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err := s.transaction.ApproveFollowRequestTx(c, database.FollowInputArgs{
+		Username:   uri.Username,
+		FollowUser: authHeader.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("%v hasn't requested to follow you", uri.Username)))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	s.homes.follow(uri.Username, authHeader.Username)
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("%v now follows %v", uri.Username, authHeader.Username),
+	})
+}
+
+// DenyFollowRequest drops the request, the requester may ask again
+func (s *Server) DenyFollowRequest(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	denied, err := s.transaction.DeleteFollowRequest(c, database.DeleteFollowRequestParams{
+		RequesterUsername: uri.Username,
+		TargetUsername:    authHeader.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if denied == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("%v hasn't requested to follow you", uri.Username)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("the request of %v to follow you has been denied", uri.Username),
+	})
+}
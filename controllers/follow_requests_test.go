@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowRequests(t *testing.T) {
+	user, _ := randomUser(t)
+	requester, _ := randomUser(t)
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List OK",
+			method: http.MethodGet,
+			url:    "/api/v1/follow_requests?limit=1",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListFollowRequestsParams{Username: user.Username, BeforeID: 1<<63 - 1, PageSize: 1}
+				transaction.EXPECT().ListFollowRequests(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return([]database.ListFollowRequestsRow{{RequestID: 4, Username: requester.Username, Name: requester.Name}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var resp usersPageResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Users, 1)
+				require.Equal(t, requester.Username, resp.Users[0].Username)
+				require.NotEmpty(t, resp.NextCursor)
+			},
+		},
+		{
+			name:   "List internal server error",
+			method: http.MethodGet,
+			url:    "/api/v1/follow_requests",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListFollowRequests(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "Approve OK",
+			method: http.MethodPut,
+			url:    "/api/v1/follow_requests/" + requester.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.FollowInputArgs{Username: requester.Username, FollowUser: user.Username}
+				transaction.EXPECT().ApproveFollowRequestTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.FollowInputResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Approve without request",
+			method: http.MethodPut,
+			url:    "/api/v1/follow_requests/" + requester.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ApproveFollowRequestTx(gomock.Any(), gomock.Any()).Times(1).Return(database.FollowInputResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Approve internal server error",
+			method: http.MethodPut,
+			url:    "/api/v1/follow_requests/" + requester.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ApproveFollowRequestTx(gomock.Any(), gomock.Any()).Times(1).Return(database.FollowInputResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "Deny OK",
+			method: http.MethodDelete,
+			url:    "/api/v1/follow_requests/" + requester.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.DeleteFollowRequestParams{RequesterUsername: requester.Username, TargetUsername: user.Username}
+				transaction.EXPECT().DeleteFollowRequest(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Deny without request",
+			method: http.MethodDelete,
+			url:    "/api/v1/follow_requests/" + requester.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().DeleteFollowRequest(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(testcase.method, testcase.url, nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
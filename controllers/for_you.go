@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/ranking"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// forYouCandidates is how many of the newest home timeline tweets get scored
+	forYouCandidates = 500
+	// affinityWindow is how far back interactions with an author count
+	affinityWindow = 30 * 24 * time.Hour
+)
+
+type ForYouRequest struct {
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// GetForYouFeed is the opt-in ranked home timeline. The recent home timeline tweets
+// are scored by the server's ranker, it's a single page, reloading it ranks again.
+func (s *Server) GetForYouFeed(c *gin.Context) {
+	var req ForYouRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPageLimit
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	now := time.Now()
+
+	rows, err := s.transaction.ListRankingCandidates(c, database.ListRankingCandidatesParams{
+		Username: authHeader.Username,
+		CandidateLimit: forYouCandidates,
+		Since: now.Add(-s.config.For_You_Window),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	affinities := map[string]int64{}
+	authors := []string{}
+	for _, row := range rows {
+		if _, ok := affinities[row.Username]; !ok {
+			affinities[row.Username] = 0
+			authors = append(authors, row.Username)
+		}
+	}
+	if len(authors) > 0 {
+		interactions, err := s.transaction.ListAuthorAffinities(c, database.ListAuthorAffinitiesParams{
+			Username: authHeader.Username,
+			Since: now.Add(-affinityWindow),
+			Authors: authors,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+			return
+		}
+		for _, interaction := range interactions {
+			affinities[interaction.AuthorUsername] = interaction.Interactions
+		}
+	}
+
+	candidates := make([]ranking.Candidate, len(rows))
+	tweets := make(map[int64]database.Tweets, len(rows))
+	for i, row := range rows {
+		candidates[i] = ranking.Candidate{
+			TweetID: row.ID,
+			Author: row.Username,
+			CreatedAt: row.CreatedAt,
+			Likes: int64(row.Likes.Int32),
+			Replies: row.ReplyCount,
+			Retweets: row.RetweetCount,
+			Affinity: affinities[row.Username],
+		}
+		tweets[row.ID] = database.Tweets{
+			ID: row.ID,
+			Tweet: row.Tweet,
+			Username: row.Username,
+			Likes: row.Likes,
+			CreatedAt: row.CreatedAt,
+			EditedAt: row.EditedAt,
+			InReplyToID: row.InReplyToID,
+			RetweetOfID: row.RetweetOfID,
+		}
+	}
+
+	ranked := s.ranker.Rank(candidates, now, int(req.Limit))
+	page := make([]database.Tweets, len(ranked))
+	for i, scored := range ranked {
+		page[i] = tweets[scored.TweetID]
+	}
+	page, err = s.visibleTweets(c, page, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := timelineResponse{}
+	resp.Tweets, err = s.tweetResponses(c, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
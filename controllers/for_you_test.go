@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/ranking"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetForYouFeed(t *testing.T) {
+	user, _ := randomUser(t)
+	friend, _ := randomUser(t)
+	stranger, _ := randomUser(t)
+
+	now := time.Now()
+	rows := []database.ListRankingCandidatesRow{
+		{ID: 3, Tweet: "fresh", Username: stranger.Username, CreatedAt: now.Add(-time.Minute)},
+		{ID: 2, Tweet: "from a friend", Username: friend.Username, CreatedAt: now.Add(-2 * time.Hour), ReplyCount: 1},
+		{ID: 1, Tweet: "old", Username: stranger.Username, CreatedAt: now.Add(-40 * time.Hour)},
+	}
+	ids := func(t *testing.T, recorder *httptest.ResponseRecorder) []int64 {
+		var resp timelineResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		ids := []int64{}
+		for _, tweet := range resp.Tweets {
+			ids = append(ids, tweet.ID)
+		}
+		return ids
+	}
+
+	testcases := []struct {
+		name          string
+		query         string
+		ranker        *ranking.Ranker
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?limit=2",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				affinities := []database.ListAuthorAffinitiesRow{{AuthorUsername: friend.Username, Interactions: 30}}
+				transaction.EXPECT().ListRankingCandidates(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg database.ListRankingCandidatesParams) ([]database.ListRankingCandidatesRow, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, int32(forYouCandidates), arg.CandidateLimit)
+						require.WithinDuration(t, now.Add(-48*time.Hour), arg.Since, time.Minute)
+						return rows, nil
+					})
+				transaction.EXPECT().ListAuthorAffinities(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg database.ListAuthorAffinitiesParams) ([]database.ListAuthorAffinitiesRow, error) {
+						require.Equal(t, []string{stranger.Username, friend.Username}, arg.Authors)
+						return affinities, nil
+					})
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(friend.Username)).Times(1).Return(friend, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(stranger.Username)).Times(1).Return(stranger, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(2).Return(false, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{2, 3})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				//the friend's reply-drawing tweet beats a fresher one from a stranger
+				require.Equal(t, []int64{2, 3}, ids(t, recorder))
+			},
+		},
+		{
+			name:  "Custom ranker",
+			query: "",
+			ranker: &ranking.Ranker{Scorer: ranking.ScorerFunc(func(candidate ranking.Candidate, now time.Time) float64 {
+				return -float64(candidate.TweetID)
+			})},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListRankingCandidates(gomock.Any(), gomock.Any()).Times(1).Return(rows, nil)
+				transaction.EXPECT().ListAuthorAffinities(gomock.Any(), gomock.Any()).Times(1).Return([]database.ListAuthorAffinitiesRow{}, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(friend.Username)).Times(1).Return(friend, nil)
+				//the stranger blocked the caller since
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(stranger.Username)).Times(1).Return(stranger, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(database.IsBlockedParams{BlockerUsername: stranger.Username, BlockedUsername: user.Username})).Times(1).Return(true, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{2})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, []int64{2}, ids(t, recorder))
+			},
+		},
+		{
+			name:  "No candidates",
+			query: "",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListRankingCandidates(gomock.Any(), gomock.Any()).Times(1).Return([]database.ListRankingCandidatesRow{}, nil)
+				transaction.EXPECT().ListAuthorAffinities(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, ids(t, recorder))
+			},
+		},
+		{
+			name:  "Bad limit",
+			query: "?limit=101",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListRankingCandidates(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Internal server error",
+			query: "",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListRankingCandidates(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		if testcase.ranker != nil {
+			server.ranker = testcase.ranker
+		}
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/feeds/for_you"+testcase.query, nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateGroupRequest struct {
+	Title   string   `json:"title" binding:"required,max=50"`
+	Members []string `json:"members" binding:"required,min=1,max=49,dive,min=1,max=30"`
+}
+
+type RenameGroupRequest struct {
+	Title string `json:"title" binding:"required,max=50"`
+}
+
+type AddGroupMembersRequest struct {
+	Usernames []string `json:"usernames" binding:"required,min=1,max=49,dive,min=1,max=30"`
+}
+
+type SetGroupRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+type groupMemberURI struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required,min=1,max=30"`
+}
+
+// groupResponse is a group after a change with the system message recording it
+type groupResponse struct {
+	ID      int64           `json:"id"`
+	Title   string          `json:"title"`
+	Message messageResponse `json:"message"`
+}
+
+// groupChanged writes the response of a group transaction and pushes its system
+// message to everyone concerned, the users it removed included
+func (s *Server) groupChanged(c *gin.Context, changed database.GroupChangeTxResult, err error) {
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+		case database.ErrNotGroupAdmin, database.ErrDirectMessageBlocked, database.ErrDirectMessagesClosed:
+			c.JSON(http.StatusForbidden, ErrResponse(err.Error()))
+		case database.ErrNotGroupConversation, database.ErrGroupFull, database.ErrLastGroupAdmin:
+			c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		case database.ErrNoGroupChange:
+			c.JSON(http.StatusConflict, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	message := newMessageResponse(changed.Message)
+	for _, username := range changed.Participants {
+		s.hub.Publish(dmsTopic(username), eventMessage, message)
+	}
+
+	c.JSON(http.StatusOK, groupResponse{
+		ID:      changed.Conversation.ID,
+		Title:   changed.Conversation.Title.String,
+		Message: message,
+	})
+}
+
+// CreateGroup starts a group conversation with the caller as its admin. Members are
+// held to their DM settings like when they're messaged.
+func (s *Server) CreateGroup(c *gin.Context) {
+	var req CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	changed, err := s.transaction.CreateGroupConversationTx(c, database.CreateGroupConversationTxParams{
+		Creator: authHeader.Username,
+		Title:   req.Title,
+		Members: req.Members,
+	})
+	s.groupChanged(c, changed, err)
+}
+
+func (s *Server) RenameGroup(c *gin.Context) {
+	var uri conversationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req RenameGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	changed, err := s.transaction.RenameGroupTx(c, database.RenameGroupTxParams{
+		ConversationID: uri.ID,
+		Actor:          authHeader.Username,
+		Title:          req.Title,
+	})
+	s.groupChanged(c, changed, err)
+}
+
+func (s *Server) AddGroupMembers(c *gin.Context) {
+	var uri conversationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req AddGroupMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	changed, err := s.transaction.AddGroupMembersTx(c, database.AddGroupMembersTxParams{
+		ConversationID: uri.ID,
+		Actor:          authHeader.Username,
+		Usernames:      req.Usernames,
+	})
+	s.groupChanged(c, changed, err)
+}
+
+func (s *Server) RemoveGroupMember(c *gin.Context) {
+	var uri groupMemberURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username == authHeader.Username {
+		c.JSON(http.StatusBadRequest, ErrResponse("leave the group instead"))
+		return
+	}
+
+	changed, err := s.transaction.RemoveGroupMemberTx(c, database.GroupMemberTxParams{
+		ConversationID: uri.ID,
+		Actor:          authHeader.Username,
+		Username:       uri.Username,
+	})
+	s.groupChanged(c, changed, err)
+}
+
+func (s *Server) LeaveGroup(c *gin.Context) {
+	var uri conversationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	changed, err := s.transaction.LeaveGroupTx(c, database.GroupMemberTxParams{
+		ConversationID: uri.ID,
+		Actor:          authHeader.Username,
+		Username:       authHeader.Username,
+	})
+	s.groupChanged(c, changed, err)
+}
+
+func (s *Server) SetGroupRole(c *gin.Context) {
+	var uri groupMemberURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	var req SetGroupRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	changed, err := s.transaction.SetGroupRoleTx(c, database.SetGroupRoleTxParams{
+		ConversationID: uri.ID,
+		Actor:          authHeader.Username,
+		Username:       uri.Username,
+		Role:           req.Role,
+	})
+	s.groupChanged(c, changed, err)
+}
@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupConversations(t *testing.T) {
+	admin, _ := randomUser(t)
+	member, _ := randomUser(t)
+
+	group := database.Conversations{ID: 5, IsGroup: true, Title: sql.NullString{String: "weekend", Valid: true}}
+	changed := func(body string, participants ...string) database.GroupChangeTxResult {
+		return database.GroupChangeTxResult{
+			Conversation: group,
+			Message: database.Messages{
+				ID:             30,
+				ConversationID: group.ID,
+				SenderUsername: admin.Username,
+				Body:           body,
+				Kind:           database.MessageSystem,
+			},
+			Participants: participants,
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		pushed        bool
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Create OK",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations",
+			body:   gin.H{"title": "weekend", "members": []string{member.Username}},
+			pushed: true,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.CreateGroupConversationTxParams{Creator: admin.Username, Title: "weekend", Members: []string{member.Username}}
+				transaction.EXPECT().CreateGroupConversationTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(changed(admin.Username+" created the group with "+member.Username, admin.Username, member.Username), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp groupResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, group.ID, resp.ID)
+				require.Equal(t, "weekend", resp.Title)
+				require.Equal(t, database.MessageSystem, resp.Message.Kind)
+			},
+		},
+		{
+			name:   "Create too big",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations",
+			body:   gin.H{"title": "everyone", "members": make([]string, database.MaxGroupParticipants)},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateGroupConversationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Create with someone closed to DMs",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations",
+			body:   gin.H{"title": "weekend", "members": []string{member.Username}},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateGroupConversationTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.GroupChangeTxResult{}, database.ErrDirectMessagesClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Add members full",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations/5/members",
+			body:   gin.H{"usernames": []string{member.Username}},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.AddGroupMembersTxParams{ConversationID: group.ID, Actor: admin.Username, Usernames: []string{member.Username}}
+				transaction.EXPECT().AddGroupMembersTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.GroupChangeTxResult{}, database.ErrGroupFull)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Add members not admin",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations/5/members",
+			body:   gin.H{"usernames": []string{member.Username}},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().AddGroupMembersTx(gomock.Any(), gomock.Any()).Times(1).Return(database.GroupChangeTxResult{}, database.ErrNotGroupAdmin)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Remove member OK",
+			method: http.MethodDelete,
+			url:    "/api/v1/conversations/5/members/" + member.Username,
+			pushed: true,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.GroupMemberTxParams{ConversationID: group.ID, Actor: admin.Username, Username: member.Username}
+				transaction.EXPECT().RemoveGroupMemberTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(changed(admin.Username+" removed "+member.Username, admin.Username, member.Username), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Remove yourself",
+			method: http.MethodDelete,
+			url:    "/api/v1/conversations/5/members/" + admin.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().RemoveGroupMemberTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Leave",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations/5/leave",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.GroupMemberTxParams{ConversationID: group.ID, Actor: admin.Username, Username: admin.Username}
+				transaction.EXPECT().LeaveGroupTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(changed(admin.Username+" left", admin.Username), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Leave a one-to-one conversation",
+			method: http.MethodPost,
+			url:    "/api/v1/conversations/5/leave",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().LeaveGroupTx(gomock.Any(), gomock.Any()).Times(1).Return(database.GroupChangeTxResult{}, database.ErrNotGroupConversation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Rename to the same title",
+			method: http.MethodPut,
+			url:    "/api/v1/conversations/5",
+			body:   gin.H{"title": "weekend"},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.RenameGroupTxParams{ConversationID: group.ID, Actor: admin.Username, Title: "weekend"}
+				transaction.EXPECT().RenameGroupTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.GroupChangeTxResult{}, database.ErrNoGroupChange)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "Demote the last admin",
+			method: http.MethodPut,
+			url:    "/api/v1/conversations/5/members/" + admin.Username + "/role",
+			body:   gin.H{"role": database.GroupMember},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.SetGroupRoleTxParams{ConversationID: group.ID, Actor: admin.Username, Username: admin.Username, Role: database.GroupMember}
+				transaction.EXPECT().SetGroupRoleTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.GroupChangeTxResult{}, database.ErrLastGroupAdmin)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Unknown role",
+			method: http.MethodPut,
+			url:    "/api/v1/conversations/5/members/" + member.Username + "/role",
+			body:   gin.H{"role": "owner"},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SetGroupRoleTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		sub := server.hub.Subscribe([]string{dmsTopic(member.Username)}, 0)
+		recorder := httptest.NewRecorder()
+
+		var data []byte
+		if testcase.body != nil {
+			var err error
+			data, err = json.Marshal(testcase.body)
+			require.NoError(t, err)
+		}
+		req, err := http.NewRequest(testcase.method, testcase.url, bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, admin.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+
+		select {
+		case event := <-sub.Events():
+			require.True(t, testcase.pushed, testcase.name)
+			require.Equal(t, eventMessage, event.Type)
+			require.Equal(t, database.MessageSystem, event.Data.(messageResponse).Kind)
+		default:
+			require.False(t, testcase.pushed, testcase.name)
+		}
+		sub.Close()
+	}
+}
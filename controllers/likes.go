@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// userSummaryResponse is the public part of a user shown in lists
+type userSummaryResponse struct {
+	Username       string `json:"username"`
+	Name           string `json:"name"`
+	FollowersCount int32  `json:"followers_count"`
+	FollowingCount int32  `json:"following_count"`
+}
+
+type usersPageResponse struct {
+	Users      []userSummaryResponse `json:"users"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	PrevCursor string                `json:"prev_cursor,omitempty"`
+}
+
+func (s *Server) GetTweetLikes(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	list := fmt.Sprintf("likes:tweet:%v", uri.ID)
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	_, _, status, err := s.visibleTweet(c, uri.ID, callerUsername(c))
+	if err != nil {
+		c.JSON(status, ErrResponse(err.Error()))
+		return
+	}
+
+	likers, err := s.transaction.ListTweetLikers(c, database.ListTweetLikersParams{
+		TweetID:  uri.ID,
+		AfterID:  p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse:  p.Reverse,
+		PageSize: p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := usersPageResponse{Users: []userSummaryResponse{}}
+	keys := make([]int64, len(likers))
+	for i, liker := range likers {
+		resp.Users = append(resp.Users, userSummaryResponse{
+			Username:       liker.Username,
+			Name:           liker.Name,
+			FollowersCount: liker.FollowersCount.Int32,
+			FollowingCount: liker.FollowingCount.Int32,
+		})
+		keys[i] = liker.LikeID
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) GetUserLikes(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	list := "likes:user:" + uri.Username
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	user, err := s.transaction.GetUser(c, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	//likes are hidden along with the tweets of the user
+	ok, err := s.canView(c, user, callerUsername(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, ErrResponse(errTweetsHidden.Error()))
+		return
+	}
+
+	rows, err := s.transaction.ListLikedTweets(c, database.ListLikedTweetsParams{
+		Username: user.Username,
+		AfterID:  p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse:  p.Reverse,
+		PageSize: p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	tweets := make([]database.Tweets, len(rows))
+	keys := make([]int64, len(rows))
+	for i, row := range rows {
+		tweets[i] = database.Tweets{
+			ID:          row.ID,
+			Tweet:       row.Tweet,
+			Username:    row.Username,
+			Likes:       row.Likes,
+			CreatedAt:   row.CreatedAt,
+			EditedAt:    row.EditedAt,
+			InReplyToID: row.InReplyToID,
+			RetweetOfID: row.RetweetOfID,
+		}
+		keys[i] = row.LikeID
+	}
+	//the keys stay as they are so the cursors don't depend on what's hidden
+	tweets, err = s.visibleTweets(c, tweets, callerUsername(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := timelineResponse{}
+	resp.Tweets, err = s.tweetResponses(c, tweets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTweetLikes(t *testing.T) {
+	user, _ := randomUser(t)
+	liker, _ := randomUser(t)
+	tweet := randomTweets(user)
+	author, _ := randomUser(t)
+	author.Protected = true
+	protectedTweet := randomTweets(author)
+
+	testcases := []struct {
+		name          string
+		query         string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?limit=1",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListTweetLikersParams{TweetID: tweet.ID, BeforeID: math.MaxInt64, PageSize: 1}
+				rows := []database.ListTweetLikersRow{{
+					LikeID:         9,
+					Username:       liker.Username,
+					Name:           liker.Name,
+					FollowersCount: sql.NullInt32{Int32: 4, Valid: true},
+				}}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				transaction.EXPECT().ListTweetLikers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "email")
+
+				var resp usersPageResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Users, 1)
+				require.Equal(t, liker.Username, resp.Users[0].Username)
+				require.Equal(t, int32(4), resp.Users[0].FollowersCount)
+				require.Equal(t, pageCursor{List: fmt.Sprintf("likes:tweet:%v", tweet.ID), ID: 9}, cursorPayload(t, resp.NextCursor))
+			},
+		},
+		{
+			name:  "Tweet not found",
+			query: "",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+				transaction.EXPECT().ListTweetLikers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "Protected tweet not followed",
+			query: "",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(protectedTweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().ListTweetLikers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "Bad cursor",
+			query: "?cursor=abc",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		url := fmt.Sprintf("/tweets/%v/likes%v", tweet.ID, testcase.query)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestGetUserLikes(t *testing.T) {
+	user, _ := randomUser(t)
+	author, _ := randomUser(t)
+	tweet := randomTweets(author)
+
+	testcases := []struct {
+		name          string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListLikedTweetsParams{Username: user.Username, BeforeID: math.MaxInt64, PageSize: defaultPageLimit}
+				rows := []database.ListLikedTweetsRow{{
+					LikeID:    3,
+					ID:        tweet.ID,
+					Tweet:     tweet.Tweet,
+					Username:  tweet.Username,
+					Likes:     sql.NullInt32{Int32: 1, Valid: true},
+					CreatedAt: tweet.CreatedAt,
+				}}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				transaction.EXPECT().ListLikedTweets(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{tweet.ID})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 1)
+				require.Equal(t, author.Username, resp.Tweets[0].Username)
+				require.Equal(t, int32(1), resp.Tweets[0].Likes)
+				require.Empty(t, resp.NextCursor)
+			},
+		},
+		{
+			name: "Tweets of blocking authors left out",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				rows := []database.ListLikedTweetsRow{{LikeID: 3, ID: tweet.ID, Username: tweet.Username}}
+				blockArg := database.IsBlockedParams{BlockerUsername: author.Username, BlockedUsername: user.Username}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				transaction.EXPECT().ListLikedTweets(gomock.Any(), gomock.Any()).Times(1).Return(rows, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(blockArg)).Times(1).Return(true, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Empty(t, resp.Tweets)
+			},
+		},
+		{
+			name: "User not found",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(database.Users{}, sql.ErrNoRows)
+				transaction.EXPECT().ListLikedTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		url := fmt.Sprintf("/users/%v/likes", user.Username)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
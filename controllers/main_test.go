@@ -20,6 +20,16 @@ func NewTestServer(t *testing.T, db database.Transaction) *Server {
 	config := util.Config{
 		Access_Token: util.GetRandomString(32),
 		Token_Duration: time.Minute,
+		Media_Path: t.TempDir(),
+		Max_Media_Size: 1 << 20,
+		Tweet_Edit_Window: time.Hour,
+		For_You_Window: 48 * time.Hour,
+		Stream_Buffer_Size: 16,
+		Stream_History_Size: 64,
+		Stream_Heartbeat_Interval: time.Second,
+		Webhook_Timeout: time.Second,
+		Prekey_Bundle_Limit: 3,
+		Prekey_Bundle_Window: time.Minute,
 	}
 
 	server, err := NewServer(config, db)
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/media"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/storage"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type mediaResponse struct {
+	ID           int64  `json:"id"`
+	MimeType     string `json:"mime_type"`
+	Width        int32  `json:"width"`
+	Height       int32  `json:"height"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+func newMediaResponse(m database.Media) mediaResponse {
+	resp := mediaResponse{
+		ID:       m.ID,
+		MimeType: m.MimeType,
+		Width:    m.Width,
+		Height:   m.Height,
+		URL:      fmt.Sprintf("/media/%v", m.ID),
+	}
+	if m.ThumbnailKey != "" {
+		resp.ThumbnailURL = fmt.Sprintf("/media/%v/thumbnail", m.ID)
+	}
+	return resp
+}
+
+func (s *Server) UploadMedia(c *gin.Context) {
+	//leave some room for the multipart envelope, the file itself is checked below
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, s.config.Max_Media_Size+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if fileHeader.Size > s.config.Max_Media_Size {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrResponse(fmt.Sprintf("file is larger than %v bytes", s.config.Max_Media_Size)))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	processed, err := media.Process(data)
+	if err != nil {
+		switch err {
+		case media.ErrUnsupportedType:
+			c.JSON(http.StatusUnsupportedMediaType, ErrResponse(err.Error()))
+		case media.ErrInvalidImage:
+			c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	name := uuid.NewString()
+	storageKey := name + processed.Extension
+	if err = s.blobStore.Put(c, storageKey, bytes.NewReader(processed.Data)); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	var thumbnailKey string
+	if len(processed.Thumbnail) > 0 {
+		thumbnailKey = name + "_thumb.jpg"
+		if err = s.blobStore.Put(c, thumbnailKey, bytes.NewReader(processed.Thumbnail)); err != nil {
+			s.blobStore.Delete(c, storageKey)
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+			return
+		}
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	created, err := s.transaction.CreateMedia(c, database.CreateMediaParams{
+		Username:     authHeader.Username,
+		MimeType:     processed.MimeType,
+		SizeBytes:    int64(len(processed.Data)),
+		Width:        int32(processed.Width),
+		Height:       int32(processed.Height),
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+	})
+	if err != nil {
+		s.blobStore.Delete(c, storageKey)
+		if thumbnailKey != "" {
+			s.blobStore.Delete(c, thumbnailKey)
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newMediaResponse(created))
+}
+
+func (s *Server) GetMediaFile(c *gin.Context) {
+	s.serveMedia(c, false)
+}
+
+func (s *Server) GetMediaThumbnail(c *gin.Context) {
+	s.serveMedia(c, true)
+}
+
+func (s *Server) serveMedia(c *gin.Context, thumbnail bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	m, err := s.transaction.GetMedia(c, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	//media goes as far as its tweet does, and only to its uploader until it's attached
+	cacheControl := "public, max-age=31536000, immutable"
+	viewer := callerUsername(c)
+	if !m.TweetID.Valid {
+		if viewer != m.Username {
+			c.JSON(http.StatusNotFound, ErrResponse(sql.ErrNoRows.Error()))
+			return
+		}
+		cacheControl = "private, no-cache"
+	} else {
+		_, author, status, err := s.visibleTweet(c, m.TweetID.Int64, viewer)
+		if err != nil {
+			c.JSON(status, ErrResponse(err.Error()))
+			return
+		}
+		if author.Protected {
+			cacheControl = "private, no-cache"
+		}
+	}
+
+	key, mimeType, size := m.StorageKey, m.MimeType, m.SizeBytes
+	if thumbnail {
+		if m.ThumbnailKey == "" {
+			c.JSON(http.StatusNotFound, ErrResponse("media has no thumbnail"))
+			return
+		}
+		key, mimeType, size = m.ThumbnailKey, media.ThumbnailMime, -1
+	}
+
+	blob, err := s.blobStore.Get(c, key)
+	if err != nil {
+		if err == storage.ErrBlobNotFound {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	defer blob.Close()
+
+	c.DataFromReader(http.StatusOK, size, mimeType, blob, map[string]string{
+		"Cache-Control":          cacheControl,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
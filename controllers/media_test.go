@@ -0,0 +1,338 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func pngFile(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+	return buf.Bytes()
+}
+
+func multipartBody(t *testing.T, field string, data []byte) (io.Reader, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "upload")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestUploadMedia(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testcases := []struct {
+		name          string
+		field         string
+		data          []byte
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			field: "file",
+			data:  pngFile(t),
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg database.CreateMediaParams) (database.Media, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, "image/png", arg.MimeType)
+						require.Equal(t, int32(40), arg.Width)
+						require.Equal(t, int32(20), arg.Height)
+						require.NotEmpty(t, arg.ThumbnailKey)
+						return database.Media{ID: 1, MimeType: arg.MimeType, ThumbnailKey: arg.ThumbnailKey}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp mediaResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, "/media/1", resp.URL)
+				require.Equal(t, "/media/1/thumbnail", resp.ThumbnailURL)
+			},
+		},
+		{
+			name:  "No file",
+			field: "other",
+			data:  pngFile(t),
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Unsupported type",
+			field: "file",
+			data:  []byte("just some text pretending to be an image"),
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name:  "Too large",
+			field: "file",
+			data:  append(pngFile(t), make([]byte, 1<<20)...),
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:  "Internal server error",
+			field: "file",
+			data:  pngFile(t),
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Times(1).Return(database.Media{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "Unauthorized",
+			field: "file",
+			data:  pngFile(t),
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		body, contentType := multipartBody(t, testcase.field, testcase.data)
+		req, err := http.NewRequest(http.MethodPost, "/media", body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestGetMediaFile(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	server := NewTestServer(t, transaction)
+
+	data := pngFile(t)
+	require.NoError(t, server.blobStore.Put(context.Background(), "abc.png", bytes.NewReader(data)))
+
+	user, _ := randomUser(t)
+	tweet := randomTweets(user)
+	stored := database.Media{ID: 3, Username: user.Username, TweetID: sql.NullInt64{Int64: tweet.ID, Valid: true}, MimeType: "image/png", SizeBytes: int64(len(data)), StorageKey: "abc.png"}
+	transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(3))).Times(2).Return(stored, nil)
+	transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(4))).Times(1).Return(database.Media{}, sql.ErrNoRows)
+	transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(2).Return(tweet, nil)
+	transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(user, nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/media/3", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+	require.Equal(t, fmt.Sprint(len(data)), recorder.Header().Get("Content-Length"))
+	require.Equal(t, "public, max-age=31536000, immutable", recorder.Header().Get("Cache-Control"))
+	require.Equal(t, data, recorder.Body.Bytes())
+
+	//stored without a thumbnail
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodGet, "/media/3/thumbnail", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodGet, "/media/4", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGetMediaFileVisibility(t *testing.T) {
+	data := []byte("image bytes")
+	owner, _ := randomUser(t)
+	viewer, _ := randomUser(t)
+	protectedOwner := owner
+	protectedOwner.Protected = true
+	tweet := randomTweets(owner)
+
+	attached := database.Media{ID: 3, Username: owner.Username, TweetID: sql.NullInt64{Int64: tweet.ID, Valid: true}, MimeType: "image/png", SizeBytes: int64(len(data)), StorageKey: "abc.png"}
+	unattached := database.Media{ID: 3, Username: owner.Username, MimeType: "image/png", SizeBytes: int64(len(data)), StorageKey: "abc.png"}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Protected author, anonymous",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(3))).Times(1).Return(attached, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(protectedOwner, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Protected author, follower",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, viewer.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(3))).Times(1).Return(attached, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(protectedOwner, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "private, no-cache", recorder.Header().Get("Cache-Control"))
+				require.Equal(t, data, recorder.Body.Bytes())
+			},
+		},
+		{
+			name: "Blocked viewer",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, viewer.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(3))).Times(1).Return(attached, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(owner, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Unattached, uploader",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, owner.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(3))).Times(1).Return(unattached, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "private, no-cache", recorder.Header().Get("Cache-Control"))
+			},
+		},
+		{
+			name: "Unattached, someone else",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, viewer.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(3))).Times(1).Return(unattached, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "Unattached, anonymous",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMedia(gomock.Any(), gomock.Eq(int64(3))).Times(1).Return(unattached, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testcase := testCases[i]
+
+		t.Run(testcase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			transaction := dbmock.NewMockTransaction(controller)
+			testcase.buildStubs(transaction)
+
+			server := NewTestServer(t, transaction)
+			require.NoError(t, server.blobStore.Put(context.Background(), "abc.png", bytes.NewReader(data)))
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, "/media/3", nil)
+			require.NoError(t, err)
+
+			testcase.setupAuth(t, req, server.paseto)
+			server.router.ServeHTTP(recorder, req)
+			testcase.checkResponse(t, recorder)
+		})
+	}
+}
@@ -39,4 +39,40 @@ func AuthMiddleware(tokenMaker token.Paseto) gin.HandlerFunc {
 
 		c.Set(authorizationPayloadKey, payload)
 	}
-}
\ No newline at end of file
+}
+
+// callerUsername is empty on routes outside of the auth middleware
+func callerUsername(c *gin.Context) string {
+	payload, ok := c.Get(authorizationPayloadKey)
+	if !ok {
+		return ""
+	}
+	return payload.(*token.Payload).Username
+}
+
+// OptionalAuthMiddleware authenticates the caller when a token is sent and lets
+// anonymous requests through, for public routes whose answer depends on who asks
+func OptionalAuthMiddleware(tokenMaker token.Paseto) gin.HandlerFunc {
+	authenticate := AuthMiddleware(tokenMaker)
+	return func(c *gin.Context) {
+		if len(c.GetHeader(authorizationHeaderKey)) == 0 {
+			return
+		}
+		authenticate(c)
+	}
+}
+
+
+// DeprecationMiddleware marks the unversioned routes kept as aliases of /api/v1.
+// successor is the route that replaces them, empty when it's the same path under
+// /api/v1.
+func DeprecationMiddleware(successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link := successor
+		if link == "" {
+			link = apiVersionPrefix + c.Request.URL.Path
+		}
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("<%v>; rel=\"successor-version\"", link))
+	}
+}
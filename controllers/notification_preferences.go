@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+const quietHoursLayout = "15:04"
+
+// QuietHours are local times like "22:00" and "07:00", the end may be past midnight
+type QuietHours struct {
+	Start string `json:"start" binding:"required"`
+	End   string `json:"end" binding:"required"`
+}
+
+// NotificationPreferencesRequest replaces every preference, a null quiet_hours turns
+// them off. TimeZone is an IANA name like "Asia/Jakarta".
+type NotificationPreferencesRequest struct {
+	Likes         *bool       `json:"likes" binding:"required"`
+	Follows       *bool       `json:"follows" binding:"required"`
+	Mentions      *bool       `json:"mentions" binding:"required"`
+	Replies       *bool       `json:"replies" binding:"required"`
+	OnlyFollowing *bool       `json:"only_following" binding:"required"`
+	QuietHours    *QuietHours `json:"quiet_hours"`
+	TimeZone      string      `json:"time_zone" binding:"required,max=64"`
+}
+
+type notificationPreferencesResponse struct {
+	Likes         bool        `json:"likes"`
+	Follows       bool        `json:"follows"`
+	Mentions      bool        `json:"mentions"`
+	Replies       bool        `json:"replies"`
+	OnlyFollowing bool        `json:"only_following"`
+	QuietHours    *QuietHours `json:"quiet_hours"`
+	TimeZone      string      `json:"time_zone"`
+}
+
+func newNotificationPreferencesResponse(prefs database.NotificationPreferences) notificationPreferencesResponse {
+	resp := notificationPreferencesResponse{
+		Likes:         prefs.Likes,
+		Follows:       prefs.Follows,
+		Mentions:      prefs.Mentions,
+		Replies:       prefs.Replies,
+		OnlyFollowing: prefs.OnlyFollowing,
+		TimeZone:      prefs.TimeZone,
+	}
+	if prefs.QuietHoursStart.Valid && prefs.QuietHoursEnd.Valid {
+		resp.QuietHours = &QuietHours{
+			Start: formatMinuteOfDay(prefs.QuietHoursStart.Int32),
+			End:   formatMinuteOfDay(prefs.QuietHoursEnd.Int32),
+		}
+	}
+	return resp
+}
+
+func formatMinuteOfDay(minute int32) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+func parseMinuteOfDay(value string) (sql.NullInt32, error) {
+	t, err := time.Parse(quietHoursLayout, value)
+	if err != nil {
+		return sql.NullInt32{}, fmt.Errorf("quiet hours must look like 22:00, got %v", value)
+	}
+	return sql.NullInt32{Int32: int32(t.Hour()*60 + t.Minute()), Valid: true}, nil
+}
+
+func (s *Server) GetNotificationPreferences(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	prefs, err := database.LoadNotificationPreferences(c, s.transaction, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationPreferencesResponse(prefs))
+}
+
+// UpdateNotificationPreferences applies to notifications from now on, the ones already
+// in the inbox stay
+func (s *Server) UpdateNotificationPreferences(c *gin.Context) {
+	var req NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if _, err := time.LoadLocation(req.TimeZone); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(fmt.Sprintf("unknown time zone : %v", req.TimeZone)))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := database.UpsertNotificationPreferencesParams{
+		Username:      authHeader.Username,
+		Likes:         *req.Likes,
+		Follows:       *req.Follows,
+		Mentions:      *req.Mentions,
+		Replies:       *req.Replies,
+		OnlyFollowing: *req.OnlyFollowing,
+		TimeZone:      req.TimeZone,
+	}
+	if req.QuietHours != nil {
+		var err error
+		if arg.QuietHoursStart, err = parseMinuteOfDay(req.QuietHours.Start); err != nil {
+			c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+			return
+		}
+		if arg.QuietHoursEnd, err = parseMinuteOfDay(req.QuietHours.End); err != nil {
+			c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+			return
+		}
+		if arg.QuietHoursStart.Int32 == arg.QuietHoursEnd.Int32 {
+			c.JSON(http.StatusBadRequest, ErrResponse("quiet hours can't start and end at the same time"))
+			return
+		}
+	}
+
+	prefs, err := s.transaction.UpsertNotificationPreferences(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationPreferencesResponse(prefs))
+}
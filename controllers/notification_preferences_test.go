@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	user, _ := randomUser(t)
+
+	saved := database.NotificationPreferences{
+		Username:        user.Username,
+		Likes:           false,
+		Follows:         true,
+		Mentions:        true,
+		Replies:         true,
+		OnlyFollowing:   true,
+		QuietHoursStart: sql.NullInt32{Int32: 22 * 60, Valid: true},
+		QuietHoursEnd:   sql.NullInt32{Int32: 7*60 + 30, Valid: true},
+		TimeZone:        "Asia/Jakarta",
+	}
+
+	testcases := []struct {
+		name          string
+		method        string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Get defaults",
+			method: http.MethodGet,
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(database.NotificationPreferences{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp notificationPreferencesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, newNotificationPreferencesResponse(database.DefaultNotificationPreferences(user.Username)), resp)
+				require.Nil(t, resp.QuietHours)
+			},
+		},
+		{
+			name:   "Update OK",
+			method: http.MethodPut,
+			body: gin.H{
+				"likes":          false,
+				"follows":        true,
+				"mentions":       true,
+				"replies":        true,
+				"only_following": true,
+				"quiet_hours":    gin.H{"start": "22:00", "end": "07:30"},
+				"time_zone":      "Asia/Jakarta",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.UpsertNotificationPreferencesParams{
+					Username:        user.Username,
+					Likes:           false,
+					Follows:         true,
+					Mentions:        true,
+					Replies:         true,
+					OnlyFollowing:   true,
+					QuietHoursStart: saved.QuietHoursStart,
+					QuietHoursEnd:   saved.QuietHoursEnd,
+					TimeZone:        "Asia/Jakarta",
+				}
+				transaction.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Eq(arg)).Times(1).Return(saved, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp notificationPreferencesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, &QuietHours{Start: "22:00", End: "07:30"}, resp.QuietHours)
+				require.False(t, resp.Likes)
+			},
+		},
+		{
+			name:   "Update unknown time zone",
+			method: http.MethodPut,
+			body: gin.H{
+				"likes": true, "follows": true, "mentions": true, "replies": true, "only_following": false,
+				"time_zone": "Mars/Olympus_Mons",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Update bad quiet hours",
+			method: http.MethodPut,
+			body: gin.H{
+				"likes": true, "follows": true, "mentions": true, "replies": true, "only_following": false,
+				"quiet_hours": gin.H{"start": "10pm", "end": "07:00"},
+				"time_zone":   "UTC",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Update empty quiet hours",
+			method: http.MethodPut,
+			body: gin.H{
+				"likes": true, "follows": true, "mentions": true, "replies": true, "only_following": false,
+				"quiet_hours": gin.H{"start": "07:00", "end": "07:00"},
+				"time_zone":   "UTC",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Update missing toggle",
+			method: http.MethodPut,
+			body:   gin.H{"likes": true, "time_zone": "UTC"},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Unauthorized",
+			method: http.MethodGet,
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(testcase.method, "/api/v1/notifications/preferences", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestPushNotification(t *testing.T) {
+	user, _ := randomUser(t)
+	actor, _ := randomUser(t)
+
+	//quiet hours around now, whatever time the test runs
+	now := time.Now().UTC()
+	minute := int32(now.Hour()*60 + now.Minute())
+	quiet := database.DefaultNotificationPreferences(user.Username)
+	quiet.QuietHoursStart = sql.NullInt32{Int32: (minute + 1440 - 60) % 1440, Valid: true}
+	quiet.QuietHoursEnd = sql.NullInt32{Int32: (minute + 60) % 1440, Valid: true}
+
+	followsOff := database.DefaultNotificationPreferences(user.Username)
+	followsOff.Follows = false
+
+	onlyFollowing := database.DefaultNotificationPreferences(user.Username)
+	onlyFollowing.OnlyFollowing = true
+
+	testcases := []struct {
+		name       string
+		buildStubs func(transaction *dbmock.MockTransaction)
+		delivered  bool
+	}{
+		{
+			name: "Defaults",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(database.NotificationPreferences{}, sql.ErrNoRows)
+			},
+			delivered: true,
+		},
+		{
+			name: "Quiet hours",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(quiet, nil)
+			},
+		},
+		{
+			name: "Type turned off",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(followsOff, nil)
+			},
+		},
+		{
+			name: "Only following, not followed",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.GetRelationsParams{FollowerUsername: user.Username, FollowedUsername: actor.Username}
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(onlyFollowing, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "Only following, followed",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(onlyFollowing, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, nil)
+			},
+			delivered: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		sub := server.hub.Subscribe([]string{notificationsTopic(user.Username)}, 0)
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		pushed := server.pushNotification(c, user.Username, actor.Username, database.NotificationFollow, 0)
+		require.Equal(t, testcase.delivered, pushed, testcase.name)
+
+		select {
+		case event := <-sub.Events():
+			require.True(t, testcase.delivered, testcase.name)
+			require.Equal(t, eventNotification, event.Type)
+			require.Equal(t, notificationEvent{Type: database.NotificationFollow, Actor: actor.Username}, event.Data)
+		default:
+			require.False(t, testcase.delivered, testcase.name)
+		}
+		sub.Close()
+	}
+}
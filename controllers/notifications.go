@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+// notificationActorLimit is how many actors of a group are named, the rest are counted
+const notificationActorLimit = 3
+
+// notificationsTopic carries the notifications pushed to a user in real time
+func notificationsTopic(username string) string { return "notifications:" + username }
+
+// notificationEvent is what's pushed on notificationsTopic
+type notificationEvent struct {
+	Type    string `json:"type"`
+	Actor   string `json:"actor"`
+	TweetID *int64 `json:"tweet_id,omitempty"`
+}
+
+type notificationURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// notificationResponse is a group of notifications, like the likes on a tweet.
+// Actors are the latest ones, ActorCount counts all of them.
+type notificationResponse struct {
+	ID         int64     `json:"id"`
+	Type       string    `json:"type"`
+	TweetID    *int64    `json:"tweet_id,omitempty"`
+	Actors     []string  `json:"actors"`
+	ActorCount int64     `json:"actor_count"`
+	Summary    string    `json:"summary"`
+	Read       bool      `json:"read"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type notificationsResponse struct {
+	Notifications []notificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unread_count"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+	PrevCursor    string                 `json:"prev_cursor,omitempty"`
+}
+
+// notificationSummary reads like "alice and 3 others liked your tweet"
+func notificationSummary(notificationType string, actors []string, count int64) string {
+	var action string
+	switch notificationType {
+	case database.NotificationFollow:
+		action = "followed you"
+	case database.NotificationLike:
+		action = "liked your tweet"
+	case database.NotificationMention:
+		action = "mentioned you"
+	default:
+		action = notificationType
+	}
+
+	switch {
+	case len(actors) == 0:
+		return fmt.Sprintf("%v people %v", count, action)
+	case count == 1:
+		return fmt.Sprintf("%v %v", actors[0], action)
+	case count == 2 && len(actors) == 2:
+		return fmt.Sprintf("%v and %v %v", actors[0], actors[1], action)
+	case count == 2:
+		return fmt.Sprintf("%v and 1 other %v", actors[0], action)
+	default:
+		return fmt.Sprintf("%v and %v others %v", actors[0], count-1, action)
+	}
+}
+
+// notificationResponses names the latest actors of each notification
+func (s *Server) notificationResponses(c *gin.Context, notifications []database.Notifications) ([]notificationResponse, error) {
+	res := make([]notificationResponse, len(notifications))
+	if len(notifications) == 0 {
+		return res, nil
+	}
+
+	ids := make([]int64, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+	}
+	rows, err := s.transaction.ListNotificationActors(c, database.ListNotificationActorsParams{
+		NotificationIds: ids,
+		ActorLimit:      notificationActorLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	actors := map[int64][]string{}
+	counts := map[int64]int64{}
+	for _, row := range rows {
+		actors[row.NotificationID] = append(actors[row.NotificationID], row.ActorUsername)
+		counts[row.NotificationID] = row.Total
+	}
+
+	for i, notification := range notifications {
+		res[i] = notificationResponse{
+			ID:         notification.ID,
+			Type:       notification.Type,
+			Actors:     actors[notification.ID],
+			ActorCount: counts[notification.ID],
+			Read:       notification.ReadAt.Valid,
+			CreatedAt:  notification.CreatedAt,
+			UpdatedAt:  notification.UpdatedAt,
+		}
+		if res[i].Actors == nil {
+			res[i].Actors = []string{}
+		}
+		if notification.TweetID.Valid {
+			tweetID := notification.TweetID.Int64
+			res[i].TweetID = &tweetID
+		}
+		res[i].Summary = notificationSummary(notification.Type, res[i].Actors, res[i].ActorCount)
+	}
+	return res, nil
+}
+
+// ListNotifications returns the notifications with the latest activity first, a group
+// moves back to the top when someone joins it
+func (s *Server) ListNotifications(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	list := "notifications:" + authHeader.Username
+
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	notifications, err := s.transaction.ListNotifications(c, database.ListNotificationsParams{
+		Username: authHeader.Username,
+		AfterID:  p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse:  p.Reverse,
+		PageSize: p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := notificationsResponse{}
+	resp.Notifications, err = s.notificationResponses(c, notifications)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	resp.UnreadCount, err = s.transaction.CountUnreadNotifications(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	keys := make([]int64, len(notifications))
+	for i, notification := range notifications {
+		keys[i] = notification.Seq
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) GetUnreadNotificationCount(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	unread, err := s.transaction.CountUnreadNotifications(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": unread})
+}
+
+func (s *Server) MarkNotificationRead(c *gin.Context) {
+	var uri notificationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	//someone else's notification is reported missing
+	notification, err := s.transaction.MarkNotificationRead(c, database.MarkNotificationReadParams{
+		ID:       uri.ID,
+		Username: authHeader.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp, err := s.notificationResponses(c, []database.Notifications{notification})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp[0])
+}
+
+func (s *Server) MarkAllNotificationsRead(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	marked, err := s.transaction.MarkAllNotificationsRead(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("%v notifications have succesfully been marked as read", marked),
+	})
+}
+
+// pushNotification delivers a notification in real time unless the user's preferences
+// turn it down or it's their quiet hours, and reports whether it did. It's best effort,
+// the notification is in their inbox either way.
+func (s *Server) pushNotification(c context.Context, username, actor, notificationType string, tweetID int64) bool {
+	prefs, err := database.LoadNotificationPreferences(c, s.transaction, username)
+	if err != nil || prefs.InQuietHours(time.Now()) {
+		return false
+	}
+	accepted, err := database.AcceptsNotification(c, s.transaction, prefs, notificationType, actor)
+	if err != nil || !accepted {
+		return false
+	}
+
+	event := notificationEvent{Type: notificationType, Actor: actor}
+	if tweetID != 0 {
+		event.TweetID = &tweetID
+	}
+	s.hub.Publish(notificationsTopic(username), eventNotification, event)
+	return true
+}
+
+// pushTweetNotifications pushes the replies and mentions a tweet notified
+func (s *Server) pushTweetNotifications(c context.Context, created database.CreateTweetTxResult) {
+	for _, notified := range created.Notified {
+		tweetID := created.Tweet.ID
+		if notified.Type == database.NotificationReply {
+			tweetID = created.Tweet.InReplyToID.Int64
+		}
+		s.pushNotification(c, notified.Username, created.Tweet.Username, notified.Type, tweetID)
+	}
+}
@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationSummary(t *testing.T) {
+	testcases := []struct {
+		notificationType string
+		actors           []string
+		count            int64
+		want             string
+	}{
+		{database.NotificationFollow, []string{"alice"}, 1, "alice followed you"},
+		{database.NotificationLike, []string{"alice", "bob"}, 2, "alice and bob liked your tweet"},
+		{database.NotificationLike, []string{"alice", "bob", "carol"}, 4, "alice and 3 others liked your tweet"},
+		{database.NotificationMention, []string{"alice"}, 2, "alice and 1 other mentioned you"},
+	}
+
+	for _, testcase := range testcases {
+		require.Equal(t, testcase.want, notificationSummary(testcase.notificationType, testcase.actors, testcase.count))
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	user, _ := randomUser(t)
+
+	likes := database.Notifications{
+		ID:        3,
+		Username:  user.Username,
+		Type:      database.NotificationLike,
+		GroupKey:  "like:1",
+		TweetID:   sql.NullInt64{Int64: 1, Valid: true},
+		Seq:       9,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	actors := []database.ListNotificationActorsRow{
+		{NotificationID: likes.ID, ActorUsername: "alice", Total: 5},
+		{NotificationID: likes.ID, ActorUsername: "bob", Total: 5},
+		{NotificationID: likes.ID, ActorUsername: "carol", Total: 5},
+	}
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		cursor        *pageCursor
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List first page",
+			method: http.MethodGet,
+			url:    "/api/v1/notifications?limit=1",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListNotificationsParams{Username: user.Username, BeforeID: math.MaxInt64, PageSize: 1}
+				transaction.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.Notifications{likes}, nil)
+				actorsArg := database.ListNotificationActorsParams{NotificationIds: []int64{likes.ID}, ActorLimit: notificationActorLimit}
+				transaction.EXPECT().ListNotificationActors(gomock.Any(), gomock.Eq(actorsArg)).Times(1).Return(actors, nil)
+				transaction.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(4), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp notificationsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Notifications, 1)
+				require.Equal(t, []string{"alice", "bob", "carol"}, resp.Notifications[0].Actors)
+				require.Equal(t, int64(5), resp.Notifications[0].ActorCount)
+				require.Equal(t, "alice and 4 others liked your tweet", resp.Notifications[0].Summary)
+				require.Equal(t, int64(1), *resp.Notifications[0].TweetID)
+				require.False(t, resp.Notifications[0].Read)
+				require.Equal(t, int64(4), resp.UnreadCount)
+				require.Equal(t, pageCursor{List: "notifications:" + user.Username, ID: 9}, cursorPayload(t, resp.NextCursor))
+				require.Empty(t, resp.PrevCursor)
+			},
+		},
+		{
+			name:   "List last page",
+			method: http.MethodGet,
+			url:    "/api/v1/notifications",
+			cursor: &pageCursor{List: "notifications:" + user.Username, ID: 9},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListNotificationsParams{Username: user.Username, BeforeID: 9, PageSize: defaultPageLimit}
+				transaction.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.Notifications{}, nil)
+				transaction.EXPECT().ListNotificationActors(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp notificationsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Empty(t, resp.Notifications)
+				require.Empty(t, resp.NextCursor)
+				require.Equal(t, pageCursor{List: "notifications:" + user.Username, ID: 8, Reverse: true}, cursorPayload(t, resp.PrevCursor))
+			},
+		},
+		{
+			name:   "List cursor of another list",
+			method: http.MethodGet,
+			url:    "/api/v1/notifications",
+			cursor: &pageCursor{List: "notifications:someone", ID: 9},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "List internal error",
+			method: http.MethodGet,
+			url:    "/api/v1/notifications",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "Unread count",
+			method: http.MethodGet,
+			url:    "/api/v1/notifications/unread_count",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"unread_count":2}`, recorder.Body.String())
+			},
+		},
+		{
+			name:   "Mark read OK",
+			method: http.MethodPut,
+			url:    "/api/v1/notifications/3/read",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				read := likes
+				read.ReadAt = sql.NullTime{Time: time.Now(), Valid: true}
+				arg := database.MarkNotificationReadParams{ID: likes.ID, Username: user.Username}
+				transaction.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Eq(arg)).Times(1).Return(read, nil)
+				transaction.EXPECT().ListNotificationActors(gomock.Any(), gomock.Any()).Times(1).Return(actors, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp notificationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.True(t, resp.Read)
+			},
+		},
+		{
+			name:   "Mark read not found",
+			method: http.MethodPut,
+			url:    "/api/v1/notifications/3/read",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(1).Return(database.Notifications{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Mark read bad id",
+			method: http.MethodPut,
+			url:    "/api/v1/notifications/0/read",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Mark all read",
+			method: http.MethodPut,
+			url:    "/api/v1/notifications/read",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().MarkAllNotificationsRead(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(4), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Unauthorized",
+			method: http.MethodGet,
+			url:    "/api/v1/notifications",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(testcase.method, withCursor(server, testcase.url, testcase.cursor), nil)
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultPageLimit = 20
+
+var errInvalidCursor = errors.New("cursor is invalid")
+
+// PageRequest is the query of cursor paginated lists. Cursor is the next_cursor or
+// prev_cursor of a previous page, an empty one starts from the newest.
+type PageRequest struct {
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" binding:"omitempty,max=256"`
+}
+
+// pageCursor is the position a cursor resumes from. It's signed so clients can't
+// forge positions, and tied to the list it was issued for. Ranked lists resume
+// after a score as well as an id.
+type pageCursor struct {
+	List    string  `json:"l"`
+	ID      int64   `json:"i"`
+	Reverse bool    `json:"r,omitempty"`
+	Score   float64 `json:"s,omitempty"`
+}
+
+// page is the keyset window of a request: keys strictly between AfterID and BeforeID,
+// the newest first, or the oldest first when walking back with a prev_cursor
+type page struct {
+	AfterID  int64
+	BeforeID int64
+	Reverse  bool
+	Limit    int32
+	// first is set when no cursor was given
+	first bool
+}
+
+func (s *Server) encodeCursor(cursor pageCursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.signCursor(payload))
+}
+
+func (s *Server) decodeCursor(list, raw string) (pageCursor, error) {
+	var cursor pageCursor
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 2 {
+		return cursor, errInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, s.signCursor(payload)) {
+		return cursor, errInvalidCursor
+	}
+
+	if err = json.Unmarshal(payload, &cursor); err != nil || cursor.List != list {
+		return cursor, errInvalidCursor
+	}
+	return cursor, nil
+}
+
+func (s *Server) signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(s.config.Access_Token))
+	mac.Write([]byte("cursor:"))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// bindPage reads the page of list requested in the query string
+func (s *Server) bindPage(c *gin.Context, list string) (page, error) {
+	var req PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return page{}, err
+	}
+	return s.newPage(list, req)
+}
+
+func (s *Server) newPage(list string, req PageRequest) (page, error) {
+	p := page{BeforeID: math.MaxInt64, Limit: req.Limit}
+	if p.Limit == 0 {
+		p.Limit = defaultPageLimit
+	}
+	if req.Cursor == "" {
+		p.first = true
+		return p, nil
+	}
+
+	cursor, err := s.decodeCursor(list, req.Cursor)
+	if err != nil {
+		return p, err
+	}
+	if cursor.Reverse {
+		p.AfterID, p.Reverse = cursor.ID, true
+	} else {
+		p.BeforeID = cursor.ID
+	}
+	return p, nil
+}
+
+// pageCursors returns the cursors around a page whose keys, newest first, are keys
+func (s *Server) pageCursors(list string, p page, keys []int64) (next, prev string) {
+	if len(keys) == 0 {
+		//nothing in this direction, the way back starts where the request did
+		if p.Reverse {
+			return s.encodeCursor(pageCursor{List: list, ID: p.AfterID + 1}), ""
+		}
+		if !p.first {
+			return "", s.encodeCursor(pageCursor{List: list, ID: p.BeforeID - 1, Reverse: true})
+		}
+		return "", ""
+	}
+
+	newest, oldest := keys[0], keys[len(keys)-1]
+	full := len(keys) == int(p.Limit)
+	if !p.Reverse && full || p.Reverse {
+		next = s.encodeCursor(pageCursor{List: list, ID: oldest})
+	}
+	if p.Reverse && full || !p.Reverse && !p.first {
+		prev = s.encodeCursor(pageCursor{List: list, ID: newest, Reverse: true})
+	}
+	return next, prev
+}
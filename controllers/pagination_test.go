@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withCursor adds the cursor, signed by server, to the query of target
+func withCursor(server *Server, target string, cursor *pageCursor) string {
+	if cursor == nil {
+		return target
+	}
+	separator := "?"
+	if strings.Contains(target, "?") {
+		separator = "&"
+	}
+	return target + separator + "cursor=" + url.QueryEscape(server.encodeCursor(*cursor))
+}
+
+// cursorPayload reads the position of a cursor without checking its signature
+func cursorPayload(t *testing.T, raw string) pageCursor {
+	parts := strings.Split(raw, ".")
+	require.Len(t, parts, 2)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+
+	var cursor pageCursor
+	require.NoError(t, json.Unmarshal(payload, &cursor))
+	return cursor
+}
+
+func TestDecodeCursor(t *testing.T) {
+	server := NewTestServer(t, nil)
+	other := NewTestServer(t, nil)
+
+	raw := server.encodeCursor(pageCursor{List: "bookmarks:someone", ID: 42, Reverse: true})
+	cursor, err := server.decodeCursor("bookmarks:someone", raw)
+	require.NoError(t, err)
+	require.Equal(t, pageCursor{List: "bookmarks:someone", ID: 42, Reverse: true}, cursor)
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"l":"bookmarks:someone","i":1}`)) + raw[strings.Index(raw, "."):]
+
+	for _, bad := range []string{"", "42", "a.b.c", "!.!", forged} {
+		_, err = server.decodeCursor("bookmarks:someone", bad)
+		require.ErrorIs(t, err, errInvalidCursor, bad)
+	}
+	//a cursor only works for the list and the server it came from
+	_, err = server.decodeCursor("bookmarks:another", raw)
+	require.ErrorIs(t, err, errInvalidCursor)
+	_, err = other.decodeCursor("bookmarks:someone", raw)
+	require.ErrorIs(t, err, errInvalidCursor)
+}
+
+func TestPageCursors(t *testing.T) {
+	server := NewTestServer(t, nil)
+	const list = "likes:user:someone"
+
+	testcases := []struct {
+		name string
+		page page
+		keys []int64
+		next *pageCursor
+		prev *pageCursor
+	}{
+		{
+			name: "First full page",
+			page: page{BeforeID: math.MaxInt64, Limit: 2, first: true},
+			keys: []int64{9, 8},
+			next: &pageCursor{List: list, ID: 8},
+		},
+		{
+			name: "Only page",
+			page: page{BeforeID: math.MaxInt64, Limit: 3, first: true},
+			keys: []int64{9, 8},
+		},
+		{
+			name: "Middle page",
+			page: page{BeforeID: 8, Limit: 2},
+			keys: []int64{7, 5},
+			next: &pageCursor{List: list, ID: 5},
+			prev: &pageCursor{List: list, ID: 7, Reverse: true},
+		},
+		{
+			name: "Past the end",
+			page: page{BeforeID: 5, Limit: 2},
+			prev: &pageCursor{List: list, ID: 4, Reverse: true},
+		},
+		{
+			name: "Back to the newest",
+			page: page{AfterID: 7, BeforeID: math.MaxInt64, Reverse: true, Limit: 2},
+			keys: []int64{9},
+			next: &pageCursor{List: list, ID: 9},
+		},
+		{
+			name: "Back with more before",
+			page: page{AfterID: 5, BeforeID: math.MaxInt64, Reverse: true, Limit: 2},
+			keys: []int64{8, 7},
+			next: &pageCursor{List: list, ID: 7},
+			prev: &pageCursor{List: list, ID: 8, Reverse: true},
+		},
+		{
+			name: "Back with nothing newer",
+			page: page{AfterID: 9, BeforeID: math.MaxInt64, Reverse: true, Limit: 2},
+			next: &pageCursor{List: list, ID: 10},
+		},
+	}
+
+	for _, testcase := range testcases {
+		next, prev := server.pageCursors(list, testcase.page, testcase.keys)
+		for _, got := range []struct {
+			raw  string
+			want *pageCursor
+		}{{next, testcase.next}, {prev, testcase.prev}} {
+			if got.want == nil {
+				require.Empty(t, got.raw, testcase.name)
+				continue
+			}
+			cursor, err := server.decodeCursor(list, got.raw)
+			require.NoError(t, err, testcase.name)
+			require.Equal(t, *got.want, cursor, testcase.name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+// PinTweet replaces the caller's pinned tweet. There's no need to unpin on delete,
+// the foreign key clears users.pinned_tweet_id when DeleteTweet removes the tweet.
+func (s *Server) PinTweet(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	tweet, err := s.transaction.GetTweet(c, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if tweet.Username != authHeader.Username {
+		c.JSON(http.StatusForbidden, ErrResponse("only your own tweets can be pinned"))
+		return
+	}
+
+	_, err = s.transaction.PinTweet(c, database.PinTweetParams{
+		PinnedTweetID: sql.NullInt64{Int64: tweet.ID, Valid: true},
+		Username:      authHeader.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Tweet with ID %v has succesfully been pinned", tweet.ID),
+	})
+}
+
+func (s *Server) UnpinTweet(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	unpinned, err := s.transaction.UnpinTweet(c, database.UnpinTweetParams{
+		Username:      authHeader.Username,
+		PinnedTweetID: sql.NullInt64{Int64: uri.ID, Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if unpinned == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("tweet %v isn't pinned", uri.ID)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Tweet with ID %v has succesfully been unpinned", uri.ID),
+	})
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinTweet(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	tweet := randomTweets(user)
+
+	testcases := []struct {
+		name          string
+		method        string
+		username      string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Pin OK",
+			method:   http.MethodPost,
+			username: user.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.PinTweetParams{
+					PinnedTweetID: sql.NullInt64{Int64: tweet.ID, Valid: true},
+					Username:      user.Username,
+				}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().PinTweet(gomock.Any(), gomock.Eq(arg)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Pin someone else's tweet",
+			method:   http.MethodPost,
+			username: other.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().PinTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Pin not found",
+			method:   http.MethodPost,
+			username: user.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+				transaction.EXPECT().PinTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Unpin OK",
+			method:   http.MethodDelete,
+			username: user.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.UnpinTweetParams{
+					Username:      user.Username,
+					PinnedTweetID: sql.NullInt64{Int64: tweet.ID, Valid: true},
+				}
+				transaction.EXPECT().UnpinTweet(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Unpin not pinned",
+			method:   http.MethodDelete,
+			username: user.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().UnpinTweet(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(testcase.method, "/tweets/1/pin", nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, testcase.username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+type PollRequest struct {
+	Options         []string `json:"options" binding:"required,min=2,max=4,dive,required,max=25"`
+	DurationMinutes int      `json:"duration_minutes" binding:"required,min=5,max=10080"`
+}
+
+type VotePollRequest struct {
+	OptionID int64 `json:"option_id" binding:"required,min=1"`
+}
+
+type pollURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type pollOptionResponse struct {
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+	// Votes is left out until the results are visible to the caller
+	Votes *int32 `json:"votes,omitempty"`
+}
+
+type pollResponse struct {
+	ID             int64                `json:"id"`
+	Options        []pollOptionResponse `json:"options"`
+	ClosesAt       time.Time            `json:"closes_at"`
+	Closed         bool                 `json:"closed"`
+	ResultsVisible bool                 `json:"results_visible"`
+	TotalVotes     *int32               `json:"total_votes,omitempty"`
+	VotedOptionID  *int64               `json:"voted_option_id,omitempty"`
+}
+
+// newPollResponse hides the tallies until the caller has voted or the poll has closed,
+// vote is nil when the caller hasn't voted
+func newPollResponse(poll database.TweetPoll, vote *database.PollVotes, now time.Time) pollResponse {
+	resp := pollResponse{
+		ID:       poll.Poll.ID,
+		Options:  []pollOptionResponse{},
+		ClosesAt: poll.Poll.ClosesAt,
+		Closed:   poll.Poll.IsClosed(now),
+	}
+	if vote != nil {
+		resp.VotedOptionID = &vote.OptionID
+	}
+	resp.ResultsVisible = resp.Closed || vote != nil
+
+	var total int32
+	for _, option := range poll.Options {
+		optionResp := pollOptionResponse{ID: option.ID, Label: option.Label}
+		if resp.ResultsVisible {
+			votes := option.Votes
+			optionResp.Votes = &votes
+			total += votes
+		}
+		resp.Options = append(resp.Options, optionResp)
+	}
+	if resp.ResultsVisible {
+		resp.TotalVotes = &total
+	}
+	return resp
+}
+
+func (s *Server) VotePoll(c *gin.Context) {
+	var uri pollURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	var req VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	//only the polls of tweets the caller may read can be voted on
+	existing, err := s.transaction.GetPoll(c, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	_, _, status, err := s.visibleTweet(c, existing.TweetID, authHeader.Username)
+	if err != nil {
+		c.JSON(status, ErrResponse(err.Error()))
+		return
+	}
+
+	arg := database.VotePollTxParams{
+		PollID:   uri.ID,
+		OptionID: req.OptionID,
+		Username: authHeader.Username,
+	}
+	poll, err := s.transaction.VotePollTx(c, arg)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+		case database.ErrInvalidPollOption:
+			c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		case database.ErrPollClosed, database.ErrAlreadyVoted:
+			c.JSON(http.StatusConflict, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	vote := database.PollVotes{
+		PollID:   poll.Poll.ID,
+		OptionID: req.OptionID,
+		Username: authHeader.Username,
+	}
+	c.JSON(http.StatusOK, newPollResponse(poll, &vote, time.Now()))
+}
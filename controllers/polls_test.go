@@ -0,0 +1,388 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPoll(tweet database.Tweets, closesAt time.Time) database.TweetPoll {
+	return database.TweetPoll{
+		Poll: database.Polls{ID: 5, TweetID: tweet.ID, ClosesAt: closesAt},
+		Options: []database.PollOptions{
+			{ID: 10, PollID: 5, Position: 0, Label: "yes", Votes: 3},
+			{ID: 11, PollID: 5, Position: 1, Label: "no", Votes: 1},
+		},
+	}
+}
+
+func TestCreateTweetWithPoll(t *testing.T) {
+	user, _ := randomUser(t)
+	tweet := randomTweets(user)
+	poll := randomPoll(tweet, time.Now().Add(time.Hour))
+
+	testcases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"tweet": tweet.Tweet,
+				"poll":  gin.H{"options": []string{"yes", "no"}, "duration_minutes": 60},
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.CreateTweetTxParams{
+					Tweet:    tweet.Tweet,
+					Username: user.Username,
+					Poll: &database.CreatePollTxParams{
+						Options:  []string{"yes", "no"},
+						Duration: time.Hour,
+					},
+				}
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.CreateTweetTxResult{Tweet: tweet, Poll: &poll}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp tweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.NotNil(t, resp.Poll)
+				require.Len(t, resp.Poll.Options, 2)
+				require.False(t, resp.Poll.ResultsVisible)
+			},
+		},
+		{
+			name: "Too many options",
+			body: gin.H{
+				"tweet": tweet.Tweet,
+				"poll":  gin.H{"options": []string{"a", "b", "c", "d", "e"}, "duration_minutes": 60},
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Empty option",
+			body: gin.H{
+				"tweet": tweet.Tweet,
+				"poll":  gin.H{"options": []string{"a", ""}, "duration_minutes": 60},
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Duration too short",
+			body: gin.H{
+				"tweet": tweet.Tweet,
+				"poll":  gin.H{"options": []string{"yes", "no"}, "duration_minutes": 1},
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "/tweet", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestVotePoll(t *testing.T) {
+	user, _ := randomUser(t)
+	tweet := randomTweets(user)
+	poll := randomPoll(tweet, time.Now().Add(time.Hour))
+	//the poll's tweet is looked up before voting
+	visiblePoll := func(transaction *dbmock.MockTransaction) {
+		transaction.EXPECT().GetPoll(gomock.Any(), gomock.Eq(poll.Poll.ID)).Times(1).Return(poll.Poll, nil)
+		transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+		transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	}
+	voter, _ := randomUser(t)
+	protectedUser := user
+	protectedUser.Protected = true
+
+	testcases := []struct {
+		name          string
+		pollID        int64
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			pollID: poll.Poll.ID,
+			body:   gin.H{"option_id": 10},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				visiblePoll(transaction)
+				arg := database.VotePollTxParams{
+					PollID:   poll.Poll.ID,
+					OptionID: 10,
+					Username: user.Username,
+				}
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(poll, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp pollResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.True(t, resp.ResultsVisible)
+				require.Equal(t, int32(4), *resp.TotalVotes)
+				require.Equal(t, int32(3), *resp.Options[0].Votes)
+				require.Equal(t, int64(10), *resp.VotedOptionID)
+			},
+		},
+		{
+			name:   "Already voted",
+			pollID: poll.Poll.ID,
+			body:   gin.H{"option_id": 10},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				visiblePoll(transaction)
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Any()).Times(1).Return(database.TweetPoll{}, database.ErrAlreadyVoted)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "Closed",
+			pollID: poll.Poll.ID,
+			body:   gin.H{"option_id": 10},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				visiblePoll(transaction)
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Any()).Times(1).Return(database.TweetPoll{}, database.ErrPollClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "Invalid option",
+			pollID: poll.Poll.ID,
+			body:   gin.H{"option_id": 99},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				visiblePoll(transaction)
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Any()).Times(1).Return(database.TweetPoll{}, database.ErrInvalidPollOption)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Not found",
+			pollID: 42,
+			body:   gin.H{"option_id": 10},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetPoll(gomock.Any(), gomock.Eq(int64(42))).Times(1).Return(database.Polls{}, sql.ErrNoRows)
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Tweet hidden from the voter",
+			pollID: poll.Poll.ID,
+			body:   gin.H{"option_id": 10},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, voter.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetPoll(gomock.Any(), gomock.Eq(poll.Poll.ID)).Times(1).Return(poll.Poll, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(protectedUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Bad Request",
+			pollID: poll.Poll.ID,
+			body:   gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Unauthorized",
+			pollID: poll.Poll.ID,
+			body:   gin.H{"option_id": 10},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().VotePollTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+
+		url := fmt.Sprintf("/polls/%v/vote", testcase.pollID)
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestPollResultsVisibility(t *testing.T) {
+	user, _ := randomUser(t)
+	tweet := randomTweets(user)
+
+	testcases := []struct {
+		name     string
+		closesAt time.Time
+		votes    []database.PollVotes
+		visible  bool
+	}{
+		{
+			name:     "Open and not voted",
+			closesAt: time.Now().Add(time.Hour),
+			votes:    []database.PollVotes{},
+			visible:  false,
+		},
+		{
+			name:     "Open and voted",
+			closesAt: time.Now().Add(time.Hour),
+			votes:    []database.PollVotes{{PollID: 5, OptionID: 11, Username: user.Username}},
+			visible:  true,
+		},
+		{
+			name:     "Closed",
+			closesAt: time.Now().Add(-time.Minute),
+			votes:    []database.PollVotes{},
+			visible:  true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		poll := randomPoll(tweet, testcase.closesAt)
+		votes := testcase.votes
+
+		transaction := dbmock.NewMockTransaction(controller)
+		transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+		transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+		transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Media{}, nil)
+		transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Eq([]int64{tweet.ID})).Times(1).Return([]database.Polls{poll.Poll}, nil)
+		transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+		transaction.EXPECT().ListPollOptionsByPollIDs(gomock.Any(), gomock.Eq([]int64{poll.Poll.ID})).Times(1).Return(poll.Options, nil)
+		transaction.EXPECT().ListPollVotesByUser(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, arg database.ListPollVotesByUserParams) ([]database.PollVotes, error) {
+				require.Equal(t, user.Username, arg.Username)
+				return votes, nil
+			})
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(gin.H{"id": tweet.ID})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, "/tweet", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code, testcase.name)
+
+		var resp tweetResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Poll)
+		require.Equal(t, testcase.visible, resp.Poll.ResultsVisible, testcase.name)
+		if testcase.visible {
+			require.Equal(t, int32(4), *resp.Poll.TotalVotes)
+			require.Equal(t, int32(1), *resp.Poll.Options[1].Votes)
+		} else {
+			require.Nil(t, resp.Poll.TotalVotes)
+			require.Nil(t, resp.Poll.Options[0].Votes)
+		}
+	}
+}
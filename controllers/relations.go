@@ -14,30 +14,73 @@ type FollowReq struct {
 	FollowUser string `json:"follow_user" binding:"required,min=1,max=30"`
 }
 
-func (s *Server) Follow(c *gin.Context) {
+// bindFollowUser reads who to (un)follow from the path on /api/v1, or from the
+// JSON body on the deprecated routes
+func bindFollowUser(c *gin.Context) (string, error) {
+	if c.Param("username") != "" {
+		var uri usernameURI
+		err := c.ShouldBindUri(&uri)
+		return uri.Username, err
+	}
 	var req FollowReq
-	if err := c.ShouldBindJSON(&req); err != nil {
+	err := c.ShouldBindJSON(&req)
+	return req.FollowUser, err
+}
+
+func (s *Server) Follow(c *gin.Context) {
+	followUser, err := bindFollowUser(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
 		return
 	}
 
 	//check if want to follow user is exist
-	_, err := s.transaction.GetUser(c, req.FollowUser)
+	user, err := s.transaction.GetUser(c, followUser)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
 		return
 	}
 
-	//check if already follow
+	//users who blocked the caller can't be followed
 	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	blocked, err := s.transaction.IsBlocked(c, database.IsBlockedParams{
+		BlockerUsername: followUser,
+		BlockedUsername: authHeader.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if blocked {
+		c.JSON(http.StatusForbidden, ErrResponse(fmt.Sprintf("%v has blocked you", followUser)))
+		return
+	}
+
+	//check if already follow
 	arg := database.GetRelationsParams{
 		FollowerUsername: authHeader.Username,
-		FollowedUsername: req.FollowUser,
+		FollowedUsername: followUser,
 	}
 	_,err = s.transaction.GetRelations(c, arg)
 	if err != sql.ErrNoRows || err == nil {
 		c.JSON(http.StatusCreated,gin.H{
-			"error" : fmt.Sprintf("%v has already followed %v", authHeader.Username, req.FollowUser),
+			"error" : fmt.Sprintf("%v has already followed %v", authHeader.Username, followUser),
+		})
+		return
+	}
+
+	//protected accounts approve their followers, until then the follow is a request
+	if user.Protected {
+		_, err = s.transaction.CreateFollowRequest(c, database.CreateFollowRequestParams{
+			RequesterUsername: authHeader.Username,
+			TargetUsername: followUser,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"Message" : fmt.Sprintf("%v has requested to follow %v", authHeader.Username, followUser),
 		})
 		return
 	}
@@ -45,7 +88,7 @@ func (s *Server) Follow(c *gin.Context) {
 	//////////////////////// FROM DBTRANSACTION ///////////////
 	txArg := database.FollowInputArgs{
 		Username: authHeader.Username,
-		FollowUser: req.FollowUser,
+		FollowUser: followUser,
 	}
 
 	_, err = s.transaction.FollowTx(c, txArg)
@@ -53,21 +96,25 @@ func (s *Server) Follow(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
+	s.homes.follow(authHeader.Username, followUser)
+	if s.pushNotification(c, followUser, authHeader.Username, database.NotificationFollow, 0) {
+		s.hub.Publish(followersTopic(followUser), eventFollow, followEvent{Username: authHeader.Username})
+	}
 	
 	c.JSON(http.StatusOK, gin.H{
-		"Message" : fmt.Sprintf("%v succesfully followed %v", authHeader.Username, req.FollowUser),
+		"Message" : fmt.Sprintf("%v succesfully followed %v", authHeader.Username, followUser),
 	})
 }
 
 func (s *Server) Unfollow(c *gin.Context) {
-	var req FollowReq
-	if err := c.ShouldBindJSON(&req); err != nil {
+	followUser, err := bindFollowUser(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
 		return
 	}
 
 	//check if want to unfollow user is exist
-	_, err := s.transaction.GetUser(c, req.FollowUser)
+	_, err = s.transaction.GetUser(c, followUser)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
 		return
@@ -77,19 +124,34 @@ func (s *Server) Unfollow(c *gin.Context) {
 	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := database.GetRelationsParams{
 		FollowerUsername: authHeader.Username,
-		FollowedUsername: req.FollowUser,
+		FollowedUsername: followUser,
 	}
 	_,err = s.transaction.GetRelations(c, arg)
 	if err != nil {
+		//unfollowing a protected account not followed yet withdraws the request
+		withdrawn, err := s.transaction.DeleteFollowRequest(c, database.DeleteFollowRequestParams{
+			RequesterUsername: authHeader.Username,
+			TargetUsername: followUser,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+			return
+		}
+		if withdrawn > 0 {
+			c.JSON(http.StatusOK, gin.H{
+				"Message" : fmt.Sprintf("%v has withdrawn the request to follow %v", authHeader.Username, followUser),
+			})
+			return
+		}
 		c.JSON(http.StatusCreated,gin.H{
-			"error" : fmt.Sprintf("%v is not following %v", authHeader.Username, req.FollowUser),
+			"error" : fmt.Sprintf("%v is not following %v", authHeader.Username, followUser),
 		})
 		return
 	}
 
 	txArg := database.FollowInputArgs{
 		Username: authHeader.Username,
-		FollowUser: req.FollowUser,
+		FollowUser: followUser,
 	}
 
 	err = s.transaction.UnfollowTx(c, txArg)
@@ -97,8 +159,9 @@ func (s *Server) Unfollow(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
+	s.homes.unfollow(authHeader.Username, followUser)
 
 	c.JSON(http.StatusOK, gin.H{
-		"Message" : fmt.Sprintf("%v succesfully unfollowed %v", authHeader.Username, req.FollowUser),
+		"Message" : fmt.Sprintf("%v succesfully unfollowed %v", authHeader.Username, followUser),
 	})
 }
\ No newline at end of file
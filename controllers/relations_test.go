@@ -20,6 +20,8 @@ import (
 func TestFollow(t *testing.T) {
 	user, _ := randomUser(t)
 	followUser, _ := randomUser(t)
+	protectedUser, _ := randomUser(t)
+	protectedUser.Protected = true
 
 	testcases := []struct{
 		name string
@@ -38,6 +40,7 @@ func TestFollow(t *testing.T) {
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
 				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(followUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
 				getRelationArg := database.GetRelationsParams{
 					FollowerUsername: user.Username,
 					FollowedUsername: followUser.Username,
@@ -48,11 +51,36 @@ func TestFollow(t *testing.T) {
 					FollowUser: followUser.Username,
 				}
 				transaction.EXPECT().FollowTx(gomock.Any(), gomock.Eq(followInputArg)).Times(1).Return(database.FollowInputResult{}, nil)
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(database.NotificationPreferences{}, sql.ErrNoRows)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "Protected account",
+			body: gin.H{
+				"follow_user" : protectedUser.Username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(protectedUser.Username)).Times(1).Return(protectedUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				requestArg := database.CreateFollowRequestParams{
+					RequesterUsername: user.Username,
+					TargetUsername: protectedUser.Username,
+				}
+				transaction.EXPECT().CreateFollowRequest(gomock.Any(), gomock.Eq(requestArg)).Times(1).Return(int64(1), nil)
+				transaction.EXPECT().FollowTx(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
 		{
 			name: "Bad Request",
 			body: gin.H{
@@ -87,6 +115,28 @@ func TestFollow(t *testing.T) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
+		{
+			name: "Blocked",
+			body: gin.H{
+				"follow_user" : followUser.Username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(followUser, nil)
+				blockedArg := database.IsBlockedParams{
+					BlockerUsername: followUser.Username,
+					BlockedUsername: user.Username,
+				}
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(blockedArg)).Times(1).Return(true, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().FollowTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "User already followed",
 			body: gin.H{
@@ -97,6 +147,7 @@ func TestFollow(t *testing.T) {
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
 				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(followUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
 				getRelationArg := database.GetRelationsParams{
 					FollowerUsername: user.Username,
 					FollowedUsername: followUser.Username,
@@ -118,6 +169,7 @@ func TestFollow(t *testing.T) {
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
 				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(followUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
 				getRelationArg := database.GetRelationsParams{
 					FollowerUsername: user.Username,
 					FollowedUsername: followUser.Username,
@@ -162,6 +214,49 @@ func TestFollow(t *testing.T) {
 	}
 }
 
+func TestFollowLiveEventFollowsPreferences(t *testing.T) {
+	user, _ := randomUser(t)
+	followUser, _ := randomUser(t)
+
+	followsOff := database.DefaultNotificationPreferences(followUser.Username)
+	followsOff.Follows = false
+
+	for _, prefs := range []database.NotificationPreferences{database.DefaultNotificationPreferences(followUser.Username), followsOff} {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(followUser, nil)
+		transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+		transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+		transaction.EXPECT().FollowTx(gomock.Any(), gomock.Any()).Times(1).Return(database.FollowInputResult{}, nil)
+		transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(prefs, nil)
+
+		server := NewTestServer(t, transaction)
+		sub := server.hub.Subscribe([]string{followersTopic(followUser.Username)}, 0)
+
+		data, err := json.Marshal(gin.H{"follow_user": followUser.Username})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/follow", bytes.NewReader(data))
+		require.NoError(t, err)
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		//the follow is streamed only when its notification is pushed
+		select {
+		case event := <-sub.Events():
+			require.True(t, prefs.Follows)
+			require.Equal(t, followEvent{Username: user.Username}, event.Data)
+		default:
+			require.False(t, prefs.Follows)
+		}
+		sub.Close()
+	}
+}
+
 func TestUnfollow(t *testing.T) {
 	user, _ := randomUser(t)
 	followUser, _ := randomUser(t)
@@ -247,12 +342,35 @@ func TestUnfollow(t *testing.T) {
 					FollowedUsername: followUser.Username,
 				}
 				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Eq(getRelationArg)).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				requestArg := database.DeleteFollowRequestParams{
+					RequesterUsername: user.Username,
+					TargetUsername: followUser.Username,
+				}
+				transaction.EXPECT().DeleteFollowRequest(gomock.Any(), gomock.Eq(requestArg)).Times(1).Return(int64(0), nil)
 				transaction.EXPECT().UnfollowTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
+		{
+			name: "Withdraws a follow request",
+			body: gin.H{
+				"follow_user" : followUser.Username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followUser.Username)).Times(1).Return(followUser, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().DeleteFollowRequest(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				transaction.EXPECT().UnfollowTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name: "Internal server error",
 			body: gin.H{
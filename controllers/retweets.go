@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// Retweet shares a tweet on the caller's timeline, retweeting a retweet shares the original
+func (s *Server) Retweet(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	tweet, author, status, err := s.visibleTweet(c, uri.ID, authHeader.Username)
+	if err != nil {
+		c.JSON(status, ErrResponse(err.Error()))
+		return
+	}
+	if tweet.RetweetOfID.Valid {
+		tweet, author, status, err = s.visibleTweet(c, tweet.RetweetOfID.Int64, authHeader.Username)
+		if err != nil {
+			c.JSON(status, ErrResponse(err.Error()))
+			return
+		}
+	}
+	//even followers can't spread what a protected account posts
+	if author.Protected && author.Username != authHeader.Username {
+		c.JSON(http.StatusForbidden, ErrResponse("tweets of protected accounts can't be retweeted"))
+		return
+	}
+
+	created, err := s.transaction.CreateTweetTx(c, database.CreateTweetTxParams{
+		Username:    authHeader.Username,
+		Tweet:       tweet.Tweet,
+		RetweetOfID: sql.NullInt64{Int64: tweet.ID, Valid: true},
+	})
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				c.JSON(http.StatusConflict, ErrResponse("you have already retweeted this tweet"))
+				return
+			}
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := newTweetResponse(created.Tweet, created.Media)
+	s.hub.Publish(tweetsTopic(authHeader.Username), eventTweet, resp)
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetweet(t *testing.T) {
+	user, _ := randomUser(t)
+	author, _ := randomUser(t)
+	original := randomTweets(author)
+	protectedAuthor := author
+	protectedAuthor.Protected = true
+
+	testcases := []struct {
+		name          string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				arg := database.CreateTweetTxParams{
+					Username:    user.Username,
+					Tweet:       original.Tweet,
+					RetweetOfID: sql.NullInt64{Int64: original.ID, Valid: true},
+				}
+				retweet := database.Tweets{ID: 2, Tweet: original.Tweet, Username: user.Username, RetweetOfID: arg.RetweetOfID}
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.CreateTweetTxResult{Tweet: retweet}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp tweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.NotNil(t, resp.RetweetOfID)
+				require.Equal(t, original.ID, *resp.RetweetOfID)
+			},
+		},
+		{
+			name: "Retweet of a retweet shares the original",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				retweet := database.Tweets{ID: 1, Tweet: original.Tweet, Username: author.Username, RetweetOfID: sql.NullInt64{Int64: 5, Valid: true}}
+				first := original
+				first.ID = 5
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(retweet, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(first, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(2).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(2).Return(false, nil)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg database.CreateTweetTxParams) (database.CreateTweetTxResult, error) {
+						require.Equal(t, int64(5), arg.RetweetOfID.Int64)
+						return database.CreateTweetTxResult{Tweet: database.Tweets{ID: 6, RetweetOfID: arg.RetweetOfID}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Protected author",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(protectedAuthor, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, nil)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Already retweeted",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.CreateTweetTxResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "Not found",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(http.MethodPost, "/tweets/1/retweet", nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleTweetRequest struct {
+	CreateTweetRequest
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+type RescheduleTweetRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+type scheduledTweetURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type scheduledTweetResponse struct {
+	ID        int64      `json:"id"`
+	Tweet     string     `json:"tweet"`
+	MediaIDs  []int64    `json:"media_ids"`
+	PublishAt time.Time  `json:"publish_at"`
+	FailedAt  *time.Time `json:"failed_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newScheduledTweetResponse(scheduled database.ScheduledTweets) scheduledTweetResponse {
+	resp := scheduledTweetResponse{
+		ID:        scheduled.ID,
+		Tweet:     scheduled.Tweet,
+		MediaIDs:  scheduled.MediaIds,
+		PublishAt: scheduled.PublishAt,
+		Error:     scheduled.Error,
+		CreatedAt: scheduled.CreatedAt,
+	}
+	if scheduled.FailedAt.Valid {
+		resp.FailedAt = &scheduled.FailedAt.Time
+	}
+	return resp
+}
+
+func validatePublishAt(publishAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return fmt.Errorf("publish_at must be in the future")
+	}
+	return nil
+}
+
+func (s *Server) ScheduleTweet(c *gin.Context) {
+	var req ScheduleTweetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if err := validatePublishAt(req.PublishAt); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if req.Poll != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse("polls can't be scheduled"))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	mediaIDs := req.MediaIDs
+	if mediaIDs == nil {
+		mediaIDs = []int64{}
+	}
+	scheduled, err := s.transaction.CreateScheduledTweet(c, database.CreateScheduledTweetParams{
+		Username:  authHeader.Username,
+		Tweet:     req.Tweet,
+		MediaIds:  mediaIDs,
+		PublishAt: req.PublishAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newScheduledTweetResponse(scheduled))
+}
+
+func (s *Server) ListScheduledTweets(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	scheduled, err := s.transaction.ListScheduledTweets(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := []scheduledTweetResponse{}
+	for _, st := range scheduled {
+		resp = append(resp, newScheduledTweetResponse(st))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) RescheduleTweet(c *gin.Context) {
+	var uri scheduledTweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	var req RescheduleTweetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if err := validatePublishAt(req.PublishAt); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	//only matches the caller's own rows that haven't been published yet
+	scheduled, err := s.transaction.RescheduleTweet(c, database.RescheduleTweetParams{
+		PublishAt: req.PublishAt,
+		ID:        uri.ID,
+		Username:  authHeader.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, newScheduledTweetResponse(scheduled))
+}
+
+func (s *Server) CancelScheduledTweet(c *gin.Context) {
+	var uri scheduledTweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	deleted, err := s.transaction.CancelScheduledTweet(c, database.CancelScheduledTweetParams{
+		ID:       uri.ID,
+		Username: authHeader.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("scheduled tweet %v not found", uri.ID)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Scheduled tweet with ID %v has succesfully been cancelled", uri.ID),
+	})
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomScheduledTweet(user database.Users) database.ScheduledTweets {
+	return database.ScheduledTweets{
+		ID:        1,
+		Username:  user.Username,
+		Tweet:     util.GetRandomString(15),
+		MediaIds:  []int64{},
+		PublishAt: time.Now().UTC().Add(time.Hour).Truncate(time.Second),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestScheduledTweets(t *testing.T) {
+	user, _ := randomUser(t)
+	scheduled := randomScheduledTweet(user)
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Schedule OK",
+			method: http.MethodPost,
+			url:    "/scheduled_tweets",
+			body: gin.H{
+				"tweet":      scheduled.Tweet,
+				"publish_at": scheduled.PublishAt,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.CreateScheduledTweetParams{
+					Username:  user.Username,
+					Tweet:     scheduled.Tweet,
+					MediaIds:  []int64{},
+					PublishAt: scheduled.PublishAt,
+				}
+				transaction.EXPECT().CreateScheduledTweet(gomock.Any(), gomock.Eq(arg)).Times(1).Return(scheduled, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp scheduledTweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, scheduled.ID, resp.ID)
+			},
+		},
+		{
+			name:   "Schedule in the past",
+			method: http.MethodPost,
+			url:    "/scheduled_tweets",
+			body: gin.H{
+				"tweet":      scheduled.Tweet,
+				"publish_at": time.Now().Add(-time.Minute),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateScheduledTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Schedule too many media",
+			method: http.MethodPost,
+			url:    "/scheduled_tweets",
+			body: gin.H{
+				"tweet":      scheduled.Tweet,
+				"media_ids":  []int64{1, 2, 3, 4, 5},
+				"publish_at": scheduled.PublishAt,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateScheduledTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "List OK",
+			method: http.MethodGet,
+			url:    "/scheduled_tweets",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListScheduledTweets(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.ScheduledTweets{scheduled}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp []scheduledTweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp, 1)
+			},
+		},
+		{
+			name:   "Reschedule OK",
+			method: http.MethodPut,
+			url:    "/scheduled_tweets/1",
+			body:   gin.H{"publish_at": scheduled.PublishAt},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.RescheduleTweetParams{
+					PublishAt: scheduled.PublishAt,
+					ID:        1,
+					Username:  user.Username,
+				}
+				transaction.EXPECT().RescheduleTweet(gomock.Any(), gomock.Eq(arg)).Times(1).Return(scheduled, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Reschedule not found",
+			method: http.MethodPut,
+			url:    "/scheduled_tweets/1",
+			body:   gin.H{"publish_at": scheduled.PublishAt},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().RescheduleTweet(gomock.Any(), gomock.Any()).Times(1).Return(database.ScheduledTweets{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Cancel OK",
+			method: http.MethodDelete,
+			url:    "/scheduled_tweets/1",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.CancelScheduledTweetParams{ID: 1, Username: user.Username}
+				transaction.EXPECT().CancelScheduledTweet(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Cancel not found",
+			method: http.MethodDelete,
+			url:    "/scheduled_tweets/1",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CancelScheduledTweet(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		var body *bytes.Reader
+		if testcase.body != nil {
+			data, err := json.Marshal(testcase.body)
+			require.NoError(t, err)
+			body = bytes.NewReader(data)
+		} else {
+			body = bytes.NewReader(nil)
+		}
+
+		req, err := http.NewRequest(testcase.method, testcase.url, body)
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
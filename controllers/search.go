@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/search"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// userSearchCandidates is how many of the closest matches get their follows
+	// looked up and ranked, it keeps short typeahead queries fast
+	userSearchCandidates   = 100
+	defaultUserSearchLimit = 8
+)
+
+var errEmptyUserQuery = errors.New("search query is empty")
+
+// likeEscaper escapes LIKE wildcards, underscores are common in usernames
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchTweetsRequest takes the query in q, see search.Parse for the operators
+type SearchTweetsRequest struct {
+	PageRequest
+	Query string `form:"q" binding:"required,max=512"`
+}
+
+// searchList names the results of a query for its cursors, hashed to keep them short
+func searchList(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return fmt.Sprintf("search:%x", sum[:8])
+}
+
+// SearchTweets finds the tweets matching a query that the caller can see, the most
+// relevant first and the newest among equally relevant ones. Relevance only comes
+// from the text so it holds still between pages, likes would skip or repeat tweets.
+// A query made only of from: and filters matches every tweet of that user they let
+// through, newest first. Results only page forward.
+func (s *Server) SearchTweets(c *gin.Context) {
+	var req SearchTweetsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	query, err := search.Parse(req.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPageLimit
+	}
+
+	list := searchList(req.Query)
+	beforeRank, beforeID := float32(math.MaxFloat32), int64(math.MaxInt64)
+	if req.Cursor != "" {
+		cursor, err := s.decodeCursor(list, req.Cursor)
+		if err != nil || cursor.Reverse {
+			c.JSON(http.StatusBadRequest, ErrResponse(errInvalidCursor.Error()))
+			return
+		}
+		beforeRank, beforeID = float32(cursor.Score), cursor.ID
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	since, until := query.Window()
+
+	rows, err := s.transaction.SearchTweets(c, database.SearchTweetsParams{
+		Terms:        query.Terms,
+		Hashtags:     query.HashtagQuery(),
+		MatchAll:     query.MatchAll(),
+		FromUsername: query.From,
+		Since:        since,
+		Until:        until,
+		MinLikes:     query.MinLikes,
+		Viewer:       authHeader.Username,
+		BeforeRank:   beforeRank,
+		BeforeID:     beforeID,
+		PageSize:     req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	tweets := make([]database.Tweets, len(rows))
+	for i, row := range rows {
+		tweets[i] = database.Tweets{
+			ID:          row.ID,
+			Tweet:       row.Tweet,
+			Username:    row.Username,
+			Likes:       row.Likes,
+			CreatedAt:   row.CreatedAt,
+			EditedAt:    row.EditedAt,
+			InReplyToID: row.InReplyToID,
+			RetweetOfID: row.RetweetOfID,
+		}
+	}
+
+	resp := timelineResponse{}
+	resp.Tweets, err = s.tweetResponses(c, tweets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if len(rows) == int(req.Limit) {
+		last := rows[len(rows)-1]
+		resp.NextCursor = s.encodeCursor(pageCursor{List: list, ID: last.ID, Score: float64(last.Rank)})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SearchUsersRequest is a typeahead query, a leading @ is ignored
+type SearchUsersRequest struct {
+	Query string `form:"q" binding:"required,max=50"`
+	Limit int32  `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+type userSearchResponse struct {
+	userSummaryResponse
+	Following  bool `json:"following"`
+	FollowedBy bool `json:"followed_by"`
+}
+
+// SearchUsers suggests users as the caller types. Usernames and names are matched
+// by trigram similarity, usernames starting with the query and an exact username
+// come first, then popular users and the ones the caller follows or is followed
+// by get a boost. The caller's follows and followers starting with the query are
+// always ranked, even past the closest strangers. It's a single page, cached
+// briefly by the client since every keystroke asks again.
+func (s *Server) SearchUsers(c *gin.Context) {
+	var req SearchUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	query := strings.TrimPrefix(strings.TrimSpace(req.Query), "@")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, ErrResponse(errEmptyUserQuery.Error()))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultUserSearchLimit
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	rows, err := s.transaction.SearchUsers(c, database.SearchUsersParams{
+		Query:          query,
+		Prefix:         likeEscaper.Replace(query) + "%",
+		Viewer:         authHeader.Username,
+		CandidateLimit: userSearchCandidates,
+		PageSize:       req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := make([]userSearchResponse, len(rows))
+	for i, row := range rows {
+		resp[i] = userSearchResponse{
+			userSummaryResponse: userSummaryResponse{
+				Username:       row.Username,
+				Name:           row.Name,
+				FollowersCount: row.FollowersCount.Int32,
+				FollowingCount: row.FollowingCount.Int32,
+			},
+			Following:  row.Following,
+			FollowedBy: row.FollowedBy,
+		}
+	}
+
+	c.Header("Cache-Control", "private, max-age=30")
+	c.JSON(http.StatusOK, resp)
+}
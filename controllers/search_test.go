@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/search"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTweets(t *testing.T) {
+	user, _ := randomUser(t)
+	author, _ := randomUser(t)
+
+	query := `"error handling" #Golang from:` + author.Username + ` since:2022-03-01 min_likes:2`
+	list := searchList(query)
+	rows := []database.SearchTweetsRow{
+		{ID: 9, Tweet: "error handling in #golang", Username: author.Username, Rank: 0.5},
+		{ID: 4, Tweet: "more #golang error handling", Username: author.Username, Rank: 0.25},
+	}
+
+	expectTweets := func(transaction *dbmock.MockTransaction) {
+		transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Media{}, nil)
+		transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+		transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+	}
+
+	testcases := []struct {
+		name          string
+		query         string
+		limit         string
+		cursor        *pageCursor
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: query,
+			limit: "2",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.SearchTweetsParams{
+					Terms:        `"error handling"`,
+					Hashtags:     "'#golang'",
+					FromUsername: author.Username,
+					Since:        time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC),
+					Until:        time.Date(9999, time.January, 1, 0, 0, 0, 0, time.UTC),
+					MinLikes:     2,
+					Viewer:       user.Username,
+					BeforeRank:   math.MaxFloat32,
+					BeforeID:     math.MaxInt64,
+					PageSize:     2,
+				}
+				transaction.EXPECT().SearchTweets(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows, nil)
+				expectTweets(transaction)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 2)
+				require.Equal(t, int64(9), resp.Tweets[0].ID)
+				require.Empty(t, resp.PrevCursor)
+
+				next := cursorPayload(t, resp.NextCursor)
+				require.Equal(t, pageCursor{List: list, ID: 4, Score: 0.25}, next)
+			},
+		},
+		{
+			name:   "Next page",
+			query:  query,
+			limit:  "2",
+			cursor: &pageCursor{List: list, ID: 4, Score: 0.25},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchTweets(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg database.SearchTweetsParams) ([]database.SearchTweetsRow, error) {
+						require.Equal(t, float32(0.25), arg.BeforeRank)
+						require.Equal(t, int64(4), arg.BeforeID)
+						return []database.SearchTweetsRow{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Empty(t, resp.Tweets)
+				require.Empty(t, resp.NextCursor)
+			},
+		},
+		{
+			name:  "Operators only",
+			query: "from:" + author.Username,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchTweets(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg database.SearchTweetsParams) ([]database.SearchTweetsRow, error) {
+						require.True(t, arg.MatchAll)
+						require.Equal(t, int32(defaultPageLimit), arg.PageSize)
+						return rows[:1], nil
+					})
+				expectTweets(transaction)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Empty(t, resp.NextCursor)
+			},
+		},
+		{
+			name:   "Cursor of another query",
+			query:  "golang",
+			cursor: &pageCursor{List: list, ID: 4, Score: 0.25},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Bad operator",
+			query: "golang min_likes:lots",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Filters only",
+			query: "min_likes:0",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), search.ErrEmptyQuery.Error())
+			},
+		},
+		{
+			name:  "No query",
+			query: "",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		target := "/api/v1/search/tweets?q=" + url.QueryEscape(testcase.query)
+		if testcase.limit != "" {
+			target += "&limit=" + testcase.limit
+		}
+		req, err := http.NewRequest(http.MethodGet, withCursor(server, target, testcase.cursor), nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, server, recorder)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testcases := []struct {
+		name          string
+		query         string
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "q=" + url.QueryEscape(" @john_d%"),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.SearchUsersParams{
+					Query:          "john_d%",
+					Prefix:         `john\_d\%%`,
+					Viewer:         user.Username,
+					CandidateLimit: userSearchCandidates,
+					PageSize:       defaultUserSearchLimit,
+				}
+				transaction.EXPECT().SearchUsers(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.SearchUsersRow{
+					{Username: "john_doe", Name: "John", FollowersCount: sql.NullInt32{Int32: 12, Valid: true}, Following: true, Score: 1.8},
+					{Username: "johnny", Name: "Johnny", FollowedBy: true, Score: 0.9},
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "private, max-age=30", recorder.Header().Get("Cache-Control"))
+
+				var resp []userSearchResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp, 2)
+				require.Equal(t, "john_doe", resp[0].Username)
+				require.Equal(t, int32(12), resp[0].FollowersCount)
+				require.True(t, resp[0].Following)
+				require.False(t, resp[0].FollowedBy)
+				require.True(t, resp[1].FollowedBy)
+			},
+		},
+		{
+			name:  "Only an @",
+			query: "q=%40",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Limit too big",
+			query: "q=john&limit=50",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/search/users?"+testcase.query, nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
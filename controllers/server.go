@@ -1,11 +1,19 @@
 package controllers
 
 import (
+	"context"
 	"log"
+	"net"
+	"net/http"
 
 	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/ranking"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/ratelimit"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/storage"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/stream"
 	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
 	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/webhook"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,6 +21,7 @@ const (
 	authorizationHeaderKey = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "payload"
+	apiVersionPrefix = "/api/v1"
 )
 
 type Server struct {
@@ -20,6 +29,14 @@ type Server struct {
 	config util.Config
 	transaction database.Transaction
 	paseto token.Paseto
+	blobStore storage.BlobStore
+	ranker *ranking.Ranker
+	hub *stream.Hub
+	webhooks *webhook.Sender
+	prekeyBundles *ratelimit.Limiter
+	sockets wsRegistry
+	homes homeStreams
+	httpServer *http.Server
 }
 
 func NewServer(config util.Config, dbtx database.Transaction) (*Server, error) {
@@ -27,19 +44,114 @@ func NewServer(config util.Config, dbtx database.Transaction) (*Server, error) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	server := &Server{config: config, transaction: dbtx, paseto: *paseto}
+	blobStore, err := storage.NewLocalBlobStore(config.Media_Path)
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{
+		config: config,
+		transaction: dbtx,
+		paseto: *paseto,
+		blobStore: blobStore,
+		ranker: ranking.NewRanker(),
+		hub: stream.NewHub(config.Stream_Buffer_Size, config.Stream_History_Size),
+		webhooks: webhook.NewSender(config.Webhook_Timeout),
+		prekeyBundles: ratelimit.NewLimiter(config.Prekey_Bundle_Limit, config.Prekey_Bundle_Window),
+	}
 	server.SetupRouter()
+	server.httpServer = &http.Server{Handler: server.router}
 	return server, nil
 }
 
 func (s *Server) SetupRouter(){
 	router := gin.Default()
 
+	// media urls handed out in responses, not part of the versioned api
+	mediaRouter := router.Group("/media").Use(OptionalAuthMiddleware(s.paseto))
+	mediaRouter.GET("/:id", s.GetMediaFile)
+	mediaRouter.GET("/:id/thumbnail", s.GetMediaThumbnail)
+
+	router.GET(apiVersionPrefix+"/ws", ProtocolTokenMiddleware(), AuthMiddleware(s.paseto), s.ServeWebSocket)
+
+	v1AuthRouter := s.setupRoutes(router.Group(apiVersionPrefix))
+
+	//resources addressed by path instead of JSON bodies
+	v1AuthRouter.GET("/tweets/:id", s.GetTweet)
+	v1AuthRouter.DELETE("/tweets/:id", s.DeleteTweet)
+	v1AuthRouter.PUT("/tweets/:id/like", s.LikeTweet)
+	v1AuthRouter.DELETE("/tweets/:id/like", s.UnlikeTweet)
+	v1AuthRouter.GET("/users/:username/followers", s.GetFollowersList)
+	v1AuthRouter.GET("/users/:username/following", s.GetFollowingList)
+	v1AuthRouter.PUT("/users/:username/follow", s.Follow)
+	v1AuthRouter.DELETE("/users/:username/follow", s.Unfollow)
+	v1AuthRouter.GET("/follow_requests", s.ListFollowRequests)
+	v1AuthRouter.PUT("/follow_requests/:username", s.ApproveFollowRequest)
+	v1AuthRouter.DELETE("/follow_requests/:username", s.DenyFollowRequest)
+	v1AuthRouter.GET("/feeds/for_you", s.GetForYouFeed)
+	v1AuthRouter.GET("/stream/home", s.StreamHome)
+	v1AuthRouter.GET("/notifications", s.ListNotifications)
+	v1AuthRouter.GET("/notifications/unread_count", s.GetUnreadNotificationCount)
+	v1AuthRouter.PUT("/notifications/read", s.MarkAllNotificationsRead)
+	v1AuthRouter.GET("/notifications/preferences", s.GetNotificationPreferences)
+	v1AuthRouter.PUT("/notifications/preferences", s.UpdateNotificationPreferences)
+	v1AuthRouter.PUT("/notifications/:id/read", s.MarkNotificationRead)
+	v1AuthRouter.POST("/webhooks", s.CreateWebhook)
+	v1AuthRouter.GET("/webhooks", s.ListWebhooks)
+	v1AuthRouter.DELETE("/webhooks/:id", s.DeleteWebhook)
+	v1AuthRouter.POST("/webhooks/:id/test", s.TestWebhook)
+	v1AuthRouter.GET("/webhooks/:id/dead_letters", s.ListDeadLetters)
+	v1AuthRouter.POST("/webhooks/:id/dead_letters/:delivery_id/retry", s.RetryDeadLetter)
+	v1AuthRouter.GET("/dms/settings", s.GetDMSettings)
+	v1AuthRouter.PUT("/dms/settings", s.UpdateDMSettings)
+	v1AuthRouter.POST("/dms/:username", s.SendDirectMessage)
+	v1AuthRouter.GET("/conversations", s.ListConversations)
+	v1AuthRouter.POST("/conversations", s.CreateGroup)
+	v1AuthRouter.GET("/conversations/unread_count", s.GetUnreadConversationCount)
+	v1AuthRouter.GET("/conversations/:id/messages", s.ListMessages)
+	v1AuthRouter.POST("/conversations/:id/messages", s.ReplyInConversation)
+	v1AuthRouter.PUT("/conversations/:id/read", s.MarkConversationRead)
+	v1AuthRouter.PUT("/conversations/:id", s.RenameGroup)
+	v1AuthRouter.POST("/conversations/:id/leave", s.LeaveGroup)
+	v1AuthRouter.POST("/conversations/:id/members", s.AddGroupMembers)
+	v1AuthRouter.DELETE("/conversations/:id/members/:username", s.RemoveGroupMember)
+	v1AuthRouter.PUT("/conversations/:id/members/:username/role", s.SetGroupRole)
+	v1AuthRouter.POST("/devices", s.RegisterDevice)
+	v1AuthRouter.GET("/devices", s.ListDevices)
+	v1AuthRouter.DELETE("/devices/:id", s.DeleteDevice)
+	v1AuthRouter.PUT("/devices/:id/signed_prekey", s.RotateSignedPrekey)
+	v1AuthRouter.POST("/devices/:id/prekeys", s.UploadPrekeys)
+	v1AuthRouter.GET("/devices/:id/envelopes", s.ListEnvelopes)
+	v1AuthRouter.POST("/devices/:id/envelopes/ack", s.AckEnvelopes)
+	v1AuthRouter.GET("/users/:username/prekey_bundles", s.GetPrekeyBundles)
+	v1AuthRouter.POST("/envelopes", s.SendEnvelopes)
+	v1AuthRouter.GET("/search/tweets", s.SearchTweets)
+	v1AuthRouter.GET("/search/users", s.SearchUsers)
+
+	// the unversioned routes predate /api/v1 and are kept as deprecated aliases
+	authRouter := s.setupRoutes(router.Group("/", DeprecationMiddleware("")))
+
+	//tweets and relations sent in JSON bodies
+	authRouter.DELETE("/tweet", DeprecationMiddleware(apiVersionPrefix+"/tweets/:id"), s.DeleteTweet)
+	authRouter.GET("/tweet", DeprecationMiddleware(apiVersionPrefix+"/tweets/:id"), s.GetTweet)
+	authRouter.POST("/like", DeprecationMiddleware(apiVersionPrefix+"/tweets/:id/like"), s.LikeTweet)
+	authRouter.DELETE("/unlike", DeprecationMiddleware(apiVersionPrefix+"/tweets/:id/like"), s.UnlikeTweet)
+	authRouter.POST("/follow", DeprecationMiddleware(apiVersionPrefix+"/users/:username/follow"), s.Follow)
+	authRouter.DELETE("/unfollow", DeprecationMiddleware(apiVersionPrefix+"/users/:username/follow"), s.Unfollow)
+
+	s.router = router
+}
+
+// setupRoutes registers the routes served both under /api/v1 and unversioned,
+// and returns the authenticated router of group
+func (s *Server) setupRoutes(group *gin.RouterGroup) gin.IRoutes {
 	// out of auth middleware
-	router.POST("/register", s.SignUp)
-	router.POST("/login", s.Login)
+	group.POST("/register", s.SignUp)
+	group.POST("/login", s.Login)
+
+	optionalAuthRouter := group.Group("/").Use(OptionalAuthMiddleware(s.paseto))
+	optionalAuthRouter.GET("/users/:username/tweets", s.GetUserTweets)
 
-	authRouter := router.Group("/").Use(AuthMiddleware(s.paseto))
+	authRouter := group.Group("/").Use(AuthMiddleware(s.paseto))
 
 	//user
 	authRouter.GET("/profile", s.GetUserProfile)
@@ -51,21 +163,63 @@ func (s *Server) SetupRouter(){
 
 	//tweets
 	authRouter.POST("/tweet", s.CreateTweet)
-	authRouter.DELETE("/tweet", s.DeleteTweet)
-	authRouter.GET("/tweet", s.GetTweet)
-	authRouter.POST("/like", s.LikeTweet)
-	authRouter.DELETE("/unlike", s.UnlikeTweet)
 	authRouter.GET("/feeds", s.GetFeeds)
+	authRouter.PUT("/tweets/:id", s.EditTweet)
+	authRouter.GET("/tweets/:id/history", s.GetTweetHistory)
+	authRouter.POST("/scheduled_tweets", s.ScheduleTweet)
+	authRouter.GET("/scheduled_tweets", s.ListScheduledTweets)
+	authRouter.PUT("/scheduled_tweets/:id", s.RescheduleTweet)
+	authRouter.DELETE("/scheduled_tweets/:id", s.CancelScheduledTweet)
+	authRouter.POST("/drafts", s.CreateDraft)
+	authRouter.GET("/drafts", s.ListDrafts)
+	authRouter.PUT("/drafts/:id", s.UpdateDraft)
+	authRouter.DELETE("/drafts/:id", s.DeleteDraft)
+	authRouter.POST("/drafts/:id/publish", s.PublishDraft)
+	authRouter.POST("/polls/:id/vote", s.VotePoll)
+	authRouter.POST("/tweets/:id/bookmark", s.AddBookmark)
+	authRouter.DELETE("/tweets/:id/bookmark", s.RemoveBookmark)
+	authRouter.GET("/bookmarks", s.ListBookmarks)
+	authRouter.POST("/tweets/:id/pin", s.PinTweet)
+	authRouter.DELETE("/tweets/:id/pin", s.UnpinTweet)
+	authRouter.GET("/tweets/:id/likes", s.GetTweetLikes)
+	authRouter.GET("/users/:username/likes", s.GetUserLikes)
+	authRouter.POST("/tweets/:id/retweet", s.Retweet)
+	authRouter.PUT("/protected", s.SetProtected)
+	authRouter.POST("/users/:username/block", s.BlockUser)
+	authRouter.DELETE("/users/:username/block", s.UnblockUser)
+	authRouter.POST("/media", s.UploadMedia)
 
-	//relations
-	authRouter.POST("/follow", s.Follow)
-	authRouter.DELETE("/unfollow", s.Unfollow)
-
-	s.router = router
+	return authRouter
 }
 
+// Start serves until Shutdown is called
 func (s *Server) Start(address string) error {
-	return s.router.Run(address)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	err = s.httpServer.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops taking requests and waits for the ones in flight until ctx is done.
+// Streams are ended and WebSockets closed with a going away status, clients should
+// reconnect to another instance.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.hub.Close()
+	drained := make(chan error, 1)
+	go func() {
+		drained <- s.sockets.drain(ctx)
+	}()
+
+	err := s.httpServer.Shutdown(ctx)
+	if drainErr := <-drained; err == nil {
+		err = drainErr
+	}
+	return err
 }
 
 func ErrResponse(error string) gin.H {
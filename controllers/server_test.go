@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionedRoutes(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	tweet := randomTweets(other)
+	ownTweet := randomTweets(user)
+	protectedOther := other
+	protectedOther.Protected = true
+
+	noDeprecation := func(t *testing.T, recorder *httptest.ResponseRecorder) {
+		require.Empty(t, recorder.Header().Get("Deprecation"))
+		require.Empty(t, recorder.Header().Get("Link"))
+	}
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Get tweet",
+			method: http.MethodGet,
+			url:    "/api/v1/tweets/1",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				noDeprecation(t, recorder)
+
+				var resp tweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, tweet.ID, resp.ID)
+			},
+		},
+		{
+			name:   "Get tweet deprecated",
+			method: http.MethodGet,
+			url:    "/tweet",
+			body:   gin.H{"id": tweet.ID},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get("Deprecation"))
+				require.Equal(t, `</api/v1/tweets/:id>; rel="successor-version"`, recorder.Header().Get("Link"))
+			},
+		},
+		{
+			name:   "Delete tweet bad id",
+			method: http.MethodDelete,
+			url:    "/api/v1/tweets/abc",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().DeleteTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Delete tweet",
+			method: http.MethodDelete,
+			url:    "/api/v1/tweets/1",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(ownTweet.ID)).Times(1).Return(ownTweet, nil)
+				transaction.EXPECT().DeleteTweet(gomock.Any(), gomock.Eq(ownTweet.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				noDeprecation(t, recorder)
+			},
+		},
+		{
+			name:   "Like tweet",
+			method: http.MethodPut,
+			url:    "/api/v1/tweets/1/like",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				arg := database.CreateLikeRelationParams{Username: user.Username, TweetID: tweet.ID}
+				transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Any()).Times(1).Return(database.LikeRelations{}, sql.ErrNoRows)
+				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(tweet.Username)).Times(1).Return(database.NotificationPreferences{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Unlike tweet",
+			method: http.MethodDelete,
+			url:    "/api/v1/tweets/1/like",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.DeleteLikeRelationParams{Username: user.Username, TweetID: tweet.ID}
+				transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Any()).Times(1).Return(database.LikeRelations{}, nil)
+				transaction.EXPECT().UnlikeTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Follow user",
+			method: http.MethodPut,
+			url:    fmt.Sprintf("/api/v1/users/%v/follow", other.Username),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.FollowInputArgs{Username: user.Username, FollowUser: other.Username}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().FollowTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.FollowInputResult{}, nil)
+				transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(database.NotificationPreferences{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Followers of a user",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/api/v1/users/%v/followers?limit=5", other.Username),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListFollowersParams{Username: other.Username, BeforeID: math.MaxInt64, PageSize: 5}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().ListFollowers(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.ListFollowersRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				noDeprecation(t, recorder)
+			},
+		},
+		{
+			name:   "Followers of a protected user",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/api/v1/users/%v/followers", other.Username),
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(protectedOther, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().ListFollowers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Following of a missing user",
+			method: http.MethodGet,
+			url:    "/api/v1/users/nobody/following",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq("nobody")).Times(1).Return(database.Users{}, sql.ErrNoRows)
+				transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Unversioned alias",
+			method: http.MethodGet,
+			url:    "/profile",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get("Deprecation"))
+				require.Equal(t, `</api/v1/profile>; rel="successor-version"`, recorder.Header().Get("Link"))
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		var body bytes.Buffer
+		if testcase.body != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(testcase.body))
+		}
+		req, err := http.NewRequest(testcase.method, testcase.url, &body)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/stream"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	eventTweet = "tweet"
+	eventLike = "like"
+	eventFollow = "follow"
+	eventNotification = "notification"
+	eventMessage = "message"
+	eventMessageRead = "message_read"
+	eventEnvelope = "envelope"
+
+	// followingsPageSize is how many followings are read at once to subscribe to them
+	followingsPageSize = 1000
+)
+
+// tweetsTopic carries the tweets a user posts, likesTopic the likes on their
+// tweets and followersTopic their new followers. Likes and follows are published
+// only when their notification preferences let the notification through.
+func tweetsTopic(username string) string { return "tweets:" + username }
+func likesTopic(username string) string { return "likes:" + username }
+func followersTopic(username string) string { return "followers:" + username }
+
+type likeEvent struct {
+	Username string `json:"username"`
+	TweetID int64 `json:"tweet_id"`
+}
+
+type followEvent struct {
+	Username string `json:"username"`
+}
+
+// followedUsernames is everyone username follows
+func (s *Server) followedUsernames(c *gin.Context, username string) ([]string, error) {
+	usernames := []string{}
+	arg := database.ListFollowingParams{
+		Username: username,
+		BeforeID: math.MaxInt64,
+		PageSize: followingsPageSize,
+	}
+	for {
+		followings, err := s.transaction.ListFollowing(c, arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, following := range followings {
+			usernames = append(usernames, following.Username)
+		}
+		if len(followings) < followingsPageSize {
+			return usernames, nil
+		}
+		arg.BeforeID = followings[len(followings)-1].RelationID
+	}
+}
+
+// homeTopics are the topics of the home stream of username: tweets of the people
+// they follow and their own, likes on their tweets and their new followers
+func (s *Server) homeTopics(c *gin.Context, username string) ([]string, error) {
+	followed, err := s.followedUsernames(c, username)
+	if err != nil {
+		return nil, err
+	}
+	topics := []string{tweetsTopic(username), likesTopic(username), followersTopic(username)}
+	for _, followedUsername := range followed {
+		topics = append(topics, tweetsTopic(followedUsername))
+	}
+	return topics, nil
+}
+
+// homeStreams tracks the live home subscriptions of every user, a follow or an
+// unfollow changes the tweets they receive right away
+type homeStreams struct {
+	mu   sync.Mutex
+	subs map[string]map[*stream.Subscription]struct{}
+}
+
+// add keeps sub until it ends
+func (h *homeStreams) add(username string, sub *stream.Subscription) {
+	h.mu.Lock()
+	if h.subs == nil {
+		h.subs = map[string]map[*stream.Subscription]struct{}{}
+	}
+	if h.subs[username] == nil {
+		h.subs[username] = map[*stream.Subscription]struct{}{}
+	}
+	h.subs[username][sub] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-sub.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[username], sub)
+		if len(h.subs[username]) == 0 {
+			delete(h.subs, username)
+		}
+	}()
+}
+
+func (h *homeStreams) follow(username, followed string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[username] {
+		sub.Add(tweetsTopic(followed))
+	}
+}
+
+func (h *homeStreams) unfollow(username, followed string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[username] {
+		sub.Remove(tweetsTopic(followed))
+	}
+}
+
+// subscribeHome subscribes to the home stream of username, kept up to date with
+// the people they follow
+func (s *Server) subscribeHome(c *gin.Context, username string, lastEventID uint64) (*stream.Subscription, error) {
+	topics, err := s.homeTopics(c, username)
+	if err != nil {
+		return nil, err
+	}
+	sub := s.hub.Subscribe(topics, lastEventID)
+	s.homes.add(username, sub)
+	return sub, nil
+}
+
+type StreamRequest struct {
+	// LastEventID resumes like the Last-Event-ID header, for clients that can't set it
+	LastEventID uint64 `form:"last_event_id"`
+}
+
+// StreamHome pushes the caller's home events as Server-Sent Events. A reconnecting
+// client gets what it missed after Last-Event-ID, or a reset event when that's
+// too old and it should reload the timeline. A client too slow to keep up is sent
+// a drop event and disconnected.
+func (s *Server) StreamHome(c *gin.Context) {
+	var req StreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrResponse("Last-Event-ID is invalid"))
+			return
+		}
+		req.LastEventID = id
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	sub, err := s.subscribeHome(c, authHeader.Username, req.LastEventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	//tell nginx not to buffer the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if sub.Missed() {
+		c.Render(-1, sse.Event{Event: "reset", Data: "events were missed, reload the timeline"})
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(s.config.Stream_Heartbeat_Interval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-sub.Events():
+			c.Render(-1, sse.Event{Id: strconv.FormatUint(event.ID, 10), Event: event.Type, Data: event.Data})
+			return true
+		case <-heartbeat.C:
+			//a comment line keeps proxies from closing an idle connection
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case <-sub.Done():
+			if sub.Err() == stream.ErrSlowConsumer {
+				c.Render(-1, sse.Event{Event: "drop", Data: sub.Err().Error()})
+			}
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
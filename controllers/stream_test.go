@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type sseMessage struct {
+	ID      string
+	Event   string
+	Data    string
+	Comment bool
+}
+
+// readSSE reads the next message or comment of an event stream
+func readSSE(t *testing.T, reader *bufio.Reader) sseMessage {
+	var message sseMessage
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return message
+		case strings.HasPrefix(line, ":"):
+			message.Comment = true
+		case strings.HasPrefix(line, "id:"):
+			message.ID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "event:"):
+			message.Event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			message.Data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+func openStream(t *testing.T, server *Server, url, username, lastEventID string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, url+"/api/v1/stream/home", nil)
+	require.NoError(t, err)
+	AddAuth(t, req, server.paseto, authorizationTypeBearer, username, time.Minute)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestStreamHome(t *testing.T) {
+	user, _ := randomUser(t)
+	followed, _ := randomUser(t)
+	stranger, _ := randomUser(t)
+	fan, _ := randomUser(t)
+	tweet := randomTweets(user)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Any()).AnyTimes().
+		Return([]database.ListFollowingRow{{RelationID: 1, Username: followed.Username}}, nil)
+	transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+	transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Any()).Times(1).Return(database.LikeRelations{}, sql.ErrNoRows)
+	transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Any()).Times(1).Return(tweet, nil)
+	transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(database.NotificationPreferences{}, sql.ErrNoRows)
+
+	server := NewTestServer(t, transaction)
+	server.config.Stream_Heartbeat_Interval = 50 * time.Millisecond
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	//published before connecting, replayed after the Last-Event-ID
+	server.hub.Publish(tweetsTopic(followed.Username), eventTweet, "already seen")
+	server.hub.Publish(tweetsTopic(followed.Username), eventTweet, tweetResponse{ID: 9, Username: followed.Username})
+	server.hub.Publish(tweetsTopic(stranger.Username), eventTweet, "not followed")
+
+	resp := openStream(t, server, httpServer.URL, user.Username, "1")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+
+	message := readSSE(t, reader)
+	require.Equal(t, "2", message.ID)
+	require.Equal(t, eventTweet, message.Event)
+	var posted tweetResponse
+	require.NoError(t, json.Unmarshal([]byte(message.Data), &posted))
+	require.Equal(t, int64(9), posted.ID)
+	require.Equal(t, followed.Username, posted.Username)
+
+	//a like through the api reaches the author's stream
+	likeReq, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/api/v1/tweets/%v/like", httpServer.URL, tweet.ID), nil)
+	require.NoError(t, err)
+	AddAuth(t, likeReq, server.paseto, authorizationTypeBearer, fan.Username, time.Minute)
+	likeResp, err := http.DefaultClient.Do(likeReq)
+	require.NoError(t, err)
+	likeResp.Body.Close()
+	require.Equal(t, http.StatusOK, likeResp.StatusCode)
+
+	//the like's notification push took 4
+	message = readSSE(t, reader)
+	require.Equal(t, "5", message.ID)
+	require.Equal(t, eventLike, message.Event)
+	var like likeEvent
+	require.NoError(t, json.Unmarshal([]byte(message.Data), &like))
+	require.Equal(t, likeEvent{Username: fan.Username, TweetID: tweet.ID}, like)
+
+	//nothing else happens, heartbeats keep the connection open
+	require.True(t, readSSE(t, reader).Comment)
+
+	server.hub.Publish(followersTopic(user.Username), eventFollow, followEvent{Username: fan.Username})
+	for message = readSSE(t, reader); message.Comment; message = readSSE(t, reader) {
+	}
+	//ids are hub wide
+	require.Equal(t, "6", message.ID)
+	require.Equal(t, eventFollow, message.Event)
+
+	//resuming from an id this hub never gave out asks for a reload
+	resumed := openStream(t, server, httpServer.URL, user.Username, "100")
+	defer resumed.Body.Close()
+	require.Equal(t, "reset", readSSE(t, bufio.NewReader(resumed.Body)).Event)
+
+	invalid := openStream(t, server, httpServer.URL, user.Username, "abc")
+	defer invalid.Body.Close()
+	require.Equal(t, http.StatusBadRequest, invalid.StatusCode)
+}
+
+func TestStreamHomeFollowsLive(t *testing.T) {
+	user, _ := randomUser(t)
+	followed, _ := randomUser(t)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Any()).Times(1).Return([]database.ListFollowingRow{}, nil)
+	transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followed.Username)).Times(2).Return(followed, nil)
+	transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	gomock.InOrder(
+		transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows),
+		transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, nil),
+	)
+	transaction.EXPECT().FollowTx(gomock.Any(), gomock.Any()).Times(1).Return(database.FollowInputResult{}, nil)
+	transaction.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(followed.Username)).Times(1).Return(database.NotificationPreferences{}, sql.ErrNoRows)
+	transaction.EXPECT().UnfollowTx(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+	server := NewTestServer(t, transaction)
+	server.config.Stream_Heartbeat_Interval = 50 * time.Millisecond
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	resp := openStream(t, server, httpServer.URL, user.Username, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	reader := bufio.NewReader(resp.Body)
+
+	relation := func(method string) {
+		req, err := http.NewRequest(method, fmt.Sprintf("%v/api/v1/users/%v/follow", httpServer.URL, followed.Username), nil)
+		require.NoError(t, err)
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		relationResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		relationResp.Body.Close()
+		require.Equal(t, http.StatusOK, relationResp.StatusCode)
+	}
+
+	//the open stream picks up a follow made after it connected
+	relation(http.MethodPut)
+	posted := server.hub.Publish(tweetsTopic(followed.Username), eventTweet, tweetResponse{ID: 9, Username: followed.Username})
+	message := readSSE(t, reader)
+	for message.Comment {
+		message = readSSE(t, reader)
+	}
+	require.Equal(t, fmt.Sprint(posted.ID), message.ID)
+
+	//and drops the tweets of someone unfollowed
+	relation(http.MethodDelete)
+	server.hub.Publish(tweetsTopic(followed.Username), eventTweet, tweetResponse{ID: 10, Username: followed.Username})
+	require.True(t, readSSE(t, reader).Comment)
+	require.True(t, readSSE(t, reader).Comment)
+}
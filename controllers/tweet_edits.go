@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+)
+
+type tweetURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type EditTweetRequest struct {
+	Tweet string `json:"tweet" binding:"required"`
+}
+
+func (s *Server) EditTweet(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	var req EditTweetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	tweet, err := s.transaction.EditTweetTx(c, database.EditTweetTxParams{
+		ID:         uri.ID,
+		Username:   authHeader.Username,
+		Tweet:      req.Tweet,
+		EditWindow: s.config.Tweet_Edit_Window,
+	})
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+		case database.ErrNotTweetOwner:
+			c.JSON(http.StatusForbidden, ErrResponse(err.Error()))
+		case database.ErrEditWindowClosed:
+			c.JSON(http.StatusConflict, ErrResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		}
+		return
+	}
+
+	resp, err := s.tweetResponses(c, []database.Tweets{tweet})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp[0])
+}
+
+type tweetRevisionResponse struct {
+	Tweet      string    `json:"tweet"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type tweetHistoryResponse struct {
+	Tweet     tweetResponse           `json:"tweet"`
+	Revisions []tweetRevisionResponse `json:"revisions"`
+}
+
+func (s *Server) GetTweetHistory(c *gin.Context) {
+	var uri tweetURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	tweet, _, status, err := s.visibleTweet(c, uri.ID, callerUsername(c))
+	if err != nil {
+		c.JSON(status, ErrResponse(err.Error()))
+		return
+	}
+
+	current, err := s.tweetResponses(c, []database.Tweets{tweet})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	revisions, err := s.transaction.ListTweetRevisions(c, uri.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := tweetHistoryResponse{
+		Tweet:     current[0],
+		Revisions: []tweetRevisionResponse{},
+	}
+	for _, r := range revisions {
+		resp.Revisions = append(resp.Revisions, tweetRevisionResponse{
+			Tweet:      r.Tweet,
+			CreatedAt:  r.CreatedAt,
+			ReplacedAt: r.ReplacedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditTweet(t *testing.T) {
+	user, _ := randomUser(t)
+	tweet := randomTweets(user)
+
+	edited := tweet
+	edited.Tweet = "edited tweet"
+	edited.EditedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	arg := database.EditTweetTxParams{
+		ID:         tweet.ID,
+		Username:   user.Username,
+		Tweet:      edited.Tweet,
+		EditWindow: time.Hour,
+	}
+
+	testcases := []struct {
+		name          string
+		id            string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   fmt.Sprint(tweet.ID),
+			body: gin.H{"tweet": edited.Tweet},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().EditTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(edited, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp tweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.True(t, resp.Edited)
+				require.Equal(t, edited.Tweet, resp.Tweet)
+			},
+		},
+		{
+			name: "Bad id",
+			id:   "abc",
+			body: gin.H{"tweet": edited.Tweet},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().EditTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Not found",
+			id:   fmt.Sprint(tweet.ID),
+			body: gin.H{"tweet": edited.Tweet},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().EditTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Not owner",
+			id:   fmt.Sprint(tweet.ID),
+			body: gin.H{"tweet": edited.Tweet},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().EditTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.Tweets{}, database.ErrNotTweetOwner)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Window closed",
+			id:   fmt.Sprint(tweet.ID),
+			body: gin.H{"tweet": edited.Tweet},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().EditTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.Tweets{}, database.ErrEditWindowClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPut, "/tweets/"+testcase.id, bytes.NewReader(data))
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestGetTweetHistory(t *testing.T) {
+	user, _ := randomUser(t)
+	tweet := randomTweets(user)
+	tweet.EditedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	author, _ := randomUser(t)
+	author.Protected = true
+	protectedTweet := randomTweets(author)
+	protectedTweet.ID = 7
+
+	revisions := []database.TweetRevisions{
+		{ID: 2, TweetID: tweet.ID, Tweet: "second", CreatedAt: time.Now().Add(-time.Minute), ReplacedAt: time.Now()},
+		{ID: 1, TweetID: tweet.ID, Tweet: "first", CreatedAt: tweet.CreatedAt, ReplacedAt: time.Now().Add(-time.Minute)},
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+	transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Media{}, nil)
+	transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+	transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+	transaction.EXPECT().ListTweetRevisions(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(revisions, nil)
+	transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(99))).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+	//the history of a protected tweet is hidden like the tweet
+	transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(protectedTweet.ID)).Times(1).Return(protectedTweet, nil)
+	transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+	transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+	transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+	transaction.EXPECT().ListTweetRevisions(gomock.Any(), gomock.Eq(protectedTweet.ID)).Times(0)
+
+	server := NewTestServer(t, transaction)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/tweets/%v/history", tweet.ID), nil)
+	require.NoError(t, err)
+	AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp tweetHistoryResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	require.True(t, resp.Tweet.Edited)
+	require.Len(t, resp.Revisions, 2)
+	require.Equal(t, "second", resp.Revisions[0].Tweet)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodGet, "/tweets/99/history", nil)
+	require.NoError(t, err)
+	AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("/tweets/%v/history", protectedTweet.ID), nil)
+	require.NoError(t, err)
+	AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"net/http"
-	"sort"
+	"time"
 
 	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
 	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
@@ -13,6 +15,150 @@ import (
 
 type CreateTweetRequest struct {
 	Tweet string `json:"tweet" binding:"required"`
+	MediaIDs []int64 `json:"media_ids" binding:"max=4"`
+	Poll *PollRequest `json:"poll"`
+	InReplyToID int64 `json:"in_reply_to_id" binding:"omitempty,min=1"`
+}
+
+type tweetResponse struct {
+	ID int64 `json:"id"`
+	Tweet string `json:"tweet"`
+	Username string `json:"username"`
+	Likes int32 `json:"likes"`
+	CreatedAt time.Time `json:"created_at"`
+	Edited bool `json:"edited"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	Media []mediaResponse `json:"media"`
+	Poll *pollResponse `json:"poll,omitempty"`
+	Bookmarked bool `json:"bookmarked"`
+	Pinned bool `json:"pinned,omitempty"`
+	InReplyToID *int64 `json:"in_reply_to_id,omitempty"`
+	RetweetOfID *int64 `json:"retweet_of_id,omitempty"`
+}
+
+func newTweetResponse(tweet database.Tweets, media []database.Media) tweetResponse {
+	resp := tweetResponse{
+		ID: tweet.ID,
+		Tweet: tweet.Tweet,
+		Username: tweet.Username,
+		Likes: tweet.Likes.Int32,
+		CreatedAt: tweet.CreatedAt,
+		Media: []mediaResponse{},
+	}
+	if tweet.EditedAt.Valid {
+		resp.Edited = true
+		resp.EditedAt = &tweet.EditedAt.Time
+	}
+	if tweet.InReplyToID.Valid {
+		resp.InReplyToID = &tweet.InReplyToID.Int64
+	}
+	if tweet.RetweetOfID.Valid {
+		resp.RetweetOfID = &tweet.RetweetOfID.Int64
+	}
+	for _, m := range media {
+		resp.Media = append(resp.Media, newMediaResponse(m))
+	}
+	return resp
+}
+
+// tweetResponses loads what's attached to the tweets with one query per kind instead of per tweet
+func (s *Server) tweetResponses(c *gin.Context, tweets []database.Tweets) ([]tweetResponse, error) {
+	resp := []tweetResponse{}
+	if len(tweets) == 0 {
+		return resp, nil
+	}
+
+	ids := make([]int64, len(tweets))
+	for i, tweet := range tweets {
+		ids[i] = tweet.ID
+	}
+
+	media, err := s.transaction.ListMediaByTweetIDs(c, ids)
+	if err != nil {
+		return nil, err
+	}
+	mediaByTweet := map[int64][]database.Media{}
+	for _, m := range media {
+		mediaByTweet[m.TweetID.Int64] = append(mediaByTweet[m.TweetID.Int64], m)
+	}
+
+	polls, err := s.tweetPolls(c, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	//bookmarks are private, only the caller's own are looked up
+	bookmarked := map[int64]bool{}
+	if username := callerUsername(c); username != "" {
+		bookmarkedIDs, err := s.transaction.ListBookmarkedTweetIDs(c, database.ListBookmarkedTweetIDsParams{
+			Username: username,
+			TweetIds: ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range bookmarkedIDs {
+			bookmarked[id] = true
+		}
+	}
+
+	for _, tweet := range tweets {
+		tweetResp := newTweetResponse(tweet, mediaByTweet[tweet.ID])
+		if poll, ok := polls[tweet.ID]; ok {
+			tweetResp.Poll = &poll
+		}
+		tweetResp.Bookmarked = bookmarked[tweet.ID]
+		resp = append(resp, tweetResp)
+	}
+	return resp, nil
+}
+
+// tweetPolls returns the polls of the given tweets keyed by tweet ID, as seen by the caller
+func (s *Server) tweetPolls(c *gin.Context, tweetIDs []int64) (map[int64]pollResponse, error) {
+	resp := map[int64]pollResponse{}
+
+	polls, err := s.transaction.ListPollsByTweetIDs(c, tweetIDs)
+	if err != nil || len(polls) == 0 {
+		return resp, err
+	}
+
+	pollIDs := make([]int64, len(polls))
+	for i, poll := range polls {
+		pollIDs[i] = poll.ID
+	}
+
+	options, err := s.transaction.ListPollOptionsByPollIDs(c, pollIDs)
+	if err != nil {
+		return nil, err
+	}
+	optionsByPoll := map[int64][]database.PollOptions{}
+	for _, option := range options {
+		optionsByPoll[option.PollID] = append(optionsByPoll[option.PollID], option)
+	}
+
+	votesByPoll := map[int64]database.PollVotes{}
+	if username := callerUsername(c); username != "" {
+		votes, err := s.transaction.ListPollVotesByUser(c, database.ListPollVotesByUserParams{
+			Username: username,
+			PollIds: pollIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, vote := range votes {
+			votesByPoll[vote.PollID] = vote
+		}
+	}
+
+	now := time.Now()
+	for _, poll := range polls {
+		var vote *database.PollVotes
+		if v, ok := votesByPoll[poll.ID]; ok {
+			vote = &v
+		}
+		resp[poll.TweetID] = newPollResponse(database.TweetPoll{Poll: poll, Options: optionsByPoll[poll.ID]}, vote, now)
+	}
+	return resp, nil
 }
 
 func (s *Server) CreateTweet(c *gin.Context) {
@@ -24,32 +170,87 @@ func (s *Server) CreateTweet(c *gin.Context) {
 
 	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	arg := database.CreateTweetParams{
+	arg := database.CreateTweetTxParams{
 		Username: authHeader.Username,
 		Tweet: req.Tweet,
+		MediaIDs: req.MediaIDs,
 	}
-	createdTweet, err := s.transaction.CreateTweet(c, arg)
+	if req.InReplyToID != 0 {
+		//only tweets the caller can see can be replied to
+		parent, _, status, err := s.visibleTweet(c, req.InReplyToID, authHeader.Username)
+		if err != nil {
+			c.JSON(status, ErrResponse(err.Error()))
+			return
+		}
+		arg.InReplyToID = sql.NullInt64{Int64: parent.ID, Valid: true}
+	}
+	if req.Poll != nil {
+		arg.Poll = &database.CreatePollTxParams{
+			Options: req.Poll.Options,
+			Duration: time.Duration(req.Poll.DurationMinutes) * time.Minute,
+		}
+	}
+	created, err := s.transaction.CreateTweetTx(c, arg)
 	if err != nil {
+		if err == database.ErrMediaUnavailable {
+			c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, createdTweet)
+	c.JSON(http.StatusOK, s.publishTweet(c, created))
+}
+
+// PublishTweet streams a tweet published outside of a request, like a scheduled one,
+// and pushes the notifications it triggered
+func (s *Server) PublishTweet(ctx context.Context, created database.CreateTweetTxResult) {
+	s.publishTweet(ctx, created)
+}
+
+// publishTweet is what follows every committed tweet : it's streamed to the author's
+// and the replied tweet's subscribers and its replies and mentions are pushed
+func (s *Server) publishTweet(ctx context.Context, created database.CreateTweetTxResult) tweetResponse {
+	resp := newTweetResponse(created.Tweet, created.Media)
+	if created.Poll != nil {
+		poll := newPollResponse(*created.Poll, nil, time.Now())
+		resp.Poll = &poll
+	}
+	s.hub.Publish(tweetsTopic(created.Tweet.Username), eventTweet, resp)
+	if created.Tweet.InReplyToID.Valid {
+		s.hub.Publish(repliesTopic(created.Tweet.InReplyToID.Int64), eventTweet, resp)
+	}
+	s.pushTweetNotifications(ctx, created)
+	return resp
 }
 
 type DeleteGetAndLikeTweetRequest struct {
 	ID int64 `json:"id" binding:"required"`
 }
 
-func (s *Server) DeleteTweet(c *gin.Context) {
+// bindTweetID reads the tweet id from the path on /api/v1, or from the JSON body
+// on the deprecated routes
+func bindTweetID(c *gin.Context) (int64, error) {
+	if c.Param("id") != "" {
+		var uri tweetURI
+		err := c.ShouldBindUri(&uri)
+		return uri.ID, err
+	}
 	var req DeleteGetAndLikeTweetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	err := c.ShouldBindJSON(&req)
+	return req.ID, err
+}
+
+func (s *Server) DeleteTweet(c *gin.Context) {
+	id, err := bindTweetID(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
 		return
 	}
 
 	//check if tweet exist
-	_, err := s.transaction.GetTweet(c, req.ID)
+	tweet, err := s.transaction.GetTweet(c, id)
 	if err != nil {
 		if err == sql.ErrNoRows{
 			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
@@ -59,56 +260,70 @@ func (s *Server) DeleteTweet(c *gin.Context) {
 		return
 	}
 
-	err = s.transaction.DeleteTweet(c, req.ID)
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if tweet.Username != authHeader.Username {
+		c.JSON(http.StatusForbidden, ErrResponse("only your own tweets can be deleted"))
+		return
+	}
+
+	err = s.transaction.DeleteTweet(c, id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"Message" : fmt.Sprintf("Tweet with ID %v has succesfully been deleted", req.ID),
+		"Message" : fmt.Sprintf("Tweet with ID %v has succesfully been deleted", id),
 	})
 }
 
 func (s *Server) GetTweet(c *gin.Context) {
-	var req DeleteGetAndLikeTweetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	id, err := bindTweetID(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
 		return
 	}
 
-	tweet, err := s.transaction.GetTweet(c, req.ID)
+	tweet, _, status, err := s.visibleTweet(c, id, callerUsername(c))
+	if err != nil {
+		c.JSON(status, ErrResponse(err.Error()))
+		return
+	}
+
+	resp, err := s.tweetResponses(c, []database.Tweets{tweet})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
-			return
-		}
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, tweet)
+	c.JSON(http.StatusOK, resp[0])
 }
 
 //TODO : SHOULD IMPLEMENT TRANSACTION ISOLATIONS
 func (s *Server) LikeTweet(c *gin.Context) {
-	var req DeleteGetAndLikeTweetRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
+	id, err := bindTweetID(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
 		return
 	}
 
 	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
 
+	//tweets the user can't see can't be liked either
+	_, _, status, err := s.visibleTweet(c, id, authHeader.Username)
+	if err != nil {
+		c.JSON(status, ErrResponse(err.Error()))
+		return
+	}
+
 	//make sure user hasn't liked the tweet
-	_, err := s.transaction.GetLikeRelation(c, database.GetLikeRelationParams{
+	_, err = s.transaction.GetLikeRelation(c, database.GetLikeRelationParams{
 		Username: authHeader.Username,
-		TweetID: req.ID,
+		TweetID: id,
 	})
 	if err != sql.ErrNoRows {
 		c.JSON(http.StatusCreated, gin.H{
-			"error" : fmt.Sprintf("%v has already liked tweet %v", authHeader.Username, req.ID),
+			"error" : fmt.Sprintf("%v has already liked tweet %v", authHeader.Username, id),
 		})
 		return
 	}
@@ -116,23 +331,30 @@ func (s *Server) LikeTweet(c *gin.Context) {
 	//TRANSACTION
 	txArg := database.CreateLikeRelationParams{
 		Username: authHeader.Username,
-		TweetID: req.ID,
+		TweetID: id,
 	}
-	err = s.transaction.LikeTweetTx(c, txArg)
+	tweet, err := s.transaction.LikeTweetTx(c, txArg)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
+	//the live like goes out only when its notification does, so it obeys the same preferences
+	if tweet.Username != authHeader.Username && s.pushNotification(c, tweet.Username, authHeader.Username, database.NotificationLike, tweet.ID) {
+		s.hub.Publish(likesTopic(tweet.Username), eventLike, likeEvent{Username: authHeader.Username, TweetID: tweet.ID})
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("%v liked tweet %v", authHeader.Username, req.ID),
+		"message": fmt.Sprintf("%v liked tweet %v", authHeader.Username, id),
 	})
 }
 
 func (s *Server) UnlikeTweet(c *gin.Context) {
-	var req DeleteGetAndLikeTweetRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
+	id, err := bindTweetID(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
 		return
 	}
@@ -140,20 +362,20 @@ func (s *Server) UnlikeTweet(c *gin.Context) {
 	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
 
 	//make sure user has liked the tweet
-	_, err := s.transaction.GetLikeRelation(c, database.GetLikeRelationParams{
+	_, err = s.transaction.GetLikeRelation(c, database.GetLikeRelationParams{
 		Username: authHeader.Username,
-		TweetID: req.ID,
+		TweetID: id,
 	})
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error" : fmt.Sprintf("%v hasn't liked tweet %v", authHeader.Username, req.ID),
+			"error" : fmt.Sprintf("%v hasn't liked tweet %v", authHeader.Username, id),
 		})
 		return
 	}
 
 	txArg := database.DeleteLikeRelationParams{
 		Username: authHeader.Username,
-		TweetID: req.ID,
+		TweetID: id,
 	}
 	err = s.transaction.UnlikeTweetTx(c, txArg)
 	if err != nil {
@@ -162,44 +384,79 @@ func (s *Server) UnlikeTweet(c *gin.Context) {
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("%v unliked tweet %v", authHeader.Username, req.ID),
+		"message": fmt.Sprintf("%v unliked tweet %v", authHeader.Username, id),
 	})
 }
 
+// FeedsRequest pages through the home timeline with cursors. max_id (inclusive) and
+// since_id are still honored on the first page for clients that page by tweet ids.
+type FeedsRequest struct {
+	PageRequest
+	MaxID int64 `form:"max_id" binding:"omitempty,min=1"`
+	SinceID int64 `form:"since_id" binding:"omitempty,min=1"`
+}
+
+// GetFeeds is the caller's home timeline, their own tweets and the ones of the users
+// they follow, newest first. It's read from the materialized timeline, tweets of
+// celebrity accounts are merged in at read time.
 func (s *Server) GetFeeds(c *gin.Context) {
+	var req FeedsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
 	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	list := "feeds:" + authHeader.Username
 
-	var feeds []database.Tweets
+	p, err := s.newPage(list, req.PageRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if p.first {
+		if req.MaxID != 0 && req.MaxID < math.MaxInt64 {
+			p.BeforeID = req.MaxID + 1
+		}
+		p.AfterID = req.SinceID
+	}
 
-	// get following list
-	relations, err := s.transaction.GetFollowing(c, database.GetFollowingParams{
-		FollowerUsername: authHeader.Username,
-		Limit: 10000,
-		Offset: 1,
+	feeds, err := s.transaction.GetMaterializedHomeTimeline(c, database.GetMaterializedHomeTimelineParams{
+		Username: authHeader.Username,
+		AfterID: p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse: p.Reverse,
+		PageSize: p.Limit,
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
-
-	for _, relation := range relations {
-		// get tweets list from each following user
-		tweets, err := s.transaction.GetListTweets(c,database.GetListTweetsParams{
-			Username: relation.FollowedUsername,
-			Limit: 100,
-			Offset: 1,
-		})
-		if err != nil {	
-			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
-			return
+	//walking back comes oldest first
+	if p.Reverse {
+		for i, j := 0, len(feeds)-1; i < j; i, j = i+1, j-1 {
+			feeds[i], feeds[j] = feeds[j], feeds[i]
 		}
-		feeds = append(feeds, tweets...)
 	}
 
-	// sort feeds by recent tweets
-	sort.Slice(feeds, func(i, j int) bool {
-		return feeds[i].ID > feeds[j].ID
-	})
+	keys := make([]int64, len(feeds))
+	for i, tweet := range feeds {
+		keys[i] = tweet.ID
+	}
+	//the cursors follow the timeline rows, hidden tweets only leave a page shorter
+	feeds, err = s.visibleTweets(c, feeds, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := timelineResponse{}
+	resp.Tweets, err = s.tweetResponses(c, feeds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
 
-	c.JSON(http.StatusOK, feeds)
+	c.JSON(http.StatusOK, resp)
 }
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -30,6 +31,7 @@ func randomTweets(user database.Users) database.Tweets {
 
 func TestCreateTweet(t *testing.T) {
 	user, _ := randomUser(t)
+	author, _ := randomUser(t)
 	tweet := randomTweets(user)
 
 	testcases := []struct{
@@ -48,16 +50,85 @@ func TestCreateTweet(t *testing.T) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				arg := database.CreateTweetParams{
+				arg := database.CreateTweetTxParams{
 					Tweet: tweet.Tweet,
 					Username: user.Username,
 				}
-				transaction.EXPECT().CreateTweet(gomock.Any(), gomock.Eq(arg)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.CreateTweetTxResult{Tweet: tweet}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "Reply",
+			body: gin.H{
+				"tweet" : tweet.Tweet,
+				"in_reply_to_id" : 7,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				parent := database.Tweets{ID: 7, Username: author.Username}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(parent, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				arg := database.CreateTweetTxParams{
+					Tweet: tweet.Tweet,
+					Username: user.Username,
+					InReplyToID: sql.NullInt64{Int64: 7, Valid: true},
+				}
+				reply := tweet
+				reply.InReplyToID = arg.InReplyToID
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.CreateTweetTxResult{Tweet: reply}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp tweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.NotNil(t, resp.InReplyToID)
+				require.Equal(t, int64(7), *resp.InReplyToID)
+			},
+		},
+		{
+			name: "Reply to missing tweet",
+			body: gin.H{
+				"tweet" : tweet.Tweet,
+				"in_reply_to_id" : 7,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Reply when blocked",
+			body: gin.H{
+				"tweet" : tweet.Tweet,
+				"in_reply_to_id" : 7,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				parent := database.Tweets{ID: 7, Username: author.Username}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(parent, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "Bad Request",
 			body: gin.H{
@@ -67,7 +138,7 @@ func TestCreateTweet(t *testing.T) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				transaction.EXPECT().CreateTweet(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -82,16 +153,82 @@ func TestCreateTweet(t *testing.T) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				arg := database.CreateTweetParams{
+				arg := database.CreateTweetTxParams{
 					Tweet: tweet.Tweet,
 					Username: user.Username,
 				}
-				transaction.EXPECT().CreateTweet(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.Tweets{}, sql.ErrConnDone)
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.CreateTweetTxResult{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "With media",
+			body: gin.H{
+				"tweet" : tweet.Tweet,
+				"media_ids" : []int64{7, 8},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.CreateTweetTxParams{
+					Tweet: tweet.Tweet,
+					Username: user.Username,
+					MediaIDs: []int64{7, 8},
+				}
+				result := database.CreateTweetTxResult{
+					Tweet: tweet,
+					Media: []database.Media{
+						{ID: 7, MimeType: "image/png", ThumbnailKey: "7_thumb.jpg"},
+						{ID: 8, MimeType: "video/mp4"},
+					},
+				}
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp tweetResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Media, 2)
+				require.Equal(t, "/media/7/thumbnail", resp.Media[0].ThumbnailURL)
+				require.Empty(t, resp.Media[1].ThumbnailURL)
+			},
+		},
+		{
+			name: "Too many media",
+			body: gin.H{
+				"tweet" : tweet.Tweet,
+				"media_ids" : []int64{1, 2, 3, 4, 5},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Media unavailable",
+			body: gin.H{
+				"tweet" : tweet.Tweet,
+				"media_ids" : []int64{9},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateTweetTx(gomock.Any(), gomock.Any()).Times(1).Return(database.CreateTweetTxResult{}, database.ErrMediaUnavailable)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 	}
 
 	for _, testcase := range testcases {
@@ -124,6 +261,8 @@ func TestCreateTweet(t *testing.T) {
 func TestDeleteTweet(t *testing.T) {
 	user, _ := randomUser(t)
 	tweet := randomTweets(user)
+	other, _ := randomUser(t)
+	otherTweet := randomTweets(other)
 
 	testcases := []struct{
 		name string
@@ -148,6 +287,22 @@ func TestDeleteTweet(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "Tweet of another user",
+			body: gin.H{
+				"id" : 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(otherTweet.ID)).Times(1).Return(otherTweet, nil)
+				transaction.EXPECT().DeleteTweet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "Bad Request",
 			body: gin.H{
@@ -244,6 +399,9 @@ func TestDeleteTweet(t *testing.T) {
 func TestGetTweet(t *testing.T) {
 	user, _ := randomUser(t)
 	tweet := randomTweets(user)
+	author, _ := randomUser(t)
+	author.Protected = true
+	authorTweet := randomTweets(author)
 
 	testcases := []struct{
 		name string
@@ -262,6 +420,10 @@ func TestGetTweet(t *testing.T) {
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
 				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{tweet.ID})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Eq([]int64{tweet.ID})).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -297,6 +459,51 @@ func TestGetTweet(t *testing.T) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
+		{
+			name: "Blocked by the author",
+			body: gin.H{
+				"id" : 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				blockArg := database.IsBlockedParams{
+					BlockerUsername: author.Username,
+					BlockedUsername: user.Username,
+				}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(authorTweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(blockArg)).Times(1).Return(true, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Protected author not followed",
+			body: gin.H{
+				"id" : 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				relationArg := database.GetRelationsParams{
+					FollowerUsername: user.Username,
+					FollowedUsername: author.Username,
+				}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(authorTweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Eq(relationArg)).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "Internal server error",
 			body: gin.H{
@@ -344,6 +551,9 @@ func TestGetTweet(t *testing.T) {
 func TestLikeTweet(t *testing.T) {
 	user, _ := randomUser(t)
 	tweet := randomTweets(user)
+	author, _ := randomUser(t)
+	author.Protected = true
+	authorTweet := randomTweets(author)
 
 	testcases := []struct{
 		name string
@@ -361,6 +571,8 @@ func TestLikeTweet(t *testing.T) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
 				getArg := database.GetLikeRelationParams{
 					Username: user.Username,
 					TweetID: tweet.ID,
@@ -370,7 +582,7 @@ func TestLikeTweet(t *testing.T) {
 					TweetID: tweet.ID,
 				}
 				transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Eq(getArg)).Times(1).Return(database.LikeRelations{}, sql.ErrNoRows)
-				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Eq(createArg)).Times(1).Return(nil)
+				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Eq(createArg)).Times(1).Return(tweet, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -409,6 +621,8 @@ func TestLikeTweet(t *testing.T) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
 				getArg := database.GetLikeRelationParams{
 					Username: user.Username,
 					TweetID: tweet.ID,
@@ -424,6 +638,66 @@ func TestLikeTweet(t *testing.T) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
+		{
+			name: "Tweet not found",
+			body: gin.H{
+				"id" : 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+				transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Blocked by the author",
+			body: gin.H{
+				"id" : 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				blockArg := database.IsBlockedParams{
+					BlockerUsername: author.Username,
+					BlockedUsername: user.Username,
+				}
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(authorTweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(blockArg)).Times(1).Return(true, nil)
+				transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Protected author not followed",
+			body: gin.H{
+				"id" : 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(authorTweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(1).Return(author, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "Internal server error",
 			body: gin.H{
@@ -433,6 +707,8 @@ func TestLikeTweet(t *testing.T) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
 				getArg := database.GetLikeRelationParams{
 					Username: user.Username,
 					TweetID: tweet.ID,
@@ -442,7 +718,7 @@ func TestLikeTweet(t *testing.T) {
 					TweetID: tweet.ID,
 				}
 				transaction.EXPECT().GetLikeRelation(gomock.Any(), gomock.Eq(getArg)).Times(1).Return(database.LikeRelations{}, sql.ErrNoRows)
-				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Eq(createArg)).Times(1).Return(sql.ErrConnDone)
+				transaction.EXPECT().LikeTweetTx(gomock.Any(), gomock.Eq(createArg)).Times(1).Return(database.Tweets{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -603,42 +879,142 @@ func TestUnlikeTweet(t *testing.T) {
 
 func TestGetFeeds(t *testing.T) {
 	user, _ := randomUser(t)
+	followed, _ := randomUser(t)
+
+	feeds := []database.Tweets{randomTweets(followed), randomTweets(user)}
+	feeds[0].ID = 2
+
+	protected, _ := randomUser(t)
+	protected.Protected = true
+	original := randomTweets(protected)
+	original.ID = 5
+	retweet := randomTweets(followed)
+	retweet.ID = 6
+	retweet.RetweetOfID = sql.NullInt64{Int64: original.ID, Valid: true}
 
 	testCases := []struct{
 		name string
+		query string
 		setupAuth func(t *testing.T, request *http.Request, paseto token.Paseto)
 		buildStubs func(transaction *dbmock.MockTransaction)
 		checkResponse func(t *testing.T,recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
+			query: "",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				getFollowingArg := database.GetFollowingParams{
-					FollowerUsername: user.Username,
-					Limit: 10000,
-					Offset: 1,
+				arg := database.GetMaterializedHomeTimelineParams{
+					Username: user.Username,
+					BeforeID: math.MaxInt64,
+					PageSize: defaultPageLimit,
 				}
-				transaction.EXPECT().GetFollowing(gomock.Any(),gomock.Eq(getFollowingArg)).Times(1).Return([]database.Relations{}, nil)
+				transaction.EXPECT().GetMaterializedHomeTimeline(gomock.Any(),gomock.Eq(arg)).Times(1).Return(feeds, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followed.Username)).Times(1).Return(followed, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{2, 1})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 2)
+				require.Equal(t, followed.Username, resp.Tweets[0].Username)
+				require.Equal(t, user.Username, resp.Tweets[1].Username)
+				require.Empty(t, resp.NextCursor)
+				require.Empty(t, resp.PrevCursor)
 			},
 		},
 		{
-			name: "Internal server error",
+			name: "Retweet of a hidden author",
+			query: "?limit=2",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				getFollowingArg := database.GetFollowingParams{
-					FollowerUsername: user.Username,
-					Limit: 10000,
-					Offset: 1,
+				transaction.EXPECT().GetMaterializedHomeTimeline(gomock.Any(),gomock.Any()).Times(1).Return([]database.Tweets{retweet, feeds[1]}, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(followed.Username)).Times(1).Return(followed, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(protected.Username)).Times(1).Return(protected, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(2).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{1})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 1)
+				require.Equal(t, user.Username, resp.Tweets[0].Username)
+				//the page was full, the hidden retweet doesn't end it
+				require.NotEmpty(t, resp.NextCursor)
+			},
+		},
+		{
+			name: "Max and since ids",
+			query: "?max_id=50&since_id=10&limit=5",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.GetMaterializedHomeTimelineParams{
+					Username: user.Username,
+					AfterID: 10,
+					BeforeID: 51,
+					PageSize: 5,
 				}
-				transaction.EXPECT().GetFollowing(gomock.Any(),gomock.Eq(getFollowingArg)).Times(1).Return([]database.Relations{}, sql.ErrConnDone)
+				transaction.EXPECT().GetMaterializedHomeTimeline(gomock.Any(),gomock.Eq(arg)).Times(1).Return([]database.Tweets{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Empty(t, resp.Tweets)
+			},
+		},
+		{
+			name: "Invalid cursor",
+			query: "?cursor=1",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMaterializedHomeTimeline(gomock.Any(),gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Bad Request",
+			query: "?limit=500",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMaterializedHomeTimeline(gomock.Any(),gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal server error",
+			query: "",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetMaterializedHomeTimeline(gomock.Any(),gomock.Any()).Times(1).Return([]database.Tweets{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -659,7 +1035,7 @@ func TestGetFeeds(t *testing.T) {
 		server := NewTestServer(t, transaction)
 		recorder := httptest.NewRecorder()
 
-		url := "/feeds"
+		url := "/feeds" + testcase.query
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		require.NoError(t, err)
 
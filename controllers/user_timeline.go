@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+var errTweetsHidden = errors.New("these tweets are not visible to you")
+
+type usernameURI struct {
+	Username string `uri:"username" binding:"required,min=1,max=30"`
+}
+
+// UserTweetsRequest filters the user timeline, replies are left out and retweets
+// kept unless asked otherwise
+type UserTweetsRequest struct {
+	PageRequest
+	IncludeReplies  *bool `form:"include_replies"`
+	IncludeRetweets *bool `form:"include_retweets"`
+	MediaOnly       bool  `form:"media_only"`
+}
+
+// userTweetsList names a user timeline for its cursors. The filters are hashed in,
+// a cursor replayed under other filters would skip or repeat tweets.
+func userTweetsList(username string, includeReplies, includeRetweets, mediaOnly bool) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%t:%t:%t", includeReplies, includeRetweets, mediaOnly)))
+	return fmt.Sprintf("tweets:%v:%x", username, sum[:8])
+}
+
+// canView tells whether viewer, empty when anonymous, may read the author's tweets:
+// not when the author blocked them, and protected accounts only to their followers
+func (s *Server) canView(c *gin.Context, author database.Users, viewer string) (bool, error) {
+	if viewer == author.Username {
+		return true, nil
+	}
+
+	if viewer != "" {
+		blocked, err := s.transaction.IsBlocked(c, database.IsBlockedParams{
+			BlockerUsername: author.Username,
+			BlockedUsername: viewer,
+		})
+		if err != nil || blocked {
+			return false, err
+		}
+	}
+
+	if !author.Protected {
+		return true, nil
+	}
+	if viewer == "" {
+		return false, nil
+	}
+	_, err := s.transaction.GetRelations(c, database.GetRelationsParams{
+		FollowerUsername: viewer,
+		FollowedUsername: author.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// visibleTweet loads a tweet the viewer is allowed to see along with its author,
+// the status is the one to answer with when it fails
+func (s *Server) visibleTweet(c *gin.Context, id int64, viewer string) (database.Tweets, database.Users, int, error) {
+	tweet, err := s.transaction.GetTweet(c, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tweet, database.Users{}, http.StatusNotFound, err
+		}
+		return tweet, database.Users{}, http.StatusInternalServerError, err
+	}
+
+	author, err := s.transaction.GetUser(c, tweet.Username)
+	if err != nil {
+		return tweet, author, http.StatusInternalServerError, err
+	}
+
+	ok, err := s.canView(c, author, viewer)
+	if err != nil {
+		return tweet, author, http.StatusInternalServerError, err
+	}
+	if !ok {
+		return tweet, author, http.StatusForbidden, errTweetsHidden
+	}
+	return tweet, author, http.StatusOK, nil
+}
+
+// visibleTweets drops the tweets the viewer isn't allowed to see, the retweets of
+// such tweets included. Each author is only looked up once.
+func (s *Server) visibleTweets(c *gin.Context, tweets []database.Tweets, viewer string) ([]database.Tweets, error) {
+	return s.visibleTweetsOf(c, tweets, viewer, map[string]bool{viewer: true})
+}
+
+// visibleTweetsOf is visibleTweets with the authors already known to be visible in allowed
+func (s *Server) visibleTweetsOf(c *gin.Context, tweets []database.Tweets, viewer string, allowed map[string]bool) ([]database.Tweets, error) {
+	authorVisible := func(username string) (bool, error) {
+		if ok, seen := allowed[username]; seen {
+			return ok, nil
+		}
+		author, err := s.transaction.GetUser(c, username)
+		if err != nil {
+			return false, err
+		}
+		ok, err := s.canView(c, author, viewer)
+		if err != nil {
+			return false, err
+		}
+		allowed[username] = ok
+		return ok, nil
+	}
+
+	visible := []database.Tweets{}
+	for _, tweet := range tweets {
+		ok, err := authorVisible(tweet.Username)
+		if err != nil {
+			return nil, err
+		}
+		if ok && tweet.RetweetOfID.Valid {
+			original, err := s.transaction.GetTweet(c, tweet.RetweetOfID.Int64)
+			if err != nil {
+				return nil, err
+			}
+			if ok, err = authorVisible(original.Username); err != nil {
+				return nil, err
+			}
+		}
+		if ok {
+			visible = append(visible, tweet)
+		}
+	}
+	return visible, nil
+}
+
+// GetUserTweets is the timeline of a user, newest first with the pinned tweet
+// on top of the first page
+func (s *Server) GetUserTweets(c *gin.Context) {
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	var req UserTweetsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	includeReplies := req.IncludeReplies != nil && *req.IncludeReplies
+	includeRetweets := req.IncludeRetweets == nil || *req.IncludeRetweets
+
+	list := userTweetsList(uri.Username, includeReplies, includeRetweets, req.MediaOnly)
+	p, err := s.newPage(list, req.PageRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	user, err := s.transaction.GetUser(c, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	ok, err := s.canView(c, user, callerUsername(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, ErrResponse(errTweetsHidden.Error()))
+		return
+	}
+
+	tweets := []database.Tweets{}
+	//media-only pages are a gallery, the pin doesn't belong there
+	showPinned := user.PinnedTweetID.Valid && !req.MediaOnly
+	//the pin takes one of the first page's slots
+	rowsPage := p
+	if p.first && showPinned {
+		pinned, _, status, err := s.visibleTweet(c, user.PinnedTweetID.Int64, callerUsername(c))
+		if status == http.StatusInternalServerError {
+			c.JSON(status, ErrResponse(err.Error()))
+			return
+		}
+		if err == nil {
+			tweets = append(tweets, pinned)
+			rowsPage.Limit--
+		}
+	}
+
+	rows := []database.Tweets{}
+	if rowsPage.Limit > 0 {
+		rows, err = s.transaction.ListUserTweets(c, database.ListUserTweetsParams{
+			Username:        user.Username,
+			IncludeReplies:  includeReplies,
+			IncludeRetweets: includeRetweets,
+			MediaOnly:       req.MediaOnly,
+			AfterID:         p.AfterID,
+			BeforeID:        p.BeforeID,
+			Reverse:         p.Reverse,
+			PageSize:        rowsPage.Limit,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+			return
+		}
+	}
+	keys := make([]int64, len(rows))
+	//the pinned tweet is only shown on top
+	for i, tweet := range rows {
+		keys[i] = tweet.ID
+		if showPinned && tweet.ID == user.PinnedTweetID.Int64 {
+			continue
+		}
+		tweets = append(tweets, tweet)
+	}
+
+	//the timeline owner is visible, the authors they retweeted may not be
+	tweets, err = s.visibleTweetsOf(c, tweets, callerUsername(c), map[string]bool{user.Username: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := timelineResponse{}
+	resp.Tweets, err = s.tweetResponses(c, tweets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	for i := range resp.Tweets {
+		resp.Tweets[i].Pinned = showPinned && resp.Tweets[i].ID == user.PinnedTweetID.Int64
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, rowsPage, keys)
+	if rowsPage.Limit == 0 {
+		//a page holding just the pin, the rest starts from the newest
+		resp.NextCursor = s.encodeCursor(pageCursor{List: list, ID: p.BeforeID})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
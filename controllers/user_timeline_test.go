@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserTweets(t *testing.T) {
+	user, _ := randomUser(t)
+
+	tweets := make([]database.Tweets, 3)
+	for i := range tweets {
+		tweets[i] = randomTweets(user)
+		tweets[i].ID = int64(3 - i)
+	}
+	pinnedUser := user
+	pinnedUser.PinnedTweetID = sql.NullInt64{Int64: 2, Valid: true}
+	protectedUser := user
+	protectedUser.Protected = true
+	viewer, _ := randomUser(t)
+	viewerAuth := func(t *testing.T, request *http.Request, paseto token.Paseto) {
+		AddAuth(t, request, paseto, authorizationTypeBearer, viewer.Username, time.Minute)
+	}
+	noAuth := func(t *testing.T, request *http.Request, paseto token.Paseto) {}
+	defaultList := userTweetsList(user.Username, false, true, false)
+
+	hiddenAuthor, _ := randomUser(t)
+	hiddenOriginal := randomTweets(hiddenAuthor)
+	hiddenOriginal.ID = 7
+	hiddenRetweet := randomTweets(user)
+	hiddenRetweet.ID = 8
+	hiddenRetweet.RetweetOfID = sql.NullInt64{Int64: hiddenOriginal.ID, Valid: true}
+
+	testcases := []struct {
+		name          string
+		query         string
+		cursor        *pageCursor
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Pinned first",
+			query:     "?limit=3",
+			setupAuth: noAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				//the pin counts within the limit
+				arg := database.ListUserTweetsParams{Username: user.Username, BeforeID: math.MaxInt64, IncludeRetweets: true, PageSize: 2}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(pinnedUser, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(2))).Times(1).Return(tweets[1], nil)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Eq(arg)).Times(1).Return(tweets[:2], nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{2, 3})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 2)
+				require.Equal(t, int64(2), resp.Tweets[0].ID)
+				require.True(t, resp.Tweets[0].Pinned)
+				require.Equal(t, int64(3), resp.Tweets[1].ID)
+				require.False(t, resp.Tweets[1].Pinned)
+				require.Equal(t, pageCursor{List: defaultList, ID: 2}, cursorPayload(t, resp.NextCursor))
+			},
+		},
+		{
+			name:      "Pinned fills the page",
+			query:     "?limit=1",
+			setupAuth: noAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(pinnedUser, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(2))).Times(1).Return(tweets[1], nil)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{2})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 1)
+				require.True(t, resp.Tweets[0].Pinned)
+				require.Equal(t, pageCursor{List: defaultList, ID: math.MaxInt64}, cursorPayload(t, resp.NextCursor))
+				require.Empty(t, resp.PrevCursor)
+			},
+		},
+		{
+			name:      "Pinned not repeated on next pages",
+			query:     "",
+			cursor:    &pageCursor{List: defaultList, ID: 3},
+			setupAuth: noAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListUserTweetsParams{Username: user.Username, BeforeID: 3, IncludeRetweets: true, PageSize: defaultPageLimit}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(pinnedUser, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Eq(arg)).Times(1).Return(tweets[1:], nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{1})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 1)
+				require.Empty(t, resp.NextCursor)
+			},
+		},
+		{
+			name:      "Retweet of a hidden author",
+			query:     "?limit=2",
+			setupAuth: viewerAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(1).Return([]database.Tweets{hiddenRetweet, tweets[2]}, nil)
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(hiddenOriginal.ID)).Times(1).Return(hiddenOriginal, nil)
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(hiddenAuthor.Username)).Times(1).Return(hiddenAuthor, nil)
+				//the retweeted author blocked the viewer
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(database.IsBlockedParams{BlockerUsername: hiddenAuthor.Username, BlockedUsername: viewer.Username})).Times(1).Return(true, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{1})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 1)
+				require.Equal(t, int64(1), resp.Tweets[0].ID)
+				require.Equal(t, pageCursor{List: defaultList, ID: 1}, cursorPayload(t, resp.NextCursor))
+			},
+		},
+		{
+			name:      "Cursor of other filters",
+			query:     "?media_only=true",
+			cursor:    &pageCursor{List: defaultList, ID: 3},
+			setupAuth: noAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "User not found",
+			query:     "",
+			setupAuth: noAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(database.Users{}, sql.ErrNoRows)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "Filters",
+			query:     "?include_replies=true&include_retweets=false&media_only=true",
+			setupAuth: viewerAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.ListUserTweetsParams{
+					Username:       user.Username,
+					BeforeID:       math.MaxInt64,
+					IncludeReplies: true,
+					MediaOnly:      true,
+					PageSize:       defaultPageLimit,
+				}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(pinnedUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				//no pin in a media gallery
+				transaction.EXPECT().GetTweet(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Eq(arg)).Times(1).Return(tweets, nil)
+				transaction.EXPECT().ListMediaByTweetIDs(gomock.Any(), gomock.Eq([]int64{3, 2, 1})).Times(1).Return([]database.Media{}, nil)
+				transaction.EXPECT().ListPollsByTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]database.Polls{}, nil)
+				transaction.EXPECT().ListBookmarkedTweetIDs(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp timelineResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Tweets, 3)
+				require.False(t, resp.Tweets[1].Pinned)
+			},
+		},
+		{
+			name:      "Blocked by the author",
+			query:     "",
+			setupAuth: viewerAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.IsBlockedParams{BlockerUsername: user.Username, BlockedUsername: viewer.Username}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(arg)).Times(1).Return(true, nil)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Protected anonymous",
+			query:     "",
+			setupAuth: noAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(protectedUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Protected not following",
+			query:     "",
+			setupAuth: viewerAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.GetRelationsParams{FollowerUsername: viewer.Username, FollowedUsername: user.Username}
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(protectedUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Eq(arg)).Times(1).Return(database.Relations{}, sql.ErrNoRows)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Protected follower",
+			query:     "",
+			setupAuth: viewerAuth,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(protectedUser, nil)
+				transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				transaction.EXPECT().GetRelations(gomock.Any(), gomock.Any()).Times(1).Return(database.Relations{}, nil)
+				transaction.EXPECT().ListUserTweets(gomock.Any(), gomock.Any()).Times(1).Return([]database.Tweets{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "Invalid token",
+			query: "",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				request.Header.Set(authorizationHeaderKey, "Bearer garbage")
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		url := fmt.Sprintf("/users/%v/tweets%v", user.Username, testcase.query)
+		req, err := http.NewRequest(http.MethodGet, withCursor(server, url, testcase.cursor), nil)
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
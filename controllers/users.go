@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 
 	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
@@ -239,93 +241,161 @@ func (s *Server) UpdateName(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-type GetListRequest struct {
-	PageSize int32 `json:"page_size" binding:"required,min=5"`
-	PageId int32 `json:"page_id" binding:"required,min=1"`
+type SetProtectedReq struct {
+	Protected *bool `json:"protected" binding:"required"`
 }
 
-func (s *Server) GetFollowersList(c *gin.Context) {
-	var req GetListRequest
+// SetProtected makes the caller's tweets visible to their followers only, or public again
+func (s *Server) SetProtected(c *gin.Context) {
+	var req SetProtectedReq
+
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
 		return
 	}
 
-	var resp []signUpAndUpdateResp
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
 
+	arg := database.SetProtectedParams{
+		Protected: *req.Protected,
+		Username: authPayload.Username,
+	}
+	user, err := s.transaction.SetProtected(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	state := "public"
+	if user.Protected {
+		state = "protected"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message" : fmt.Sprintf("%v's tweets have succesfully been made %v", user.Username, state),
+	})
+}
+
+var errRelationsHidden = errors.New("this user's relations are not visible to you")
+
+// relationsOwner is whose followers or followings are listed, the user in the path
+// on /api/v1 or the caller on the deprecated routes. It responds itself and returns
+// false when the list can't be shown.
+func (s *Server) relationsOwner(c *gin.Context) (string, bool) {
 	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if c.Param("username") == "" {
+		return authHeader.Username, true
+	}
 
-	arg := database.GetFollowerParams{
-		FollowedUsername: authHeader.Username,
-		Limit: req.PageSize,
-		Offset: req.PageId,
+	var uri usernameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return "", false
+	}
+
+	user, err := s.transaction.GetUser(c, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return "", false
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return "", false
 	}
 
-	followers, err := s.transaction.GetFollower(c, arg)
+	ok, err := s.canView(c, user, authHeader.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return "", false
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, ErrResponse(errRelationsHidden.Error()))
+		return "", false
+	}
+	return user.Username, true
+}
+
+func (s *Server) GetFollowersList(c *gin.Context) {
+	username, ok := s.relationsOwner(c)
+	if !ok {
 		return
 	}
+	list := "followers:" + username
 
-	for _,f := range followers {
-		follower, err := s.transaction.GetUser(c, f.FollowerUsername)
-		if err != nil {
-			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
-			return
-		}
-		r := signUpAndUpdateResp{
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	arg := database.ListFollowersParams{
+		Username: username,
+		AfterID: p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse: p.Reverse,
+		PageSize: p.Limit,
+	}
+
+	followers, err := s.transaction.ListFollowers(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := usersPageResponse{Users: []userSummaryResponse{}}
+	keys := make([]int64, len(followers))
+	for i, follower := range followers {
+		resp.Users = append(resp.Users, userSummaryResponse{
 			Username: follower.Username,
-			Email: follower.Email,
 			Name: follower.Name,
-			Followers_Count: follower.FollowersCount.Int32,
-			Following_Count: follower.FollowingCount.Int32,
-		}
-
-		resp = append(resp, r)
+			FollowersCount: follower.FollowersCount.Int32,
+			FollowingCount: follower.FollowingCount.Int32,
+		})
+		keys[i] = follower.RelationID
 	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
 
 	c.JSON(http.StatusOK, resp)
 }
 
 func (s *Server) GetFollowingList(c *gin.Context) {
-	var req GetListRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+	username, ok := s.relationsOwner(c)
+	if !ok {
 		return
 	}
+	list := "following:" + username
 
-	var resp []signUpAndUpdateResp
-
-	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
 
-	arg := database.GetFollowingParams{
-		FollowerUsername: authHeader.Username,
-		Limit: req.PageSize,
-		Offset: req.PageId,
+	arg := database.ListFollowingParams{
+		Username: username,
+		AfterID: p.AfterID,
+		BeforeID: p.BeforeID,
+		Reverse: p.Reverse,
+		PageSize: p.Limit,
 	}
 
-	followings, err := s.transaction.GetFollowing(c, arg)
+	followings, err := s.transaction.ListFollowing(c, arg)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
 		return
 	}
 
-	for _,f := range followings {
-		following, err := s.transaction.GetUser(c, f.FollowedUsername)
-		if err != nil {
-			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
-			return
-		}
-		r := signUpAndUpdateResp{
+	resp := usersPageResponse{Users: []userSummaryResponse{}}
+	keys := make([]int64, len(followings))
+	for i, following := range followings {
+		resp.Users = append(resp.Users, userSummaryResponse{
 			Username: following.Username,
-			Email: following.Email,
 			Name: following.Name,
-			Followers_Count: following.FollowersCount.Int32,
-			Following_Count: following.FollowingCount.Int32,
-		}
-
-		resp = append(resp, r)
+			FollowersCount: following.FollowersCount.Int32,
+			FollowingCount: following.FollowingCount.Int32,
+		})
+		keys[i] = following.RelationID
 	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
 
 	c.JSON(http.StatusOK, resp)
 }
\ No newline at end of file
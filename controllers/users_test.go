@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -592,64 +593,82 @@ func TestUpdateEmail(t *testing.T) {
 
 func TestGetFollowers(t *testing.T) {
 	user, _ := randomUser(t)
+	rows := []database.ListFollowersRow{
+		{RelationID: 7, Username: util.GetRandomString(6), Name: util.GetRandomString(6)},
+		{RelationID: 3, Username: util.GetRandomString(6), Name: util.GetRandomString(6)},
+	}
 
 	testcases := []struct{
 		name string
-		body gin.H
+		query string
 		setupAuth func(t *testing.T, request *http.Request, paseto token.Paseto)
 		buildStubs func(transaction *dbmock.MockTransaction)
-		checkResponse func(t *testing.T,recorder *httptest.ResponseRecorder)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
-			body: gin.H{
-				"page_size" : 5,
-				"page_id" : 1,
-			},
+			query: "?limit=2",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				arg := database.GetFollowerParams{
-					FollowedUsername: user.Username,
-					Limit: 5,
-					Offset: 1,
+				arg := database.ListFollowersParams{
+					Username: user.Username,
+					BeforeID: math.MaxInt64,
+					PageSize: 2,
 				}
-				transaction.EXPECT().GetFollower(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.Relations{}, nil)
+				transaction.EXPECT().ListFollowers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows, nil)
 			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp usersPageResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Users, 2)
+				require.Equal(t, rows[0].Username, resp.Users[0].Username)
+				require.Empty(t, resp.PrevCursor)
+
+				cursor, err := server.decodeCursor("followers:"+user.Username, resp.NextCursor)
+				require.NoError(t, err)
+				require.Equal(t, pageCursor{List: "followers:"+user.Username, ID: 3}, cursor)
 			},
 		},
 		{
 			name: "Bad Request",
-			body: gin.H{
-				"page_sizee" : 5,
-				"page_id" : 1,
+			query: "?limit=500",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListFollowers(gomock.Any(), gomock.Any()).Times(0)
 			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid cursor",
+			query: "?cursor=1",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				transaction.EXPECT().GetFollower(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().ListFollowers(gomock.Any(), gomock.Any()).Times(0)
 			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
 			name: "Internal server error",
-			body: gin.H{
-				"page_size" : 5,
-				"page_id" : 1,
-			},
+			query: "",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				transaction.EXPECT().GetFollower(gomock.Any(), gomock.Any()).Times(1).Return([]database.Relations{}, sql.ErrConnDone)
+				transaction.EXPECT().ListFollowers(gomock.Any(), gomock.Any()).Times(1).Return([]database.ListFollowersRow{}, sql.ErrConnDone)
 			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
@@ -668,80 +687,94 @@ func TestGetFollowers(t *testing.T) {
 		server := NewTestServer(t, transaction)
 		recorder := httptest.NewRecorder()
 
-		// marshal/read body params
-		data, err := json.Marshal(testcase.body)
-		require.NoError(t, err)
-
-		url := "/followers"
-		req, err := http.NewRequest(http.MethodGet, url, bytes.NewReader(data))
+		url := "/followers" + testcase.query
+		req, err := http.NewRequest(http.MethodGet, url, nil)
 		require.NoError(t, err)
 
 		testcase.setupAuth(t, req, server.paseto)
 		server.router.ServeHTTP(recorder, req)
-		testcase.checkResponse(t,recorder)
+		testcase.checkResponse(t, server, recorder)
 	}
 }
 
 func TestGetFollowing(t *testing.T) {
 	user, _ := randomUser(t)
+	rows := []database.ListFollowingRow{
+		{RelationID: 7, Username: util.GetRandomString(6), Name: util.GetRandomString(6)},
+		{RelationID: 3, Username: util.GetRandomString(6), Name: util.GetRandomString(6)},
+	}
 
 	testcases := []struct{
 		name string
-		body gin.H
+		query string
 		setupAuth func(t *testing.T, request *http.Request, paseto token.Paseto)
 		buildStubs func(transaction *dbmock.MockTransaction)
-		checkResponse func(t *testing.T,recorder *httptest.ResponseRecorder)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
-			body: gin.H{
-				"page_size" : 5,
-				"page_id" : 1,
-			},
+			query: "?limit=2",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				arg := database.GetFollowingParams{
-					FollowerUsername: user.Username,
-					Limit: 5,
-					Offset: 1,
+				arg := database.ListFollowingParams{
+					Username: user.Username,
+					BeforeID: math.MaxInt64,
+					PageSize: 2,
 				}
-				transaction.EXPECT().GetFollowing(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.Relations{}, nil)
+				transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows, nil)
 			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp usersPageResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Users, 2)
+				require.Equal(t, rows[0].Username, resp.Users[0].Username)
+				require.Empty(t, resp.PrevCursor)
+
+				cursor, err := server.decodeCursor("following:"+user.Username, resp.NextCursor)
+				require.NoError(t, err)
+				require.Equal(t, pageCursor{List: "following:"+user.Username, ID: 3}, cursor)
 			},
 		},
 		{
 			name: "Bad Request",
-			body: gin.H{
-				"page_sizee" : 5,
-				"page_id" : 1,
+			query: "?limit=500",
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Any()).Times(0)
 			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid cursor",
+			query: "?cursor=1",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				transaction.EXPECT().GetFollowing(gomock.Any(), gomock.Any()).Times(0)
+				transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Any()).Times(0)
 			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
 			name: "Internal server error",
-			body: gin.H{
-				"page_size" : 5,
-				"page_id" : 1,
-			},
+			query: "",
 			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
 				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(transaction *dbmock.MockTransaction) {
-				transaction.EXPECT().GetFollowing(gomock.Any(), gomock.Any()).Times(1).Return([]database.Relations{}, sql.ErrConnDone)
+				transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Any()).Times(1).Return([]database.ListFollowingRow{}, sql.ErrConnDone)
 			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
@@ -760,16 +793,12 @@ func TestGetFollowing(t *testing.T) {
 		server := NewTestServer(t, transaction)
 		recorder := httptest.NewRecorder()
 
-		// marshal/read body params
-		data, err := json.Marshal(testcase.body)
-		require.NoError(t, err)
-
-		url := "/following"
-		req, err := http.NewRequest(http.MethodGet, url, bytes.NewReader(data))
+		url := "/following" + testcase.query
+		req, err := http.NewRequest(http.MethodGet, url, nil)
 		require.NoError(t, err)
 
 		testcase.setupAuth(t, req, server.paseto)
 		server.router.ServeHTTP(recorder, req)
-		testcase.checkResponse(t,recorder)
+		testcase.checkResponse(t, server, recorder)
 	}
 }
\ No newline at end of file
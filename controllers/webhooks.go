@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+// webhookTestEvent is what the test-fire endpoint sends, it never goes through the queue
+const webhookTestEvent = "webhook.test"
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,max=3,dive,oneof=tweet.created follow.created like.created"`
+}
+
+type webhookURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type webhookDeliveryURI struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+// webhookResponse carries the secret only when the webhook is created
+type webhookResponse struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newWebhookResponse(hook database.Webhooks) webhookResponse {
+	return webhookResponse{
+		ID:        hook.ID,
+		URL:       hook.Url,
+		Events:    hook.Events,
+		CreatedAt: hook.CreatedAt,
+	}
+}
+
+type webhookDeliveryResponse struct {
+	ID         int64           `json:"id"`
+	Event      string          `json:"event"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int32           `json:"attempts"`
+	LastError  string          `json:"last_error,omitempty"`
+	LastStatus int32           `json:"last_status,omitempty"`
+	DeadAt     time.Time       `json:"dead_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type deadLettersResponse struct {
+	Deliveries []webhookDeliveryResponse `json:"deliveries"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+	PrevCursor string                    `json:"prev_cursor,omitempty"`
+}
+
+// webhookTestResponse only tells whether the receiver took the event, its status
+// and errors would let callers probe what the server can reach
+type webhookTestResponse struct {
+	Delivered bool `json:"delivered"`
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// CreateWebhook registers a URL for events the caller is part of: their tweets,
+// follows they make or get and likes they give or get
+func (s *Server) CreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	if err := s.webhooks.CheckURL(c, req.URL); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	hook, err := s.transaction.CreateWebhook(c, database.CreateWebhookParams{
+		Username: authHeader.Username,
+		Url:      req.URL,
+		Secret:   secret,
+		Events:   req.Events,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := newWebhookResponse(hook)
+	resp.Secret = hook.Secret
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ListWebhooks(c *gin.Context) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	hooks, err := s.transaction.ListWebhooks(c, authHeader.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := make([]webhookResponse, len(hooks))
+	for i, hook := range hooks {
+		resp[i] = newWebhookResponse(hook)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) DeleteWebhook(c *gin.Context) {
+	var uri webhookURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	deleted, err := s.transaction.DeleteWebhook(c, database.DeleteWebhookParams{
+		ID:       uri.ID,
+		Username: authHeader.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(sql.ErrNoRows.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Webhook with ID %v has succesfully been deleted", uri.ID),
+	})
+}
+
+// ownWebhook is the caller's webhook addressed by the :id param, it writes the error
+// response and returns false otherwise
+func (s *Server) ownWebhook(c *gin.Context, id int64) (database.Webhooks, bool) {
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	hook, err := s.transaction.GetWebhook(c, database.GetWebhookParams{
+		ID:       id,
+		Username: authHeader.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, ErrResponse(err.Error()))
+			return hook, false
+		}
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return hook, false
+	}
+	return hook, true
+}
+
+// TestWebhook sends a webhook.test event right away and reports whether it was
+// delivered, the delivery isn't queued nor retried
+func (s *Server) TestWebhook(c *gin.Context) {
+	var uri webhookURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	hook, ok := s.ownWebhook(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(database.WebhookPayload{
+		Event:     webhookTestEvent,
+		CreatedAt: time.Now().UTC(),
+		Data:      gin.H{"webhook_id": hook.ID},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	_, err = s.webhooks.Send(c, webhook.Delivery{
+		URL:    hook.Url,
+		Secret: hook.Secret,
+		Event:  webhookTestEvent,
+		Body:   body,
+	})
+	c.JSON(http.StatusOK, webhookTestResponse{Delivered: err == nil})
+}
+
+// ListDeadLetters returns the deliveries that ran out of attempts, newest first
+func (s *Server) ListDeadLetters(c *gin.Context) {
+	var uri webhookURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+	list := "dead_letters:" + strconv.FormatInt(uri.ID, 10)
+
+	p, err := s.bindPage(c, list)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	if _, ok := s.ownWebhook(c, uri.ID); !ok {
+		return
+	}
+
+	deliveries, err := s.transaction.ListDeadWebhookDeliveries(c, database.ListDeadWebhookDeliveriesParams{
+		WebhookID: uri.ID,
+		AfterID:   p.AfterID,
+		BeforeID:  p.BeforeID,
+		Reverse:   p.Reverse,
+		PageSize:  p.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+
+	resp := deadLettersResponse{Deliveries: make([]webhookDeliveryResponse, len(deliveries))}
+	keys := make([]int64, len(deliveries))
+	for i, delivery := range deliveries {
+		resp.Deliveries[i] = webhookDeliveryResponse{
+			ID:         delivery.ID,
+			Event:      delivery.Event,
+			Payload:    delivery.Payload,
+			Attempts:   delivery.Attempts,
+			LastError:  delivery.LastError.String,
+			LastStatus: delivery.LastStatus.Int32,
+			DeadAt:     delivery.DeadAt.Time,
+			CreatedAt:  delivery.CreatedAt,
+		}
+		keys[i] = delivery.ID
+	}
+	resp.NextCursor, resp.PrevCursor = s.pageCursors(list, p, keys)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RetryDeadLetter queues a dead delivery again with a fresh set of attempts
+func (s *Server) RetryDeadLetter(c *gin.Context) {
+	var uri webhookDeliveryURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ErrResponse(err.Error()))
+		return
+	}
+
+	if _, ok := s.ownWebhook(c, uri.ID); !ok {
+		return
+	}
+
+	retried, err := s.transaction.RetryWebhookDelivery(c, database.RetryWebhookDeliveryParams{
+		ID:        uri.DeliveryID,
+		WebhookID: uri.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrResponse(err.Error()))
+		return
+	}
+	if retried == 0 {
+		c.JSON(http.StatusNotFound, ErrResponse(fmt.Sprintf("delivery %v isn't in the dead-letter list", uri.DeliveryID)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("Delivery with ID %v has succesfully been queued again", uri.DeliveryID),
+	})
+}
@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWebhook(username, url string) database.Webhooks {
+	return database.Webhooks{
+		ID:        7,
+		Username:  username,
+		Url:       url,
+		Secret:    util.GetRandomString(32),
+		Events:    []string{database.WebhookTweetCreated, database.WebhookLikeCreated},
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+type webhookResolver map[string]string
+
+func (r webhookResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+func TestCreateWebhook(t *testing.T) {
+	user, _ := randomUser(t)
+	hook := randomWebhook(user.Username, "https://example.com/hooks")
+
+	testcases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, paseto token.Paseto)
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"url": hook.Url, "events": hook.Events},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreateWebhookParams) (database.Webhooks, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, hook.Url, arg.Url)
+						require.Equal(t, hook.Events, arg.Events)
+						require.Len(t, arg.Secret, 64)
+						created := hook
+						created.Secret = arg.Secret
+						return created, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp webhookResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, hook.ID, resp.ID)
+				require.Len(t, resp.Secret, 64)
+			},
+		},
+		{
+			name: "Unknown event",
+			body: gin.H{"url": hook.Url, "events": []string{"tweet.deleted"}},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Not http",
+			body: gin.H{"url": "ftp://example.com/hooks", "events": hook.Events},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Loopback",
+			body: gin.H{"url": "http://127.0.0.1:8080/hooks", "events": hook.Events},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), webhook.ErrForbiddenAddress.Error())
+			},
+		},
+		{
+			name: "Private",
+			body: gin.H{"url": "http://192.168.0.10/hooks", "events": hook.Events},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), webhook.ErrForbiddenAddress.Error())
+			},
+		},
+		{
+			name: "Host resolving to metadata",
+			body: gin.H{"url": "http://metadata.internal/hooks", "events": hook.Events},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+				AddAuth(t, request, paseto, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), webhook.ErrForbiddenAddress.Error())
+			},
+		},
+		{
+			name: "Unauthorized",
+			body: gin.H{"url": hook.Url, "events": hook.Events},
+			setupAuth: func(t *testing.T, request *http.Request, paseto token.Paseto) {
+			},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		server.webhooks.Resolver = webhookResolver{
+			"example.com":       "93.184.216.34",
+			"metadata.internal": "169.254.169.254",
+		}
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(testcase.body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		testcase.setupAuth(t, req, server.paseto)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestListWebhooksHidesSecret(t *testing.T) {
+	user, _ := randomUser(t)
+	hook := randomWebhook(user.Username, "https://example.com/hooks")
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().ListWebhooks(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.Webhooks{hook}, nil)
+
+	server := NewTestServer(t, transaction)
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/webhooks", nil)
+	require.NoError(t, err)
+	AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), hook.Secret)
+
+	var resp []webhookResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	require.Equal(t, []webhookResponse{newWebhookResponse(hook)}, resp)
+}
+
+func TestTestWebhook(t *testing.T) {
+	user, _ := randomUser(t)
+
+	var received *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	hook := randomWebhook(user.Username, receiver.URL)
+
+	testcases := []struct {
+		name          string
+		status        int
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Delivered",
+			status: http.StatusNoContent,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				arg := database.GetWebhookParams{ID: hook.ID, Username: user.Username}
+				transaction.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(arg)).Times(1).Return(hook, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp webhookTestResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, webhookTestResponse{Delivered: true}, resp)
+
+				require.Equal(t, webhookTestEvent, received.Header.Get(webhook.EventHeader))
+				require.True(t, webhook.Verify(hook.Secret, received.Header.Get(webhook.SignatureHeader),
+					received.Header.Get(webhook.TimestampHeader), body, time.Minute, time.Now()))
+
+				var payload database.WebhookPayload
+				require.NoError(t, json.Unmarshal(body, &payload))
+				require.Equal(t, webhookTestEvent, payload.Event)
+			},
+		},
+		{
+			name:   "Receiver fails",
+			status: http.StatusInternalServerError,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(hook, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp webhookTestResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Equal(t, webhookTestResponse{Delivered: false}, resp)
+				require.NotContains(t, recorder.Body.String(), "500")
+			},
+		},
+		{
+			name:   "Not Found",
+			status: http.StatusNoContent,
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(database.Webhooks{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+		status = testcase.status
+		received, body = nil, nil
+
+		server := NewTestServer(t, transaction)
+		//the receiver listens on loopback, which the server's sender refuses
+		server.webhooks = &webhook.Sender{Client: receiver.Client()}
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/webhooks/"+fmt.Sprint(hook.ID)+"/test", nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	user, _ := randomUser(t)
+	hook := randomWebhook(user.Username, "https://example.com/hooks")
+	list := "dead_letters:" + fmt.Sprint(hook.ID)
+
+	dead := database.WebhookDeliveries{
+		ID:         41,
+		WebhookID:  hook.ID,
+		Event:      database.WebhookLikeCreated,
+		Payload:    json.RawMessage(`{"event":"like.created"}`),
+		Attempts:   8,
+		LastError:  sql.NullString{String: "webhook receiver answered 500", Valid: true},
+		LastStatus: sql.NullInt32{Int32: 500, Valid: true},
+		DeadAt:     sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true},
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+
+	testcases := []struct {
+		name          string
+		method        string
+		url           string
+		cursor        *pageCursor
+		buildStubs    func(transaction *dbmock.MockTransaction)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List first page",
+			method: http.MethodGet,
+			url:    "/api/v1/webhooks/" + fmt.Sprint(hook.ID) + "/dead_letters?limit=1",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(hook, nil)
+				arg := database.ListDeadWebhookDeliveriesParams{WebhookID: hook.ID, BeforeID: math.MaxInt64, PageSize: 1}
+				transaction.EXPECT().ListDeadWebhookDeliveries(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]database.WebhookDeliveries{dead}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp deadLettersResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.Len(t, resp.Deliveries, 1)
+				require.Equal(t, int32(500), resp.Deliveries[0].LastStatus)
+				require.JSONEq(t, string(dead.Payload), string(resp.Deliveries[0].Payload))
+				require.Equal(t, pageCursor{List: list, ID: dead.ID}, cursorPayload(t, resp.NextCursor))
+			},
+		},
+		{
+			name:   "Cursor of another webhook",
+			method: http.MethodGet,
+			url:    "/api/v1/webhooks/" + fmt.Sprint(hook.ID) + "/dead_letters",
+			cursor: &pageCursor{List: "dead_letters:" + fmt.Sprint(hook.ID+1), ID: 9},
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().ListDeadWebhookDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "List of someone else's webhook",
+			method: http.MethodGet,
+			url:    "/api/v1/webhooks/" + fmt.Sprint(hook.ID) + "/dead_letters",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(database.Webhooks{}, sql.ErrNoRows)
+				transaction.EXPECT().ListDeadWebhookDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Retry OK",
+			method: http.MethodPost,
+			url:    "/api/v1/webhooks/" + fmt.Sprint(hook.ID) + "/dead_letters/" + fmt.Sprint(dead.ID) + "/retry",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(hook, nil)
+				arg := database.RetryWebhookDeliveryParams{ID: dead.ID, WebhookID: hook.ID}
+				transaction.EXPECT().RetryWebhookDelivery(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Retry not dead",
+			method: http.MethodPost,
+			url:    "/api/v1/webhooks/" + fmt.Sprint(hook.ID) + "/dead_letters/" + fmt.Sprint(dead.ID) + "/retry",
+			buildStubs: func(transaction *dbmock.MockTransaction) {
+				transaction.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(hook, nil)
+				transaction.EXPECT().RetryWebhookDelivery(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+		transaction := dbmock.NewMockTransaction(controller)
+		testcase.buildStubs(transaction)
+
+		server := NewTestServer(t, transaction)
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(testcase.method, withCursor(server, testcase.url, testcase.cursor), nil)
+		require.NoError(t, err)
+
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, req)
+		testcase.checkResponse(t, recorder)
+	}
+}
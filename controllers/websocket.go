@@ -0,0 +1,426 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/stream"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait = 10 * time.Second
+	// wsPongWait is how long a client may stay silent, pings go out more often
+	wsPongWait         = 60 * time.Second
+	wsPingPeriod       = wsPongWait * 9 / 10
+	wsMaxMessageSize   = 4096
+	wsSendBuffer       = 64
+	wsMaxSubscriptions = 20
+	// wsTokenProtocol is offered by clients that can't set headers on a handshake,
+	// like browsers, with their token as the next protocol
+	wsTokenProtocol = "access_token"
+)
+
+var (
+	errUnknownTopic         = errors.New("unknown topic")
+	errAlreadySubscribed    = errors.New("already subscribed to this topic")
+	errNotSubscribed        = errors.New("not subscribed to this topic")
+	errTooManySubscriptions = fmt.Errorf("at most %v subscriptions per connection", wsMaxSubscriptions)
+	errShuttingDown         = errors.New("server is shutting down")
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// only the protocol name is echoed back, never the token
+	Subprotocols: []string{wsTokenProtocol},
+}
+
+// wsClientMessage is what clients send. Topics are home, notifications, dms and
+// tweet:<id>:replies; last_event_id resumes a subscription like in GET /stream/home.
+type wsClientMessage struct {
+	Type        string `json:"type"`
+	ID          string `json:"id,omitempty"`
+	Topic       string `json:"topic,omitempty"`
+	LastEventID uint64 `json:"last_event_id,omitempty"`
+}
+
+// wsServerMessage is what the server sends, ID echoes the client message answered
+type wsServerMessage struct {
+	Type   string        `json:"type"`
+	ID     string        `json:"id,omitempty"`
+	Topic  string        `json:"topic,omitempty"`
+	Event  *stream.Event `json:"event,omitempty"`
+	Missed bool          `json:"missed,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// repliesTopic carries the replies to a tweet and dmsTopic the messages, read
+// receipts and encrypted envelopes of a user's conversations, notificationsTopic is
+// in notifications.go
+func repliesTopic(tweetID int64) string { return "replies:" + strconv.FormatInt(tweetID, 10) }
+func dmsTopic(username string) string   { return "dms:" + username }
+
+// wsTopics resolves a client topic into the hub topics it covers, home is
+// subscribed with subscribeHome
+func (s *Server) wsTopics(c *gin.Context, username, topic string) ([]string, error) {
+	switch topic {
+	case "notifications":
+		return []string{notificationsTopic(username)}, nil
+	case "dms":
+		return []string{dmsTopic(username)}, nil
+	}
+
+	parts := strings.Split(topic, ":")
+	if len(parts) != 3 || parts[0] != "tweet" || parts[2] != "replies" {
+		return nil, errUnknownTopic
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id < 1 {
+		return nil, errUnknownTopic
+	}
+	tweet, _, _, err := s.visibleTweet(c, id, username)
+	if err != nil {
+		return nil, err
+	}
+	return []string{repliesTopic(tweet.ID)}, nil
+}
+
+// ProtocolTokenMiddleware lets clients that can't set headers on a WebSocket
+// handshake send their token in Sec-WebSocket-Protocol, after the access_token
+// protocol. It's not taken from the query string, URLs end up in access logs.
+func ProtocolTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(authorizationHeaderKey) != "" {
+			return
+		}
+		protocols := websocket.Subprotocols(c.Request)
+		for i, protocol := range protocols {
+			if protocol == wsTokenProtocol && i+1 < len(protocols) {
+				c.Request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+protocols[i+1])
+				return
+			}
+		}
+	}
+}
+
+// ServeWebSocket upgrades to a WebSocket where the caller subscribes to topics and
+// receives their events, until either side closes or the server drains
+func (s *Server) ServeWebSocket(c *gin.Context) {
+	if s.sockets.isDraining() {
+		c.JSON(http.StatusServiceUnavailable, ErrResponse(errShuttingDown.Error()))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		//the upgrader already answered
+		return
+	}
+
+	authHeader := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	client := &wsClient{
+		server:    s,
+		ctx:       c,
+		conn:      conn,
+		username:  authHeader.Username,
+		send:      make(chan wsServerMessage, wsSendBuffer),
+		done:      make(chan struct{}),
+		goingAway: make(chan struct{}),
+		subs:      map[string]*stream.Subscription{},
+	}
+	if !s.sockets.add(client) {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, errShuttingDown.Error()), time.Now().Add(wsWriteWait))
+		conn.Close()
+		return
+	}
+	defer s.sockets.remove(client)
+
+	written := make(chan struct{})
+	go func() {
+		client.writePump()
+		close(written)
+	}()
+	client.readPump()
+	client.stop()
+	<-written
+}
+
+type wsClient struct {
+	server   *Server
+	ctx      *gin.Context
+	conn     *websocket.Conn
+	username string
+	send     chan wsServerMessage
+
+	// done is closed once the client stops reading, goingAway when the server drains
+	done       chan struct{}
+	stopOnce   sync.Once
+	goingAway  chan struct{}
+	goAwayOnce sync.Once
+
+	mu   sync.Mutex
+	subs map[string]*stream.Subscription
+}
+
+func (w *wsClient) readPump() {
+	w.conn.SetReadLimit(wsMaxMessageSize)
+	w.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	w.conn.SetPongHandler(func(string) error {
+		return w.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := w.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		w.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		var msg wsClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			w.reply(wsServerMessage{Type: "error", Error: "message is not valid JSON"})
+			continue
+		}
+		w.handle(msg)
+	}
+}
+
+func (w *wsClient) handle(msg wsClientMessage) {
+	switch msg.Type {
+	case "subscribe":
+		err := w.subscribe(msg)
+		if err != nil {
+			w.reply(wsServerMessage{Type: "error", ID: msg.ID, Topic: msg.Topic, Error: err.Error()})
+		}
+	case "unsubscribe":
+		w.mu.Lock()
+		sub, ok := w.subs[msg.Topic]
+		delete(w.subs, msg.Topic)
+		w.mu.Unlock()
+		if !ok {
+			w.reply(wsServerMessage{Type: "error", ID: msg.ID, Topic: msg.Topic, Error: errNotSubscribed.Error()})
+			return
+		}
+		sub.Close()
+		w.reply(wsServerMessage{Type: "unsubscribed", ID: msg.ID, Topic: msg.Topic})
+	case "ping":
+		w.reply(wsServerMessage{Type: "pong", ID: msg.ID})
+	default:
+		w.reply(wsServerMessage{Type: "error", ID: msg.ID, Error: fmt.Sprintf("unknown message type : %v", msg.Type)})
+	}
+}
+
+func (w *wsClient) subscribe(msg wsClientMessage) error {
+	w.mu.Lock()
+	_, exists := w.subs[msg.Topic]
+	count := len(w.subs)
+	w.mu.Unlock()
+	if exists {
+		return errAlreadySubscribed
+	}
+	if count >= wsMaxSubscriptions {
+		return errTooManySubscriptions
+	}
+
+	var sub *stream.Subscription
+	if msg.Topic == "home" {
+		var err error
+		sub, err = w.server.subscribeHome(w.ctx, w.username, msg.LastEventID)
+		if err != nil {
+			return err
+		}
+	} else {
+		topics, err := w.server.wsTopics(w.ctx, w.username, msg.Topic)
+		if err != nil {
+			return err
+		}
+		sub = w.server.hub.Subscribe(topics, msg.LastEventID)
+	}
+
+	//only the read loop subscribes, nothing took the topic in between
+	w.mu.Lock()
+	w.subs[msg.Topic] = sub
+	w.mu.Unlock()
+
+	w.reply(wsServerMessage{Type: "subscribed", ID: msg.ID, Topic: msg.Topic, Missed: sub.Missed()})
+	go w.forward(msg.Topic, sub)
+	return nil
+}
+
+// forward relays the events of a subscription. A client that doesn't read fast
+// enough blocks it until the hub drops the subscription, the client is then told
+// to subscribe again from its last event id.
+func (w *wsClient) forward(topic string, sub *stream.Subscription) {
+	for {
+		select {
+		case event := <-sub.Events():
+			if !w.visible(event) {
+				continue
+			}
+			if !w.reply(wsServerMessage{Type: "event", Topic: topic, Event: &event}) {
+				return
+			}
+		case <-sub.Done():
+			//the socket is closed with going away when the hub shuts down
+			err := sub.Err()
+			if err == nil || err == stream.ErrHubClosed {
+				return
+			}
+			w.mu.Lock()
+			if w.subs[topic] == sub {
+				delete(w.subs, topic)
+			}
+			w.mu.Unlock()
+			w.reply(wsServerMessage{Type: "error", Topic: topic, Error: err.Error()})
+			return
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// visible tells whether the client may see an event. Replies go out to everyone
+// following the parent tweet, their authors are checked for each subscriber.
+func (w *wsClient) visible(event stream.Event) bool {
+	reply, ok := event.Data.(tweetResponse)
+	if !ok || !strings.HasPrefix(event.Topic, "replies:") {
+		return true
+	}
+	author, err := w.server.transaction.GetUser(w.ctx, reply.Username)
+	if err != nil {
+		return false
+	}
+	ok, err = w.server.canView(w.ctx, author, w.username)
+	return err == nil && ok
+}
+
+// reply queues a message for the write pump, false once the client is gone
+func (w *wsClient) reply(msg wsServerMessage) bool {
+	select {
+	case w.send <- msg:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func (w *wsClient) writePump() {
+	ping := time.NewTicker(wsPingPeriod)
+	defer ping.Stop()
+	defer w.conn.Close()
+
+	for {
+		select {
+		case msg := <-w.send:
+			w.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := w.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := w.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-w.goingAway:
+			//give the client a moment to answer the close before hanging up
+			closing := websocket.FormatCloseMessage(websocket.CloseGoingAway, errShuttingDown.Error())
+			if err := w.conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+			select {
+			case <-w.done:
+			case <-time.After(wsWriteWait):
+			}
+			return
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// stop ends the subscriptions once the client stopped reading
+func (w *wsClient) stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for topic, sub := range w.subs {
+		sub.Close()
+		delete(w.subs, topic)
+	}
+}
+
+func (w *wsClient) goAway() {
+	w.goAwayOnce.Do(func() {
+		close(w.goingAway)
+	})
+}
+
+// wsRegistry tracks the open WebSockets, the http server forgets them once they're
+// hijacked
+type wsRegistry struct {
+	mu       sync.Mutex
+	clients  map[*wsClient]struct{}
+	draining bool
+	wg       sync.WaitGroup
+}
+
+func (r *wsRegistry) isDraining() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.draining
+}
+
+func (r *wsRegistry) add(client *wsClient) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.draining {
+		return false
+	}
+	if r.clients == nil {
+		r.clients = map[*wsClient]struct{}{}
+	}
+	r.clients[client] = struct{}{}
+	r.wg.Add(1)
+	return true
+}
+
+func (r *wsRegistry) remove(client *wsClient) {
+	r.mu.Lock()
+	delete(r.clients, client)
+	r.mu.Unlock()
+	r.wg.Done()
+}
+
+// drain asks every client to go away and waits for them until ctx is done
+func (r *wsRegistry) drain(ctx context.Context) error {
+	r.mu.Lock()
+	r.draining = true
+	for client := range r.clients {
+		client.goAway()
+	}
+	r.mu.Unlock()
+
+	closed := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
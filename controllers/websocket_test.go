@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func dialWebSocket(t *testing.T, server *Server, url, username string) *websocket.Conn {
+	header := http.Header{}
+	if username != "" {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		AddAuth(t, req, server.paseto, authorizationTypeBearer, username, time.Minute)
+		header = req.Header
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(url), header)
+	require.NoError(t, err)
+	return conn
+}
+
+func wsURL(url string) string {
+	return "ws" + strings.TrimPrefix(url, "http") + "/api/v1/ws"
+}
+
+func readWebSocket(t *testing.T, conn *websocket.Conn) wsServerMessage {
+	var msg wsServerMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebSocket(t *testing.T) {
+	user, _ := randomUser(t)
+	followed, _ := randomUser(t)
+	author, _ := randomUser(t)
+	tweet := randomTweets(author)
+	blocker, _ := randomUser(t)
+	hidden := randomTweets(blocker)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().ListFollowing(gomock.Any(), gomock.Any()).AnyTimes().
+		Return([]database.ListFollowingRow{{RelationID: 1, Username: followed.Username}}, nil)
+	transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(tweet.ID)).Times(1).Return(tweet, nil)
+	transaction.EXPECT().GetTweet(gomock.Any(), gomock.Eq(int64(404))).Times(1).Return(database.Tweets{}, sql.ErrNoRows)
+	//the parent tweet on subscribe, then each reply's author
+	transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(author.Username)).Times(2).Return(author, nil)
+	transaction.EXPECT().GetUser(gomock.Any(), gomock.Eq(blocker.Username)).Times(1).Return(blocker, nil)
+	transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Eq(database.IsBlockedParams{BlockerUsername: blocker.Username, BlockedUsername: user.Username})).
+		Times(1).Return(true, nil)
+	transaction.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Times(2).Return(false, nil)
+
+	server := NewTestServer(t, transaction)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	//unauthenticated handshakes are refused
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(httpServer.URL), nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	conn := dialWebSocket(t, server, httpServer.URL, user.Username)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "ping", ID: "1"}))
+	msg := readWebSocket(t, conn)
+	require.Equal(t, "pong", msg.Type)
+	require.Equal(t, "1", msg.ID)
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "subscribe", ID: "2", Topic: "home"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "subscribed", msg.Type)
+	require.Equal(t, "2", msg.ID)
+	require.False(t, msg.Missed)
+
+	server.hub.Publish(tweetsTopic(followed.Username), eventTweet, tweet)
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "event", msg.Type)
+	require.Equal(t, "home", msg.Topic)
+	require.NotNil(t, msg.Event)
+	require.Equal(t, eventTweet, msg.Event.Type)
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "subscribe", ID: "3", Topic: "home"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "error", msg.Type)
+	require.Equal(t, errAlreadySubscribed.Error(), msg.Error)
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "subscribe", ID: "4", Topic: "tweet:1:replies"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "subscribed", msg.Type)
+
+	//replies of authors who blocked the subscriber are held back
+	server.hub.Publish(repliesTopic(tweet.ID), eventTweet, newTweetResponse(hidden, nil))
+	server.hub.Publish(repliesTopic(tweet.ID), eventTweet, newTweetResponse(tweet, nil))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "event", msg.Type)
+	require.Equal(t, "tweet:1:replies", msg.Topic)
+	require.Equal(t, author.Username, msg.Event.Data.(map[string]interface{})["username"])
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "subscribe", ID: "5", Topic: "tweet:404:replies"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "error", msg.Type)
+	require.Equal(t, sql.ErrNoRows.Error(), msg.Error)
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "subscribe", ID: "6", Topic: "everything"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "error", msg.Type)
+	require.Equal(t, errUnknownTopic.Error(), msg.Error)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "error", msg.Type)
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "unsubscribe", ID: "7", Topic: "home"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "unsubscribed", msg.Type)
+	require.Equal(t, "home", msg.Topic)
+
+	//nothing arrives once unsubscribed, the pong proves the event was skipped
+	server.hub.Publish(tweetsTopic(followed.Username), eventTweet, tweet)
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "ping", ID: "8"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "pong", msg.Type)
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "unsubscribe", ID: "9", Topic: "home"}))
+	msg = readWebSocket(t, conn)
+	require.Equal(t, "error", msg.Type)
+	require.Equal(t, errNotSubscribed.Error(), msg.Error)
+}
+
+func TestWebSocketProtocolToken(t *testing.T) {
+	user, _ := randomUser(t)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+
+	server := NewTestServer(t, transaction)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	accessToken, err := server.paseto.CreateToken(user.Username, time.Minute)
+	require.NoError(t, err)
+	//tokens in the url would be logged
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(httpServer.URL)+"?access_token="+accessToken, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	dialer := websocket.Dialer{Subprotocols: []string{wsTokenProtocol, accessToken}}
+	conn, resp, err := dialer.Dial(wsURL(httpServer.URL), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, wsTokenProtocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "subscribe", Topic: "dms"}))
+	msg := readWebSocket(t, conn)
+	require.Equal(t, "subscribed", msg.Type)
+}
+
+func TestWebSocketShutdown(t *testing.T) {
+	user, _ := randomUser(t)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	transaction := dbmock.NewMockTransaction(controller)
+
+	server := NewTestServer(t, transaction)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	conn := dialWebSocket(t, server, httpServer.URL, user.Username)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(wsClientMessage{Type: "subscribe", Topic: "notifications"}))
+	msg := readWebSocket(t, conn)
+	require.Equal(t, "subscribed", msg.Type)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown(ctx)
+	}()
+
+	//the client answers the going away close and the drain completes
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+			break
+		}
+	}
+	require.NoError(t, <-shutdown)
+
+	//new sockets are turned away while draining
+	req, err := http.NewRequest(http.MethodGet, httpServer.URL, nil)
+	require.NoError(t, err)
+	AddAuth(t, req, server.paseto, authorizationTypeBearer, user.Username, time.Minute)
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(httpServer.URL), req.Header)
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
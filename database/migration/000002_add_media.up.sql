@@ -0,0 +1,19 @@
+CREATE TABLE "media" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "tweet_id" bigint,
+  "position" int NOT NULL DEFAULT 0,
+  "mime_type" varchar NOT NULL,
+  "size_bytes" bigint NOT NULL,
+  "width" int NOT NULL DEFAULT 0,
+  "height" int NOT NULL DEFAULT 0,
+  "storage_key" varchar NOT NULL,
+  "thumbnail_key" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "media" ("tweet_id", "position");
+
+ALTER TABLE "media" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "media" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tweet_revisions;
+ALTER TABLE "tweets" DROP COLUMN IF EXISTS "edited_at";
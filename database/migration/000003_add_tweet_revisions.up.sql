@@ -0,0 +1,13 @@
+ALTER TABLE "tweets" ADD COLUMN "edited_at" timestamptz;
+
+CREATE TABLE "tweet_revisions" (
+  "id" bigserial PRIMARY KEY,
+  "tweet_id" bigint NOT NULL,
+  "tweet" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL,
+  "replaced_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "tweet_revisions" ("tweet_id");
+
+ALTER TABLE "tweet_revisions" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
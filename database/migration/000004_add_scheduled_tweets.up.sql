@@ -0,0 +1,16 @@
+CREATE TABLE "scheduled_tweets" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "tweet" varchar NOT NULL,
+  "media_ids" bigint[] NOT NULL DEFAULT '{}',
+  "publish_at" timestamptz NOT NULL,
+  "failed_at" timestamptz,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_tweets" ("username", "publish_at");
+
+CREATE INDEX ON "scheduled_tweets" ("publish_at") WHERE "failed_at" IS NULL;
+
+ALTER TABLE "scheduled_tweets" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
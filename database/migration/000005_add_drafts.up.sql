@@ -0,0 +1,12 @@
+CREATE TABLE "drafts" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "tweet" varchar NOT NULL DEFAULT '',
+  "media_ids" bigint[] NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "drafts" ("username", "updated_at");
+
+ALTER TABLE "drafts" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
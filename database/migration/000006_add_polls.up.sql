@@ -0,0 +1,41 @@
+CREATE TABLE "polls" (
+  "id" bigserial PRIMARY KEY,
+  "tweet_id" bigint UNIQUE NOT NULL,
+  "closes_at" timestamptz NOT NULL,
+  "closed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "poll_options" (
+  "id" bigserial PRIMARY KEY,
+  "poll_id" bigint NOT NULL,
+  "position" int NOT NULL,
+  "label" varchar NOT NULL,
+  "votes" int NOT NULL DEFAULT 0
+);
+
+CREATE TABLE "poll_votes" (
+  "poll_id" bigint NOT NULL,
+  "option_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("poll_id", "username")
+);
+
+CREATE INDEX ON "polls" ("closes_at") WHERE "closed_at" IS NULL;
+
+CREATE UNIQUE INDEX ON "poll_options" ("poll_id", "position");
+
+CREATE INDEX ON "poll_votes" ("option_id");
+
+CREATE INDEX ON "poll_votes" ("username");
+
+ALTER TABLE "polls" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "poll_options" ADD FOREIGN KEY ("poll_id") REFERENCES "polls" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "poll_votes" ADD FOREIGN KEY ("poll_id") REFERENCES "polls" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "poll_votes" ADD FOREIGN KEY ("option_id") REFERENCES "poll_options" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "poll_votes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
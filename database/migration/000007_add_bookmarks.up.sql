@@ -0,0 +1,16 @@
+CREATE TABLE "bookmarks" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "tweet_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "bookmarks" ("username", "tweet_id");
+
+CREATE INDEX ON "bookmarks" ("username", "id");
+
+CREATE INDEX ON "bookmarks" ("tweet_id");
+
+ALTER TABLE "bookmarks" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "bookmarks" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "pinned_tweet_id" bigint;
+
+ALTER TABLE "users" ADD FOREIGN KEY ("pinned_tweet_id") REFERENCES "tweets" ("id") ON DELETE SET NULL;
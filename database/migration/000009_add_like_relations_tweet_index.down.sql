@@ -0,0 +1 @@
+DROP INDEX IF EXISTS like_relations_tweet_id_id_idx;
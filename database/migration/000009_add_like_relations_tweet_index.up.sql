@@ -0,0 +1 @@
+CREATE INDEX "like_relations_tweet_id_id_idx" ON "like_relations" ("tweet_id", "id");
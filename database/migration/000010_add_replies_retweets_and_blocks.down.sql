@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS blocks;
+
+ALTER TABLE users DROP COLUMN IF EXISTS protected;
+
+ALTER TABLE tweets DROP COLUMN IF EXISTS retweet_of_id;
+
+ALTER TABLE tweets DROP COLUMN IF EXISTS in_reply_to_id;
@@ -0,0 +1,26 @@
+ALTER TABLE "tweets" ADD COLUMN "in_reply_to_id" bigint;
+
+ALTER TABLE "tweets" ADD COLUMN "retweet_of_id" bigint;
+
+ALTER TABLE "users" ADD COLUMN "protected" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "blocks" (
+  "blocker_username" varchar NOT NULL,
+  "blocked_username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("blocker_username", "blocked_username")
+);
+
+CREATE INDEX ON "tweets" ("in_reply_to_id");
+
+CREATE UNIQUE INDEX ON "tweets" ("username", "retweet_of_id") WHERE "retweet_of_id" IS NOT NULL;
+
+CREATE INDEX ON "blocks" ("blocked_username");
+
+ALTER TABLE "tweets" ADD FOREIGN KEY ("in_reply_to_id") REFERENCES "tweets" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "tweets" ADD FOREIGN KEY ("retweet_of_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "blocks" ADD FOREIGN KEY ("blocker_username") REFERENCES "users" ("username");
+
+ALTER TABLE "blocks" ADD FOREIGN KEY ("blocked_username") REFERENCES "users" ("username");
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS tweets_username_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX "tweets_username_id_idx" ON "tweets" ("username", "id");
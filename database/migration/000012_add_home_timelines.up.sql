@@ -0,0 +1,37 @@
+CREATE TABLE "home_timelines" (
+  "username" varchar NOT NULL,
+  "tweet_id" bigint NOT NULL,
+  "author_username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "tweet_id")
+);
+
+CREATE TABLE "timeline_fanouts" (
+  "tweet_id" bigint PRIMARY KEY,
+  "author_username" varchar NOT NULL,
+  "fan_in" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "home_timelines" ("username", "author_username");
+
+CREATE INDEX ON "home_timelines" ("tweet_id");
+
+CREATE INDEX ON "timeline_fanouts" ("tweet_id") WHERE NOT "fan_in";
+
+CREATE INDEX ON "timeline_fanouts" ("author_username", "tweet_id") WHERE "fan_in";
+
+ALTER TABLE "home_timelines" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "home_timelines" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "timeline_fanouts" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
+
+-- existing tweets are materialized once, new ones go through the fan-out worker
+INSERT INTO "home_timelines" ("username", "tweet_id", "author_username")
+SELECT "relations"."follower_username", "tweets"."id", "tweets"."username"
+FROM "tweets"
+JOIN "relations" ON "relations"."followed_username" = "tweets"."username"
+UNION
+SELECT "tweets"."username", "tweets"."id", "tweets"."username"
+FROM "tweets";
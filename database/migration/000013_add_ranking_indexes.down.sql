@@ -0,0 +1 @@
+DROP INDEX IF EXISTS tweets_retweet_of_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX "tweets_retweet_of_id_idx" ON "tweets" ("retweet_of_id") WHERE "retweet_of_id" IS NOT NULL;
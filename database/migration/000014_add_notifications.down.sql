@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS notification_actors;
+
+DROP TABLE IF EXISTS notifications;
+
+DROP SEQUENCE IF EXISTS notifications_seq;
@@ -0,0 +1,37 @@
+-- seq orders notifications by their latest activity, a group moves back to the top
+-- when someone joins it
+CREATE SEQUENCE "notifications_seq";
+
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "group_key" varchar NOT NULL,
+  "tweet_id" bigint,
+  "seq" bigint NOT NULL DEFAULT (nextval('notifications_seq')),
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "notification_actors" (
+  "notification_id" bigint NOT NULL,
+  "actor_username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("notification_id", "actor_username")
+);
+
+-- one unread group per key, once read the next activity starts a new group
+CREATE UNIQUE INDEX ON "notifications" ("username", "group_key") WHERE "read_at" IS NULL;
+
+CREATE UNIQUE INDEX ON "notifications" ("username", "seq");
+
+CREATE INDEX ON "notifications" ("tweet_id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "notification_actors" ADD FOREIGN KEY ("notification_id") REFERENCES "notifications" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "notification_actors" ADD FOREIGN KEY ("actor_username") REFERENCES "users" ("username");
@@ -0,0 +1,16 @@
+-- users without a row take every notification at any time, quiet hours are minutes
+-- after midnight in time_zone and may wrap past midnight
+CREATE TABLE "notification_preferences" (
+  "username" varchar PRIMARY KEY,
+  "likes" boolean NOT NULL DEFAULT true,
+  "follows" boolean NOT NULL DEFAULT true,
+  "mentions" boolean NOT NULL DEFAULT true,
+  "replies" boolean NOT NULL DEFAULT true,
+  "only_following" boolean NOT NULL DEFAULT false,
+  "quiet_hours_start" integer,
+  "quiet_hours_end" integer,
+  "time_zone" varchar NOT NULL DEFAULT 'UTC',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
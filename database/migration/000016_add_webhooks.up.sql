@@ -0,0 +1,34 @@
+CREATE TABLE "webhooks" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "events" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- a delivery is pending until delivered_at or dead_at is set, dead ones make up the
+-- dead-letter list
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "webhook_id" bigint NOT NULL,
+  "event" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_error" varchar,
+  "last_status" integer,
+  "delivered_at" timestamptz,
+  "dead_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhooks" ("username");
+
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at", "id") WHERE "delivered_at" IS NULL AND "dead_at" IS NULL;
+
+CREATE INDEX ON "webhook_deliveries" ("webhook_id", "id") WHERE "dead_at" IS NOT NULL;
+
+ALTER TABLE "webhooks" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id") ON DELETE CASCADE;
@@ -0,0 +1,22 @@
+-- domain events written in the transaction that caused them, the relay publishes
+-- the unpublished ones in id order
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "aggregate_key" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz
+);
+
+-- events a consumer has already handled, delivery is at-least-once
+CREATE TABLE "processed_events" (
+  "consumer" varchar NOT NULL,
+  "event_id" bigint NOT NULL,
+  "processed_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("consumer", "event_id")
+);
+
+CREATE INDEX ON "outbox" ("id") WHERE "published_at" IS NULL;
+
+CREATE INDEX ON "outbox" ("published_at") WHERE "published_at" IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS dm_settings;
+
+DROP TABLE IF EXISTS messages;
+
+DROP TABLE IF EXISTS conversation_participants;
+
+DROP TABLE IF EXISTS conversations;
@@ -0,0 +1,46 @@
+-- one-to-one conversations have a direct_key made of both usernames, last_message_id
+-- orders the inbox
+CREATE TABLE "conversations" (
+  "id" bigserial PRIMARY KEY,
+  "direct_key" varchar UNIQUE,
+  "last_message_id" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- last_read_message_id is the participant's read receipt
+CREATE TABLE "conversation_participants" (
+  "conversation_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "last_read_message_id" bigint NOT NULL DEFAULT 0,
+  "joined_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("conversation_id", "username")
+);
+
+CREATE TABLE "messages" (
+  "id" bigserial PRIMARY KEY,
+  "conversation_id" bigint NOT NULL,
+  "sender_username" varchar NOT NULL,
+  "body" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- who can start a conversation with the user, no row is everyone
+CREATE TABLE "dm_settings" (
+  "username" varchar PRIMARY KEY,
+  "allow_from" varchar NOT NULL DEFAULT 'everyone',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "conversation_participants" ("username");
+
+CREATE INDEX ON "messages" ("conversation_id", "id");
+
+ALTER TABLE "conversation_participants" ADD FOREIGN KEY ("conversation_id") REFERENCES "conversations" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "conversation_participants" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "messages" ADD FOREIGN KEY ("conversation_id") REFERENCES "conversations" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "messages" ADD FOREIGN KEY ("sender_username") REFERENCES "users" ("username");
+
+ALTER TABLE "dm_settings" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,9 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE conversation_participants DROP COLUMN IF EXISTS role;
+
+ALTER TABLE conversations DROP COLUMN IF EXISTS created_by;
+
+ALTER TABLE conversations DROP COLUMN IF EXISTS title;
+
+ALTER TABLE conversations DROP COLUMN IF EXISTS is_group;
@@ -0,0 +1,13 @@
+-- group conversations have a title and no direct_key, admins manage their members
+ALTER TABLE "conversations" ADD COLUMN "is_group" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "conversations" ADD COLUMN "title" varchar;
+
+ALTER TABLE "conversations" ADD COLUMN "created_by" varchar;
+
+ALTER TABLE "conversation_participants" ADD COLUMN "role" varchar NOT NULL DEFAULT 'member';
+
+-- system messages record membership changes, their sender is whoever made the change
+ALTER TABLE "messages" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'text';
+
+ALTER TABLE "conversations" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS envelopes;
+
+DROP TABLE IF EXISTS one_time_prekeys;
+
+DROP TABLE IF EXISTS devices;
@@ -0,0 +1,45 @@
+-- a device publishes its X25519 identity key, the Ed25519 key its prekeys are signed
+-- with and its current signed prekey. Private keys never reach the server.
+CREATE TABLE "devices" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "identity_key" bytea NOT NULL,
+  "signing_key" bytea NOT NULL,
+  "signed_prekey_id" bigint NOT NULL,
+  "signed_prekey" bytea NOT NULL,
+  "signed_prekey_signature" bytea NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- one-time prekeys are handed out in a single prekey bundle each, then deleted
+CREATE TABLE "one_time_prekeys" (
+  "device_id" bigint NOT NULL,
+  "key_id" bigint NOT NULL,
+  "public_key" bytea NOT NULL,
+  PRIMARY KEY ("device_id", "key_id")
+);
+
+-- envelopes keep ciphertext for one device until it acknowledges them, the server
+-- can't read it
+CREATE TABLE "envelopes" (
+  "id" bigserial PRIMARY KEY,
+  "recipient_device_id" bigint NOT NULL,
+  "sender_username" varchar NOT NULL,
+  "sender_device_id" bigint NOT NULL,
+  "ciphertext" bytea NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "devices" ("username");
+
+CREATE INDEX ON "envelopes" ("recipient_device_id", "id");
+
+ALTER TABLE "devices" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "one_time_prekeys" ADD FOREIGN KEY ("device_id") REFERENCES "devices" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "envelopes" ADD FOREIGN KEY ("recipient_device_id") REFERENCES "devices" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "envelopes" ADD FOREIGN KEY ("sender_username") REFERENCES "users" ("username");
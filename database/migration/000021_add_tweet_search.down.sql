@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS tweet_search_update ON tweets;
+
+DROP FUNCTION IF EXISTS tweet_search_update;
+
+DROP FUNCTION IF EXISTS tweet_search_vector;
+
+DROP TABLE IF EXISTS tweet_search;
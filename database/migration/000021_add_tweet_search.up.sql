@@ -0,0 +1,34 @@
+-- tweet_search holds the english lexemes of a tweet and its hashtags whole, like
+-- '#golang', so searching a hashtag doesn't match the plain word. It's kept out of
+-- tweets so the tweet queries don't carry the vector around.
+CREATE TABLE "tweet_search" (
+  "tweet_id" bigint PRIMARY KEY,
+  "search_vector" tsvector NOT NULL
+);
+
+CREATE FUNCTION tweet_search_vector(tweet text) RETURNS tsvector AS $$
+  SELECT to_tsvector('english', tweet) || array_to_tsvector(ARRAY(
+    SELECT DISTINCT '#' || lower(hashtag[1])
+    FROM regexp_matches(tweet, '#([[:alnum:]_]+)', 'g') AS hashtag
+  ))
+$$ LANGUAGE sql;
+
+CREATE FUNCTION tweet_search_update() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO tweet_search (tweet_id, search_vector)
+  VALUES (NEW.id, tweet_search_vector(NEW.tweet))
+  ON CONFLICT (tweet_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "tweet_search_update" AFTER INSERT OR UPDATE OF "tweet" ON "tweets"
+FOR EACH ROW EXECUTE FUNCTION tweet_search_update();
+
+-- the tweets already there
+INSERT INTO "tweet_search" ("tweet_id", "search_vector")
+SELECT "id", tweet_search_vector("tweet") FROM "tweets";
+
+CREATE INDEX "tweet_search_vector_idx" ON "tweet_search" USING GIN ("search_vector");
+
+ALTER TABLE "tweet_search" ADD FOREIGN KEY ("tweet_id") REFERENCES "tweets" ("id") ON DELETE CASCADE;
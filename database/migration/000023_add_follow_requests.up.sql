@@ -0,0 +1,14 @@
+CREATE TABLE "follow_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester_username" varchar NOT NULL,
+  "target_username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "follow_requests" ("requester_username", "target_username");
+
+CREATE INDEX ON "follow_requests" ("target_username", "id");
+
+ALTER TABLE "follow_requests" ADD FOREIGN KEY ("requester_username") REFERENCES "users" ("username");
+
+ALTER TABLE "follow_requests" ADD FOREIGN KEY ("target_username") REFERENCES "users" ("username");
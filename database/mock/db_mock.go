@@ -6,7 +6,9 @@ package dbmock
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
 	gomock "github.com/golang/mock/gomock"
@@ -35,330 +37,2370 @@ func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
 	return m.recorder
 }
 
+// AddConversationParticipant mocks base method.
+func (m *MockTransaction) AddConversationParticipant(arg0 context.Context, arg1 database.AddConversationParticipantParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddConversationParticipant", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddConversationParticipant indicates an expected call of AddConversationParticipant.
+func (mr *MockTransactionMockRecorder) AddConversationParticipant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConversationParticipant", reflect.TypeOf((*MockTransaction)(nil).AddConversationParticipant), arg0, arg1)
+}
+
+// AddGroupMembersTx mocks base method.
+func (m *MockTransaction) AddGroupMembersTx(arg0 context.Context, arg1 database.AddGroupMembersTxParams) (database.GroupChangeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGroupMembersTx", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupChangeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddGroupMembersTx indicates an expected call of AddGroupMembersTx.
+func (mr *MockTransactionMockRecorder) AddGroupMembersTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGroupMembersTx", reflect.TypeOf((*MockTransaction)(nil).AddGroupMembersTx), arg0, arg1)
+}
+
+// AddGroupParticipant mocks base method.
+func (m *MockTransaction) AddGroupParticipant(arg0 context.Context, arg1 database.AddGroupParticipantParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGroupParticipant", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddGroupParticipant indicates an expected call of AddGroupParticipant.
+func (mr *MockTransactionMockRecorder) AddGroupParticipant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGroupParticipant", reflect.TypeOf((*MockTransaction)(nil).AddGroupParticipant), arg0, arg1)
+}
+
+// AddNotificationActor mocks base method.
+func (m *MockTransaction) AddNotificationActor(arg0 context.Context, arg1 database.AddNotificationActorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotificationActor", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNotificationActor indicates an expected call of AddNotificationActor.
+func (mr *MockTransactionMockRecorder) AddNotificationActor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotificationActor", reflect.TypeOf((*MockTransaction)(nil).AddNotificationActor), arg0, arg1)
+}
+
+// AddOneTimePrekey mocks base method.
+func (m *MockTransaction) AddOneTimePrekey(arg0 context.Context, arg1 database.AddOneTimePrekeyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOneTimePrekey", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOneTimePrekey indicates an expected call of AddOneTimePrekey.
+func (mr *MockTransactionMockRecorder) AddOneTimePrekey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOneTimePrekey", reflect.TypeOf((*MockTransaction)(nil).AddOneTimePrekey), arg0, arg1)
+}
+
+// AddPrekeysTx mocks base method.
+func (m *MockTransaction) AddPrekeysTx(arg0 context.Context, arg1 database.AddPrekeysTxParams) (database.DeviceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrekeysTx", arg0, arg1)
+	ret0, _ := ret[0].(database.DeviceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPrekeysTx indicates an expected call of AddPrekeysTx.
+func (mr *MockTransactionMockRecorder) AddPrekeysTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrekeysTx", reflect.TypeOf((*MockTransaction)(nil).AddPrekeysTx), arg0, arg1)
+}
+
+// AddToHomeTimeline mocks base method.
+func (m *MockTransaction) AddToHomeTimeline(arg0 context.Context, arg1 database.AddToHomeTimelineParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToHomeTimeline", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToHomeTimeline indicates an expected call of AddToHomeTimeline.
+func (mr *MockTransactionMockRecorder) AddToHomeTimeline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToHomeTimeline", reflect.TypeOf((*MockTransaction)(nil).AddToHomeTimeline), arg0, arg1)
+}
+
+// ApproveFollowRequestTx mocks base method.
+func (m *MockTransaction) ApproveFollowRequestTx(arg0 context.Context, arg1 database.FollowInputArgs) (database.FollowInputResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveFollowRequestTx", arg0, arg1)
+	ret0, _ := ret[0].(database.FollowInputResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveFollowRequestTx indicates an expected call of ApproveFollowRequestTx.
+func (mr *MockTransactionMockRecorder) ApproveFollowRequestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveFollowRequestTx", reflect.TypeOf((*MockTransaction)(nil).ApproveFollowRequestTx), arg0, arg1)
+}
+
+// AttachMedia mocks base method.
+func (m *MockTransaction) AttachMedia(arg0 context.Context, arg1 database.AttachMediaParams) (database.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachMedia", arg0, arg1)
+	ret0, _ := ret[0].(database.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachMedia indicates an expected call of AttachMedia.
+func (mr *MockTransactionMockRecorder) AttachMedia(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachMedia", reflect.TypeOf((*MockTransaction)(nil).AttachMedia), arg0, arg1)
+}
+
+// BackfillHomeTimeline mocks base method.
+func (m *MockTransaction) BackfillHomeTimeline(arg0 context.Context, arg1 database.BackfillHomeTimelineParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillHomeTimeline", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillHomeTimeline indicates an expected call of BackfillHomeTimeline.
+func (mr *MockTransactionMockRecorder) BackfillHomeTimeline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillHomeTimeline", reflect.TypeOf((*MockTransaction)(nil).BackfillHomeTimeline), arg0, arg1)
+}
+
+// BlockTx mocks base method.
+func (m *MockTransaction) BlockTx(arg0 context.Context, arg1 database.BlockTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockTx indicates an expected call of BlockTx.
+func (mr *MockTransactionMockRecorder) BlockTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockTx", reflect.TypeOf((*MockTransaction)(nil).BlockTx), arg0, arg1)
+}
+
+// CancelScheduledTweet mocks base method.
+func (m *MockTransaction) CancelScheduledTweet(arg0 context.Context, arg1 database.CancelScheduledTweetParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledTweet", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledTweet indicates an expected call of CancelScheduledTweet.
+func (mr *MockTransactionMockRecorder) CancelScheduledTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledTweet", reflect.TypeOf((*MockTransaction)(nil).CancelScheduledTweet), arg0, arg1)
+}
+
+// ClaimOneTimePrekey mocks base method.
+func (m *MockTransaction) ClaimOneTimePrekey(arg0 context.Context, arg1 int64) (database.OneTimePrekeys, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOneTimePrekey", arg0, arg1)
+	ret0, _ := ret[0].(database.OneTimePrekeys)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOneTimePrekey indicates an expected call of ClaimOneTimePrekey.
+func (mr *MockTransactionMockRecorder) ClaimOneTimePrekey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOneTimePrekey", reflect.TypeOf((*MockTransaction)(nil).ClaimOneTimePrekey), arg0, arg1)
+}
+
+// ClaimPrekeyBundlesTx mocks base method.
+func (m *MockTransaction) ClaimPrekeyBundlesTx(arg0 context.Context, arg1 database.ClaimPrekeyBundlesTxParams) ([]database.PrekeyBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPrekeyBundlesTx", arg0, arg1)
+	ret0, _ := ret[0].([]database.PrekeyBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPrekeyBundlesTx indicates an expected call of ClaimPrekeyBundlesTx.
+func (mr *MockTransactionMockRecorder) ClaimPrekeyBundlesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPrekeyBundlesTx", reflect.TypeOf((*MockTransaction)(nil).ClaimPrekeyBundlesTx), arg0, arg1)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockTransaction) ClaimWebhookDeliveries(arg0 context.Context, arg1 database.ClaimWebhookDeliveriesParams) ([]database.ClaimWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]database.ClaimWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockTransactionMockRecorder) ClaimWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockTransaction)(nil).ClaimWebhookDeliveries), arg0, arg1)
+}
+
+// ClosePoll mocks base method.
+func (m *MockTransaction) ClosePoll(arg0 context.Context, arg1 int64) (database.Polls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePoll", arg0, arg1)
+	ret0, _ := ret[0].(database.Polls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePoll indicates an expected call of ClosePoll.
+func (mr *MockTransactionMockRecorder) ClosePoll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePoll", reflect.TypeOf((*MockTransaction)(nil).ClosePoll), arg0, arg1)
+}
+
+// ClosePollTx mocks base method.
+func (m *MockTransaction) ClosePollTx(arg0 context.Context) (database.TweetPoll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePollTx", arg0)
+	ret0, _ := ret[0].(database.TweetPoll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePollTx indicates an expected call of ClosePollTx.
+func (mr *MockTransactionMockRecorder) ClosePollTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePollTx", reflect.TypeOf((*MockTransaction)(nil).ClosePollTx), arg0)
+}
+
+// CountConversationAdmins mocks base method.
+func (m *MockTransaction) CountConversationAdmins(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountConversationAdmins", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountConversationAdmins indicates an expected call of CountConversationAdmins.
+func (mr *MockTransactionMockRecorder) CountConversationAdmins(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountConversationAdmins", reflect.TypeOf((*MockTransaction)(nil).CountConversationAdmins), arg0, arg1)
+}
+
+// CountDevices mocks base method.
+func (m *MockTransaction) CountDevices(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDevices", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDevices indicates an expected call of CountDevices.
+func (mr *MockTransactionMockRecorder) CountDevices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDevices", reflect.TypeOf((*MockTransaction)(nil).CountDevices), arg0, arg1)
+}
+
+// CountOneTimePrekeys mocks base method.
+func (m *MockTransaction) CountOneTimePrekeys(arg0 context.Context, arg1 []int64) ([]database.CountOneTimePrekeysRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOneTimePrekeys", arg0, arg1)
+	ret0, _ := ret[0].([]database.CountOneTimePrekeysRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOneTimePrekeys indicates an expected call of CountOneTimePrekeys.
+func (mr *MockTransactionMockRecorder) CountOneTimePrekeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOneTimePrekeys", reflect.TypeOf((*MockTransaction)(nil).CountOneTimePrekeys), arg0, arg1)
+}
+
+// CountUnreadConversations mocks base method.
+func (m *MockTransaction) CountUnreadConversations(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadConversations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadConversations indicates an expected call of CountUnreadConversations.
+func (mr *MockTransactionMockRecorder) CountUnreadConversations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadConversations", reflect.TypeOf((*MockTransaction)(nil).CountUnreadConversations), arg0, arg1)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockTransaction) CountUnreadNotifications(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockTransactionMockRecorder) CountUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockTransaction)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// CreateBlock mocks base method.
+func (m *MockTransaction) CreateBlock(arg0 context.Context, arg1 database.CreateBlockParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlock", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBlock indicates an expected call of CreateBlock.
+func (mr *MockTransactionMockRecorder) CreateBlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlock", reflect.TypeOf((*MockTransaction)(nil).CreateBlock), arg0, arg1)
+}
+
+// CreateBookmark mocks base method.
+func (m *MockTransaction) CreateBookmark(arg0 context.Context, arg1 database.CreateBookmarkParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookmark", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBookmark indicates an expected call of CreateBookmark.
+func (mr *MockTransactionMockRecorder) CreateBookmark(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookmark", reflect.TypeOf((*MockTransaction)(nil).CreateBookmark), arg0, arg1)
+}
+
+// CreateDevice mocks base method.
+func (m *MockTransaction) CreateDevice(arg0 context.Context, arg1 database.CreateDeviceParams) (database.Devices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDevice", arg0, arg1)
+	ret0, _ := ret[0].(database.Devices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDevice indicates an expected call of CreateDevice.
+func (mr *MockTransactionMockRecorder) CreateDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDevice", reflect.TypeOf((*MockTransaction)(nil).CreateDevice), arg0, arg1)
+}
+
+// CreateDraft mocks base method.
+func (m *MockTransaction) CreateDraft(arg0 context.Context, arg1 database.CreateDraftParams) (database.Drafts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDraft", arg0, arg1)
+	ret0, _ := ret[0].(database.Drafts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDraft indicates an expected call of CreateDraft.
+func (mr *MockTransactionMockRecorder) CreateDraft(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDraft", reflect.TypeOf((*MockTransaction)(nil).CreateDraft), arg0, arg1)
+}
+
+// CreateEnvelope mocks base method.
+func (m *MockTransaction) CreateEnvelope(arg0 context.Context, arg1 database.CreateEnvelopeParams) (database.Envelopes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEnvelope", arg0, arg1)
+	ret0, _ := ret[0].(database.Envelopes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEnvelope indicates an expected call of CreateEnvelope.
+func (mr *MockTransactionMockRecorder) CreateEnvelope(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEnvelope", reflect.TypeOf((*MockTransaction)(nil).CreateEnvelope), arg0, arg1)
+}
+
+// CreateFollowRequest mocks base method.
+func (m *MockTransaction) CreateFollowRequest(arg0 context.Context, arg1 database.CreateFollowRequestParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFollowRequest", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFollowRequest indicates an expected call of CreateFollowRequest.
+func (mr *MockTransactionMockRecorder) CreateFollowRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFollowRequest", reflect.TypeOf((*MockTransaction)(nil).CreateFollowRequest), arg0, arg1)
+}
+
+// CreateGroupConversation mocks base method.
+func (m *MockTransaction) CreateGroupConversation(arg0 context.Context, arg1 database.CreateGroupConversationParams) (database.Conversations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupConversation", arg0, arg1)
+	ret0, _ := ret[0].(database.Conversations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroupConversation indicates an expected call of CreateGroupConversation.
+func (mr *MockTransactionMockRecorder) CreateGroupConversation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupConversation", reflect.TypeOf((*MockTransaction)(nil).CreateGroupConversation), arg0, arg1)
+}
+
+// CreateGroupConversationTx mocks base method.
+func (m *MockTransaction) CreateGroupConversationTx(arg0 context.Context, arg1 database.CreateGroupConversationTxParams) (database.GroupChangeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupConversationTx", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupChangeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroupConversationTx indicates an expected call of CreateGroupConversationTx.
+func (mr *MockTransactionMockRecorder) CreateGroupConversationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupConversationTx", reflect.TypeOf((*MockTransaction)(nil).CreateGroupConversationTx), arg0, arg1)
+}
+
 // CreateLikeRelation mocks base method.
 func (m *MockTransaction) CreateLikeRelation(arg0 context.Context, arg1 database.CreateLikeRelationParams) (database.LikeRelations, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateLikeRelation", arg0, arg1)
-	ret0, _ := ret[0].(database.LikeRelations)
+	ret := m.ctrl.Call(m, "CreateLikeRelation", arg0, arg1)
+	ret0, _ := ret[0].(database.LikeRelations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLikeRelation indicates an expected call of CreateLikeRelation.
+func (mr *MockTransactionMockRecorder) CreateLikeRelation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLikeRelation", reflect.TypeOf((*MockTransaction)(nil).CreateLikeRelation), arg0, arg1)
+}
+
+// CreateMedia mocks base method.
+func (m *MockTransaction) CreateMedia(arg0 context.Context, arg1 database.CreateMediaParams) (database.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMedia", arg0, arg1)
+	ret0, _ := ret[0].(database.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMedia indicates an expected call of CreateMedia.
+func (mr *MockTransactionMockRecorder) CreateMedia(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMedia", reflect.TypeOf((*MockTransaction)(nil).CreateMedia), arg0, arg1)
+}
+
+// CreateMessage mocks base method.
+func (m *MockTransaction) CreateMessage(arg0 context.Context, arg1 database.CreateMessageParams) (database.Messages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", arg0, arg1)
+	ret0, _ := ret[0].(database.Messages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockTransactionMockRecorder) CreateMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockTransaction)(nil).CreateMessage), arg0, arg1)
+}
+
+// CreatePoll mocks base method.
+func (m *MockTransaction) CreatePoll(arg0 context.Context, arg1 database.CreatePollParams) (database.Polls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePoll", arg0, arg1)
+	ret0, _ := ret[0].(database.Polls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePoll indicates an expected call of CreatePoll.
+func (mr *MockTransactionMockRecorder) CreatePoll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoll", reflect.TypeOf((*MockTransaction)(nil).CreatePoll), arg0, arg1)
+}
+
+// CreatePollOption mocks base method.
+func (m *MockTransaction) CreatePollOption(arg0 context.Context, arg1 database.CreatePollOptionParams) (database.PollOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePollOption", arg0, arg1)
+	ret0, _ := ret[0].(database.PollOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePollOption indicates an expected call of CreatePollOption.
+func (mr *MockTransactionMockRecorder) CreatePollOption(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePollOption", reflect.TypeOf((*MockTransaction)(nil).CreatePollOption), arg0, arg1)
+}
+
+// CreatePollVote mocks base method.
+func (m *MockTransaction) CreatePollVote(arg0 context.Context, arg1 database.CreatePollVoteParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePollVote", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePollVote indicates an expected call of CreatePollVote.
+func (mr *MockTransactionMockRecorder) CreatePollVote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePollVote", reflect.TypeOf((*MockTransaction)(nil).CreatePollVote), arg0, arg1)
+}
+
+// CreateRelations mocks base method.
+func (m *MockTransaction) CreateRelations(arg0 context.Context, arg1 database.CreateRelationsParams) (database.Relations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRelations", arg0, arg1)
+	ret0, _ := ret[0].(database.Relations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRelations indicates an expected call of CreateRelations.
+func (mr *MockTransactionMockRecorder) CreateRelations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRelations", reflect.TypeOf((*MockTransaction)(nil).CreateRelations), arg0, arg1)
+}
+
+// CreateScheduledTweet mocks base method.
+func (m *MockTransaction) CreateScheduledTweet(arg0 context.Context, arg1 database.CreateScheduledTweetParams) (database.ScheduledTweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTweet", arg0, arg1)
+	ret0, _ := ret[0].(database.ScheduledTweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTweet indicates an expected call of CreateScheduledTweet.
+func (mr *MockTransactionMockRecorder) CreateScheduledTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTweet", reflect.TypeOf((*MockTransaction)(nil).CreateScheduledTweet), arg0, arg1)
+}
+
+// CreateTimelineFanout mocks base method.
+func (m *MockTransaction) CreateTimelineFanout(arg0 context.Context, arg1 database.CreateTimelineFanoutParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTimelineFanout", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTimelineFanout indicates an expected call of CreateTimelineFanout.
+func (mr *MockTransactionMockRecorder) CreateTimelineFanout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTimelineFanout", reflect.TypeOf((*MockTransaction)(nil).CreateTimelineFanout), arg0, arg1)
+}
+
+// CreateTweet mocks base method.
+func (m *MockTransaction) CreateTweet(arg0 context.Context, arg1 database.CreateTweetParams) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTweet", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTweet indicates an expected call of CreateTweet.
+func (mr *MockTransactionMockRecorder) CreateTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTweet", reflect.TypeOf((*MockTransaction)(nil).CreateTweet), arg0, arg1)
+}
+
+// CreateTweetRevision mocks base method.
+func (m *MockTransaction) CreateTweetRevision(arg0 context.Context, arg1 database.CreateTweetRevisionParams) (database.TweetRevisions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTweetRevision", arg0, arg1)
+	ret0, _ := ret[0].(database.TweetRevisions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTweetRevision indicates an expected call of CreateTweetRevision.
+func (mr *MockTransactionMockRecorder) CreateTweetRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTweetRevision", reflect.TypeOf((*MockTransaction)(nil).CreateTweetRevision), arg0, arg1)
+}
+
+// CreateTweetTx mocks base method.
+func (m *MockTransaction) CreateTweetTx(arg0 context.Context, arg1 database.CreateTweetTxParams) (database.CreateTweetTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTweetTx", arg0, arg1)
+	ret0, _ := ret[0].(database.CreateTweetTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTweetTx indicates an expected call of CreateTweetTx.
+func (mr *MockTransactionMockRecorder) CreateTweetTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTweetTx", reflect.TypeOf((*MockTransaction)(nil).CreateTweetTx), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockTransaction) CreateUser(arg0 context.Context, arg1 database.CreateUserParams) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockTransactionMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockTransaction)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockTransaction) CreateWebhook(arg0 context.Context, arg1 database.CreateWebhookParams) (database.Webhooks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhooks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockTransactionMockRecorder) CreateWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockTransaction)(nil).CreateWebhook), arg0, arg1)
+}
+
+// DecrementFollower mocks base method.
+func (m *MockTransaction) DecrementFollower(arg0 context.Context, arg1 string) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementFollower", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementFollower indicates an expected call of DecrementFollower.
+func (mr *MockTransactionMockRecorder) DecrementFollower(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementFollower", reflect.TypeOf((*MockTransaction)(nil).DecrementFollower), arg0, arg1)
+}
+
+// DecrementFollowing mocks base method.
+func (m *MockTransaction) DecrementFollowing(arg0 context.Context, arg1 string) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementFollowing", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementFollowing indicates an expected call of DecrementFollowing.
+func (mr *MockTransactionMockRecorder) DecrementFollowing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementFollowing", reflect.TypeOf((*MockTransaction)(nil).DecrementFollowing), arg0, arg1)
+}
+
+// DecrementLike mocks base method.
+func (m *MockTransaction) DecrementLike(arg0 context.Context, arg1 int64) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementLike", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementLike indicates an expected call of DecrementLike.
+func (mr *MockTransactionMockRecorder) DecrementLike(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementLike", reflect.TypeOf((*MockTransaction)(nil).DecrementLike), arg0, arg1)
+}
+
+// DeleteBlock mocks base method.
+func (m *MockTransaction) DeleteBlock(arg0 context.Context, arg1 database.DeleteBlockParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlock", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBlock indicates an expected call of DeleteBlock.
+func (mr *MockTransactionMockRecorder) DeleteBlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlock", reflect.TypeOf((*MockTransaction)(nil).DeleteBlock), arg0, arg1)
+}
+
+// DeleteBookmark mocks base method.
+func (m *MockTransaction) DeleteBookmark(arg0 context.Context, arg1 database.DeleteBookmarkParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookmark", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBookmark indicates an expected call of DeleteBookmark.
+func (mr *MockTransactionMockRecorder) DeleteBookmark(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookmark", reflect.TypeOf((*MockTransaction)(nil).DeleteBookmark), arg0, arg1)
+}
+
+// DeleteDevice mocks base method.
+func (m *MockTransaction) DeleteDevice(arg0 context.Context, arg1 database.DeleteDeviceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDevice", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDevice indicates an expected call of DeleteDevice.
+func (mr *MockTransactionMockRecorder) DeleteDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDevice", reflect.TypeOf((*MockTransaction)(nil).DeleteDevice), arg0, arg1)
+}
+
+// DeleteDraft mocks base method.
+func (m *MockTransaction) DeleteDraft(arg0 context.Context, arg1 database.DeleteDraftParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDraft", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDraft indicates an expected call of DeleteDraft.
+func (mr *MockTransactionMockRecorder) DeleteDraft(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDraft", reflect.TypeOf((*MockTransaction)(nil).DeleteDraft), arg0, arg1)
+}
+
+// DeleteEnvelopes mocks base method.
+func (m *MockTransaction) DeleteEnvelopes(arg0 context.Context, arg1 database.DeleteEnvelopesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEnvelopes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEnvelopes indicates an expected call of DeleteEnvelopes.
+func (mr *MockTransactionMockRecorder) DeleteEnvelopes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEnvelopes", reflect.TypeOf((*MockTransaction)(nil).DeleteEnvelopes), arg0, arg1)
+}
+
+// DeleteFollowRequest mocks base method.
+func (m *MockTransaction) DeleteFollowRequest(arg0 context.Context, arg1 database.DeleteFollowRequestParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFollowRequest", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFollowRequest indicates an expected call of DeleteFollowRequest.
+func (mr *MockTransactionMockRecorder) DeleteFollowRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFollowRequest", reflect.TypeOf((*MockTransaction)(nil).DeleteFollowRequest), arg0, arg1)
+}
+
+// DeleteLikeRelation mocks base method.
+func (m *MockTransaction) DeleteLikeRelation(arg0 context.Context, arg1 database.DeleteLikeRelationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLikeRelation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLikeRelation indicates an expected call of DeleteLikeRelation.
+func (mr *MockTransactionMockRecorder) DeleteLikeRelation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLikeRelation", reflect.TypeOf((*MockTransaction)(nil).DeleteLikeRelation), arg0, arg1)
+}
+
+// DeleteRelation mocks base method.
+func (m *MockTransaction) DeleteRelation(arg0 context.Context, arg1 database.DeleteRelationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRelation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRelation indicates an expected call of DeleteRelation.
+func (mr *MockTransactionMockRecorder) DeleteRelation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRelation", reflect.TypeOf((*MockTransaction)(nil).DeleteRelation), arg0, arg1)
+}
+
+// DeleteScheduledTweet mocks base method.
+func (m *MockTransaction) DeleteScheduledTweet(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledTweet", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduledTweet indicates an expected call of DeleteScheduledTweet.
+func (mr *MockTransactionMockRecorder) DeleteScheduledTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledTweet", reflect.TypeOf((*MockTransaction)(nil).DeleteScheduledTweet), arg0, arg1)
+}
+
+// DeleteTimelineFanout mocks base method.
+func (m *MockTransaction) DeleteTimelineFanout(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTimelineFanout", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTimelineFanout indicates an expected call of DeleteTimelineFanout.
+func (mr *MockTransactionMockRecorder) DeleteTimelineFanout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTimelineFanout", reflect.TypeOf((*MockTransaction)(nil).DeleteTimelineFanout), arg0, arg1)
+}
+
+// DeleteTweet mocks base method.
+func (m *MockTransaction) DeleteTweet(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTweet", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTweet indicates an expected call of DeleteTweet.
+func (mr *MockTransactionMockRecorder) DeleteTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTweet", reflect.TypeOf((*MockTransaction)(nil).DeleteTweet), arg0, arg1)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockTransaction) DeleteWebhook(arg0 context.Context, arg1 database.DeleteWebhookParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockTransactionMockRecorder) DeleteWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockTransaction)(nil).DeleteWebhook), arg0, arg1)
+}
+
+// EditTweetTx mocks base method.
+func (m *MockTransaction) EditTweetTx(arg0 context.Context, arg1 database.EditTweetTxParams) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditTweetTx", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditTweetTx indicates an expected call of EditTweetTx.
+func (mr *MockTransactionMockRecorder) EditTweetTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditTweetTx", reflect.TypeOf((*MockTransaction)(nil).EditTweetTx), arg0, arg1)
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockTransaction) EnqueueWebhookDeliveries(arg0 context.Context, arg1 database.EnqueueWebhookDeliveriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockTransactionMockRecorder) EnqueueWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockTransaction)(nil).EnqueueWebhookDeliveries), arg0, arg1)
+}
+
+// FailWebhookDelivery mocks base method.
+func (m *MockTransaction) FailWebhookDelivery(arg0 context.Context, arg1 database.FailWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailWebhookDelivery indicates an expected call of FailWebhookDelivery.
+func (mr *MockTransactionMockRecorder) FailWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailWebhookDelivery", reflect.TypeOf((*MockTransaction)(nil).FailWebhookDelivery), arg0, arg1)
+}
+
+// FanOutTweet mocks base method.
+func (m *MockTransaction) FanOutTweet(arg0 context.Context, arg1 database.FanOutTweetParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FanOutTweet", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FanOutTweet indicates an expected call of FanOutTweet.
+func (mr *MockTransactionMockRecorder) FanOutTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FanOutTweet", reflect.TypeOf((*MockTransaction)(nil).FanOutTweet), arg0, arg1)
+}
+
+// FanoutTweetTx mocks base method.
+func (m *MockTransaction) FanoutTweetTx(arg0 context.Context, arg1 int32) (database.TimelineFanouts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FanoutTweetTx", arg0, arg1)
+	ret0, _ := ret[0].(database.TimelineFanouts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FanoutTweetTx indicates an expected call of FanoutTweetTx.
+func (mr *MockTransactionMockRecorder) FanoutTweetTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FanoutTweetTx", reflect.TypeOf((*MockTransaction)(nil).FanoutTweetTx), arg0, arg1)
+}
+
+// FinalizePollOptions mocks base method.
+func (m *MockTransaction) FinalizePollOptions(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizePollOptions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinalizePollOptions indicates an expected call of FinalizePollOptions.
+func (mr *MockTransactionMockRecorder) FinalizePollOptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizePollOptions", reflect.TypeOf((*MockTransaction)(nil).FinalizePollOptions), arg0, arg1)
+}
+
+// FollowTx mocks base method.
+func (m *MockTransaction) FollowTx(arg0 context.Context, arg1 database.FollowInputArgs) (database.FollowInputResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowTx", arg0, arg1)
+	ret0, _ := ret[0].(database.FollowInputResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowTx indicates an expected call of FollowTx.
+func (mr *MockTransactionMockRecorder) FollowTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowTx", reflect.TypeOf((*MockTransaction)(nil).FollowTx), arg0, arg1)
+}
+
+// GetConversationForUpdate mocks base method.
+func (m *MockTransaction) GetConversationForUpdate(arg0 context.Context, arg1 int64) (database.Conversations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversationForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.Conversations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversationForUpdate indicates an expected call of GetConversationForUpdate.
+func (mr *MockTransactionMockRecorder) GetConversationForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversationForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetConversationForUpdate), arg0, arg1)
+}
+
+// GetConversationParticipant mocks base method.
+func (m *MockTransaction) GetConversationParticipant(arg0 context.Context, arg1 database.GetConversationParticipantParams) (database.ConversationParticipants, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversationParticipant", arg0, arg1)
+	ret0, _ := ret[0].(database.ConversationParticipants)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversationParticipant indicates an expected call of GetConversationParticipant.
+func (mr *MockTransactionMockRecorder) GetConversationParticipant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversationParticipant", reflect.TypeOf((*MockTransaction)(nil).GetConversationParticipant), arg0, arg1)
+}
+
+// GetDMSettings mocks base method.
+func (m *MockTransaction) GetDMSettings(arg0 context.Context, arg1 string) (database.DmSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDMSettings", arg0, arg1)
+	ret0, _ := ret[0].(database.DmSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDMSettings indicates an expected call of GetDMSettings.
+func (mr *MockTransactionMockRecorder) GetDMSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDMSettings", reflect.TypeOf((*MockTransaction)(nil).GetDMSettings), arg0, arg1)
+}
+
+// GetDevice mocks base method.
+func (m *MockTransaction) GetDevice(arg0 context.Context, arg1 int64) (database.Devices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDevice", arg0, arg1)
+	ret0, _ := ret[0].(database.Devices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDevice indicates an expected call of GetDevice.
+func (mr *MockTransactionMockRecorder) GetDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDevice", reflect.TypeOf((*MockTransaction)(nil).GetDevice), arg0, arg1)
+}
+
+// GetDraftForUpdate mocks base method.
+func (m *MockTransaction) GetDraftForUpdate(arg0 context.Context, arg1 database.GetDraftForUpdateParams) (database.Drafts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDraftForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.Drafts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDraftForUpdate indicates an expected call of GetDraftForUpdate.
+func (mr *MockTransactionMockRecorder) GetDraftForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDraftForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetDraftForUpdate), arg0, arg1)
+}
+
+// GetDuePollForUpdate mocks base method.
+func (m *MockTransaction) GetDuePollForUpdate(arg0 context.Context) (database.Polls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuePollForUpdate", arg0)
+	ret0, _ := ret[0].(database.Polls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuePollForUpdate indicates an expected call of GetDuePollForUpdate.
+func (mr *MockTransactionMockRecorder) GetDuePollForUpdate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuePollForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetDuePollForUpdate), arg0)
+}
+
+// GetDueScheduledTweetForUpdate mocks base method.
+func (m *MockTransaction) GetDueScheduledTweetForUpdate(arg0 context.Context) (database.ScheduledTweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueScheduledTweetForUpdate", arg0)
+	ret0, _ := ret[0].(database.ScheduledTweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueScheduledTweetForUpdate indicates an expected call of GetDueScheduledTweetForUpdate.
+func (mr *MockTransactionMockRecorder) GetDueScheduledTweetForUpdate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueScheduledTweetForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetDueScheduledTweetForUpdate), arg0)
+}
+
+// GetLikeRelation mocks base method.
+func (m *MockTransaction) GetLikeRelation(arg0 context.Context, arg1 database.GetLikeRelationParams) (database.LikeRelations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLikeRelation", arg0, arg1)
+	ret0, _ := ret[0].(database.LikeRelations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLikeRelation indicates an expected call of GetLikeRelation.
+func (mr *MockTransactionMockRecorder) GetLikeRelation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLikeRelation", reflect.TypeOf((*MockTransaction)(nil).GetLikeRelation), arg0, arg1)
+}
+
+// GetMaterializedHomeTimeline mocks base method.
+func (m *MockTransaction) GetMaterializedHomeTimeline(arg0 context.Context, arg1 database.GetMaterializedHomeTimelineParams) ([]database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaterializedHomeTimeline", arg0, arg1)
+	ret0, _ := ret[0].([]database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaterializedHomeTimeline indicates an expected call of GetMaterializedHomeTimeline.
+func (mr *MockTransactionMockRecorder) GetMaterializedHomeTimeline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaterializedHomeTimeline", reflect.TypeOf((*MockTransaction)(nil).GetMaterializedHomeTimeline), arg0, arg1)
+}
+
+// GetMedia mocks base method.
+func (m *MockTransaction) GetMedia(arg0 context.Context, arg1 int64) (database.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMedia", arg0, arg1)
+	ret0, _ := ret[0].(database.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMedia indicates an expected call of GetMedia.
+func (mr *MockTransactionMockRecorder) GetMedia(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMedia", reflect.TypeOf((*MockTransaction)(nil).GetMedia), arg0, arg1)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockTransaction) GetNotificationPreferences(arg0 context.Context, arg1 string) (database.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockTransactionMockRecorder) GetNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockTransaction)(nil).GetNotificationPreferences), arg0, arg1)
+}
+
+// GetPendingFanoutForUpdate mocks base method.
+func (m *MockTransaction) GetPendingFanoutForUpdate(arg0 context.Context) (database.TimelineFanouts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingFanoutForUpdate", arg0)
+	ret0, _ := ret[0].(database.TimelineFanouts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingFanoutForUpdate indicates an expected call of GetPendingFanoutForUpdate.
+func (mr *MockTransactionMockRecorder) GetPendingFanoutForUpdate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingFanoutForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetPendingFanoutForUpdate), arg0)
+}
+
+// GetPoll mocks base method.
+func (m *MockTransaction) GetPoll(arg0 context.Context, arg1 int64) (database.Polls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoll", arg0, arg1)
+	ret0, _ := ret[0].(database.Polls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoll indicates an expected call of GetPoll.
+func (mr *MockTransactionMockRecorder) GetPoll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoll", reflect.TypeOf((*MockTransaction)(nil).GetPoll), arg0, arg1)
+}
+
+// GetPollForVote mocks base method.
+func (m *MockTransaction) GetPollForVote(arg0 context.Context, arg1 int64) (database.Polls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPollForVote", arg0, arg1)
+	ret0, _ := ret[0].(database.Polls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPollForVote indicates an expected call of GetPollForVote.
+func (mr *MockTransactionMockRecorder) GetPollForVote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPollForVote", reflect.TypeOf((*MockTransaction)(nil).GetPollForVote), arg0, arg1)
+}
+
+// GetRelations mocks base method.
+func (m *MockTransaction) GetRelations(arg0 context.Context, arg1 database.GetRelationsParams) (database.Relations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelations", arg0, arg1)
+	ret0, _ := ret[0].(database.Relations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelations indicates an expected call of GetRelations.
+func (mr *MockTransactionMockRecorder) GetRelations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelations", reflect.TypeOf((*MockTransaction)(nil).GetRelations), arg0, arg1)
+}
+
+// GetTweet mocks base method.
+func (m *MockTransaction) GetTweet(arg0 context.Context, arg1 int64) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTweet", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTweet indicates an expected call of GetTweet.
+func (mr *MockTransactionMockRecorder) GetTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTweet", reflect.TypeOf((*MockTransaction)(nil).GetTweet), arg0, arg1)
+}
+
+// GetTweetForUpdate mocks base method.
+func (m *MockTransaction) GetTweetForUpdate(arg0 context.Context, arg1 int64) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTweetForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTweetForUpdate indicates an expected call of GetTweetForUpdate.
+func (mr *MockTransactionMockRecorder) GetTweetForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTweetForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetTweetForUpdate), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockTransaction) GetUser(arg0 context.Context, arg1 string) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockTransactionMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockTransaction)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserForUpdate mocks base method.
+func (m *MockTransaction) GetUserForUpdate(arg0 context.Context, arg1 string) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForUpdate indicates an expected call of GetUserForUpdate.
+func (mr *MockTransactionMockRecorder) GetUserForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetUserForUpdate), arg0, arg1)
+}
+
+// GetWebhook mocks base method.
+func (m *MockTransaction) GetWebhook(arg0 context.Context, arg1 database.GetWebhookParams) (database.Webhooks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhooks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockTransactionMockRecorder) GetWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockTransaction)(nil).GetWebhook), arg0, arg1)
+}
+
+// HasProcessedEvent mocks base method.
+func (m *MockTransaction) HasProcessedEvent(arg0 context.Context, arg1 database.HasProcessedEventParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasProcessedEvent", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasProcessedEvent indicates an expected call of HasProcessedEvent.
+func (mr *MockTransactionMockRecorder) HasProcessedEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasProcessedEvent", reflect.TypeOf((*MockTransaction)(nil).HasProcessedEvent), arg0, arg1)
+}
+
+// HasSentMessage mocks base method.
+func (m *MockTransaction) HasSentMessage(arg0 context.Context, arg1 database.HasSentMessageParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSentMessage", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasSentMessage indicates an expected call of HasSentMessage.
+func (mr *MockTransactionMockRecorder) HasSentMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSentMessage", reflect.TypeOf((*MockTransaction)(nil).HasSentMessage), arg0, arg1)
+}
+
+// IncrementFollower mocks base method.
+func (m *MockTransaction) IncrementFollower(arg0 context.Context, arg1 string) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementFollower", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementFollower indicates an expected call of IncrementFollower.
+func (mr *MockTransactionMockRecorder) IncrementFollower(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementFollower", reflect.TypeOf((*MockTransaction)(nil).IncrementFollower), arg0, arg1)
+}
+
+// IncrementFollowing mocks base method.
+func (m *MockTransaction) IncrementFollowing(arg0 context.Context, arg1 string) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementFollowing", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementFollowing indicates an expected call of IncrementFollowing.
+func (mr *MockTransactionMockRecorder) IncrementFollowing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementFollowing", reflect.TypeOf((*MockTransaction)(nil).IncrementFollowing), arg0, arg1)
+}
+
+// IncrementLike mocks base method.
+func (m *MockTransaction) IncrementLike(arg0 context.Context, arg1 int64) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementLike", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementLike indicates an expected call of IncrementLike.
+func (mr *MockTransactionMockRecorder) IncrementLike(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLike", reflect.TypeOf((*MockTransaction)(nil).IncrementLike), arg0, arg1)
+}
+
+// IncrementPollOptionVotes mocks base method.
+func (m *MockTransaction) IncrementPollOptionVotes(arg0 context.Context, arg1 database.IncrementPollOptionVotesParams) (database.PollOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementPollOptionVotes", arg0, arg1)
+	ret0, _ := ret[0].(database.PollOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementPollOptionVotes indicates an expected call of IncrementPollOptionVotes.
+func (mr *MockTransactionMockRecorder) IncrementPollOptionVotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementPollOptionVotes", reflect.TypeOf((*MockTransaction)(nil).IncrementPollOptionVotes), arg0, arg1)
+}
+
+// InsertOutboxEvent mocks base method.
+func (m *MockTransaction) InsertOutboxEvent(arg0 context.Context, arg1 database.InsertOutboxEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOutboxEvent indicates an expected call of InsertOutboxEvent.
+func (mr *MockTransactionMockRecorder) InsertOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOutboxEvent", reflect.TypeOf((*MockTransaction)(nil).InsertOutboxEvent), arg0, arg1)
+}
+
+// IsBlocked mocks base method.
+func (m *MockTransaction) IsBlocked(arg0 context.Context, arg1 database.IsBlockedParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBlocked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBlocked indicates an expected call of IsBlocked.
+func (mr *MockTransactionMockRecorder) IsBlocked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBlocked", reflect.TypeOf((*MockTransaction)(nil).IsBlocked), arg0, arg1)
+}
+
+// LeaveGroupTx mocks base method.
+func (m *MockTransaction) LeaveGroupTx(arg0 context.Context, arg1 database.GroupMemberTxParams) (database.GroupChangeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveGroupTx", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupChangeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaveGroupTx indicates an expected call of LeaveGroupTx.
+func (mr *MockTransactionMockRecorder) LeaveGroupTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveGroupTx", reflect.TypeOf((*MockTransaction)(nil).LeaveGroupTx), arg0, arg1)
+}
+
+// LikeTweetTx mocks base method.
+func (m *MockTransaction) LikeTweetTx(arg0 context.Context, arg1 database.CreateLikeRelationParams) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LikeTweetTx", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LikeTweetTx indicates an expected call of LikeTweetTx.
+func (mr *MockTransactionMockRecorder) LikeTweetTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LikeTweetTx", reflect.TypeOf((*MockTransaction)(nil).LikeTweetTx), arg0, arg1)
+}
+
+// ListAuthorAffinities mocks base method.
+func (m *MockTransaction) ListAuthorAffinities(arg0 context.Context, arg1 database.ListAuthorAffinitiesParams) ([]database.ListAuthorAffinitiesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorAffinities", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListAuthorAffinitiesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthorAffinities indicates an expected call of ListAuthorAffinities.
+func (mr *MockTransactionMockRecorder) ListAuthorAffinities(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorAffinities", reflect.TypeOf((*MockTransaction)(nil).ListAuthorAffinities), arg0, arg1)
+}
+
+// ListBookmarkedTweetIDs mocks base method.
+func (m *MockTransaction) ListBookmarkedTweetIDs(arg0 context.Context, arg1 database.ListBookmarkedTweetIDsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookmarkedTweetIDs", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookmarkedTweetIDs indicates an expected call of ListBookmarkedTweetIDs.
+func (mr *MockTransactionMockRecorder) ListBookmarkedTweetIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookmarkedTweetIDs", reflect.TypeOf((*MockTransaction)(nil).ListBookmarkedTweetIDs), arg0, arg1)
+}
+
+// ListBookmarks mocks base method.
+func (m *MockTransaction) ListBookmarks(arg0 context.Context, arg1 database.ListBookmarksParams) ([]database.ListBookmarksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookmarks", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListBookmarksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookmarks indicates an expected call of ListBookmarks.
+func (mr *MockTransactionMockRecorder) ListBookmarks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookmarks", reflect.TypeOf((*MockTransaction)(nil).ListBookmarks), arg0, arg1)
+}
+
+// ListConversationParticipants mocks base method.
+func (m *MockTransaction) ListConversationParticipants(arg0 context.Context, arg1 []int64) ([]database.ConversationParticipants, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConversationParticipants", arg0, arg1)
+	ret0, _ := ret[0].([]database.ConversationParticipants)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConversationParticipants indicates an expected call of ListConversationParticipants.
+func (mr *MockTransactionMockRecorder) ListConversationParticipants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConversationParticipants", reflect.TypeOf((*MockTransaction)(nil).ListConversationParticipants), arg0, arg1)
+}
+
+// ListConversations mocks base method.
+func (m *MockTransaction) ListConversations(arg0 context.Context, arg1 database.ListConversationsParams) ([]database.ListConversationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConversations", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListConversationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConversations indicates an expected call of ListConversations.
+func (mr *MockTransactionMockRecorder) ListConversations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConversations", reflect.TypeOf((*MockTransaction)(nil).ListConversations), arg0, arg1)
+}
+
+// ListDeadWebhookDeliveries mocks base method.
+func (m *MockTransaction) ListDeadWebhookDeliveries(arg0 context.Context, arg1 database.ListDeadWebhookDeliveriesParams) ([]database.WebhookDeliveries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDeliveries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadWebhookDeliveries indicates an expected call of ListDeadWebhookDeliveries.
+func (mr *MockTransactionMockRecorder) ListDeadWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadWebhookDeliveries", reflect.TypeOf((*MockTransaction)(nil).ListDeadWebhookDeliveries), arg0, arg1)
+}
+
+// ListDevices mocks base method.
+func (m *MockTransaction) ListDevices(arg0 context.Context, arg1 string) ([]database.Devices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDevices", arg0, arg1)
+	ret0, _ := ret[0].([]database.Devices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDevices indicates an expected call of ListDevices.
+func (mr *MockTransactionMockRecorder) ListDevices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevices", reflect.TypeOf((*MockTransaction)(nil).ListDevices), arg0, arg1)
+}
+
+// ListDrafts mocks base method.
+func (m *MockTransaction) ListDrafts(arg0 context.Context, arg1 string) ([]database.Drafts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDrafts", arg0, arg1)
+	ret0, _ := ret[0].([]database.Drafts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDrafts indicates an expected call of ListDrafts.
+func (mr *MockTransactionMockRecorder) ListDrafts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDrafts", reflect.TypeOf((*MockTransaction)(nil).ListDrafts), arg0, arg1)
+}
+
+// ListFollowRequests mocks base method.
+func (m *MockTransaction) ListFollowRequests(arg0 context.Context, arg1 database.ListFollowRequestsParams) ([]database.ListFollowRequestsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowRequests", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListFollowRequestsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowRequests indicates an expected call of ListFollowRequests.
+func (mr *MockTransactionMockRecorder) ListFollowRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowRequests", reflect.TypeOf((*MockTransaction)(nil).ListFollowRequests), arg0, arg1)
+}
+
+// ListFollowers mocks base method.
+func (m *MockTransaction) ListFollowers(arg0 context.Context, arg1 database.ListFollowersParams) ([]database.ListFollowersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowers", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListFollowersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowers indicates an expected call of ListFollowers.
+func (mr *MockTransactionMockRecorder) ListFollowers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowers", reflect.TypeOf((*MockTransaction)(nil).ListFollowers), arg0, arg1)
+}
+
+// ListFollowing mocks base method.
+func (m *MockTransaction) ListFollowing(arg0 context.Context, arg1 database.ListFollowingParams) ([]database.ListFollowingRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowing", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListFollowingRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowing indicates an expected call of ListFollowing.
+func (mr *MockTransactionMockRecorder) ListFollowing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowing", reflect.TypeOf((*MockTransaction)(nil).ListFollowing), arg0, arg1)
+}
+
+// ListLikedTweets mocks base method.
+func (m *MockTransaction) ListLikedTweets(arg0 context.Context, arg1 database.ListLikedTweetsParams) ([]database.ListLikedTweetsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLikedTweets", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListLikedTweetsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLikedTweets indicates an expected call of ListLikedTweets.
+func (mr *MockTransactionMockRecorder) ListLikedTweets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLikedTweets", reflect.TypeOf((*MockTransaction)(nil).ListLikedTweets), arg0, arg1)
+}
+
+// ListMediaByTweetIDs mocks base method.
+func (m *MockTransaction) ListMediaByTweetIDs(arg0 context.Context, arg1 []int64) ([]database.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMediaByTweetIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMediaByTweetIDs indicates an expected call of ListMediaByTweetIDs.
+func (mr *MockTransactionMockRecorder) ListMediaByTweetIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMediaByTweetIDs", reflect.TypeOf((*MockTransaction)(nil).ListMediaByTweetIDs), arg0, arg1)
+}
+
+// ListMentionableUsernames mocks base method.
+func (m *MockTransaction) ListMentionableUsernames(arg0 context.Context, arg1 database.ListMentionableUsernamesParams) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMentionableUsernames", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMentionableUsernames indicates an expected call of ListMentionableUsernames.
+func (mr *MockTransactionMockRecorder) ListMentionableUsernames(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMentionableUsernames", reflect.TypeOf((*MockTransaction)(nil).ListMentionableUsernames), arg0, arg1)
+}
+
+// ListMessages mocks base method.
+func (m *MockTransaction) ListMessages(arg0 context.Context, arg1 database.ListMessagesParams) ([]database.Messages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", arg0, arg1)
+	ret0, _ := ret[0].([]database.Messages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockTransactionMockRecorder) ListMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockTransaction)(nil).ListMessages), arg0, arg1)
+}
+
+// ListNotificationActors mocks base method.
+func (m *MockTransaction) ListNotificationActors(arg0 context.Context, arg1 database.ListNotificationActorsParams) ([]database.ListNotificationActorsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationActors", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListNotificationActorsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationActors indicates an expected call of ListNotificationActors.
+func (mr *MockTransactionMockRecorder) ListNotificationActors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationActors", reflect.TypeOf((*MockTransaction)(nil).ListNotificationActors), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockTransaction) ListNotifications(arg0 context.Context, arg1 database.ListNotificationsParams) ([]database.Notifications, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]database.Notifications)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockTransactionMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockTransaction)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListPendingEnvelopes mocks base method.
+func (m *MockTransaction) ListPendingEnvelopes(arg0 context.Context, arg1 database.ListPendingEnvelopesParams) ([]database.Envelopes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingEnvelopes", arg0, arg1)
+	ret0, _ := ret[0].([]database.Envelopes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingEnvelopes indicates an expected call of ListPendingEnvelopes.
+func (mr *MockTransactionMockRecorder) ListPendingEnvelopes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingEnvelopes", reflect.TypeOf((*MockTransaction)(nil).ListPendingEnvelopes), arg0, arg1)
+}
+
+// ListPollOptions mocks base method.
+func (m *MockTransaction) ListPollOptions(arg0 context.Context, arg1 int64) ([]database.PollOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPollOptions", arg0, arg1)
+	ret0, _ := ret[0].([]database.PollOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPollOptions indicates an expected call of ListPollOptions.
+func (mr *MockTransactionMockRecorder) ListPollOptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPollOptions", reflect.TypeOf((*MockTransaction)(nil).ListPollOptions), arg0, arg1)
+}
+
+// ListPollOptionsByPollIDs mocks base method.
+func (m *MockTransaction) ListPollOptionsByPollIDs(arg0 context.Context, arg1 []int64) ([]database.PollOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPollOptionsByPollIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.PollOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPollOptionsByPollIDs indicates an expected call of ListPollOptionsByPollIDs.
+func (mr *MockTransactionMockRecorder) ListPollOptionsByPollIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPollOptionsByPollIDs", reflect.TypeOf((*MockTransaction)(nil).ListPollOptionsByPollIDs), arg0, arg1)
+}
+
+// ListPollVotesByUser mocks base method.
+func (m *MockTransaction) ListPollVotesByUser(arg0 context.Context, arg1 database.ListPollVotesByUserParams) ([]database.PollVotes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPollVotesByUser", arg0, arg1)
+	ret0, _ := ret[0].([]database.PollVotes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPollVotesByUser indicates an expected call of ListPollVotesByUser.
+func (mr *MockTransactionMockRecorder) ListPollVotesByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPollVotesByUser", reflect.TypeOf((*MockTransaction)(nil).ListPollVotesByUser), arg0, arg1)
+}
+
+// ListPollsByTweetIDs mocks base method.
+func (m *MockTransaction) ListPollsByTweetIDs(arg0 context.Context, arg1 []int64) ([]database.Polls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPollsByTweetIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.Polls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPollsByTweetIDs indicates an expected call of ListPollsByTweetIDs.
+func (mr *MockTransactionMockRecorder) ListPollsByTweetIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPollsByTweetIDs", reflect.TypeOf((*MockTransaction)(nil).ListPollsByTweetIDs), arg0, arg1)
+}
+
+// ListRankingCandidates mocks base method.
+func (m *MockTransaction) ListRankingCandidates(arg0 context.Context, arg1 database.ListRankingCandidatesParams) ([]database.ListRankingCandidatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRankingCandidates", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListRankingCandidatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRankingCandidates indicates an expected call of ListRankingCandidates.
+func (mr *MockTransactionMockRecorder) ListRankingCandidates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRankingCandidates", reflect.TypeOf((*MockTransaction)(nil).ListRankingCandidates), arg0, arg1)
+}
+
+// ListScheduledTweets mocks base method.
+func (m *MockTransaction) ListScheduledTweets(arg0 context.Context, arg1 string) ([]database.ScheduledTweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTweets", arg0, arg1)
+	ret0, _ := ret[0].([]database.ScheduledTweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTweets indicates an expected call of ListScheduledTweets.
+func (mr *MockTransactionMockRecorder) ListScheduledTweets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTweets", reflect.TypeOf((*MockTransaction)(nil).ListScheduledTweets), arg0, arg1)
+}
+
+// ListTweetLikers mocks base method.
+func (m *MockTransaction) ListTweetLikers(arg0 context.Context, arg1 database.ListTweetLikersParams) ([]database.ListTweetLikersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTweetLikers", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListTweetLikersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTweetLikers indicates an expected call of ListTweetLikers.
+func (mr *MockTransactionMockRecorder) ListTweetLikers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTweetLikers", reflect.TypeOf((*MockTransaction)(nil).ListTweetLikers), arg0, arg1)
+}
+
+// ListTweetRevisions mocks base method.
+func (m *MockTransaction) ListTweetRevisions(arg0 context.Context, arg1 int64) ([]database.TweetRevisions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTweetRevisions", arg0, arg1)
+	ret0, _ := ret[0].([]database.TweetRevisions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTweetRevisions indicates an expected call of ListTweetRevisions.
+func (mr *MockTransactionMockRecorder) ListTweetRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTweetRevisions", reflect.TypeOf((*MockTransaction)(nil).ListTweetRevisions), arg0, arg1)
+}
+
+// ListUnpublishedOutboxEvents mocks base method.
+func (m *MockTransaction) ListUnpublishedOutboxEvents(arg0 context.Context, arg1 int32) ([]database.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpublishedOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]database.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpublishedOutboxEvents indicates an expected call of ListUnpublishedOutboxEvents.
+func (mr *MockTransactionMockRecorder) ListUnpublishedOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpublishedOutboxEvents", reflect.TypeOf((*MockTransaction)(nil).ListUnpublishedOutboxEvents), arg0, arg1)
+}
+
+// ListUserTweets mocks base method.
+func (m *MockTransaction) ListUserTweets(arg0 context.Context, arg1 database.ListUserTweetsParams) ([]database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTweets", arg0, arg1)
+	ret0, _ := ret[0].([]database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTweets indicates an expected call of ListUserTweets.
+func (mr *MockTransactionMockRecorder) ListUserTweets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTweets", reflect.TypeOf((*MockTransaction)(nil).ListUserTweets), arg0, arg1)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockTransaction) ListWebhooks(arg0 context.Context, arg1 string) ([]database.Webhooks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", arg0, arg1)
+	ret0, _ := ret[0].([]database.Webhooks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockTransactionMockRecorder) ListWebhooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockTransaction)(nil).ListWebhooks), arg0, arg1)
+}
+
+// LockFollowerRelations mocks base method.
+func (m *MockTransaction) LockFollowerRelations(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockFollowerRelations", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockFollowerRelations indicates an expected call of LockFollowerRelations.
+func (mr *MockTransactionMockRecorder) LockFollowerRelations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockFollowerRelations", reflect.TypeOf((*MockTransaction)(nil).LockFollowerRelations), arg0, arg1)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockTransaction) MarkAllNotificationsRead(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockTransactionMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockTransaction)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkConversationRead mocks base method.
+func (m *MockTransaction) MarkConversationRead(arg0 context.Context, arg1 database.MarkConversationReadParams) (database.ConversationParticipants, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkConversationRead", arg0, arg1)
+	ret0, _ := ret[0].(database.ConversationParticipants)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkConversationRead indicates an expected call of MarkConversationRead.
+func (mr *MockTransactionMockRecorder) MarkConversationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkConversationRead", reflect.TypeOf((*MockTransaction)(nil).MarkConversationRead), arg0, arg1)
+}
+
+// MarkEventProcessed mocks base method.
+func (m *MockTransaction) MarkEventProcessed(arg0 context.Context, arg1 database.MarkEventProcessedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventProcessed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventProcessed indicates an expected call of MarkEventProcessed.
+func (mr *MockTransactionMockRecorder) MarkEventProcessed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventProcessed", reflect.TypeOf((*MockTransaction)(nil).MarkEventProcessed), arg0, arg1)
+}
+
+// MarkFanoutFanIn mocks base method.
+func (m *MockTransaction) MarkFanoutFanIn(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFanoutFanIn", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFanoutFanIn indicates an expected call of MarkFanoutFanIn.
+func (mr *MockTransactionMockRecorder) MarkFanoutFanIn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFanoutFanIn", reflect.TypeOf((*MockTransaction)(nil).MarkFanoutFanIn), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockTransaction) MarkNotificationRead(arg0 context.Context, arg1 database.MarkNotificationReadParams) (database.Notifications, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(database.Notifications)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockTransactionMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockTransaction)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// MarkOutboxEventsPublished mocks base method.
+func (m *MockTransaction) MarkOutboxEventsPublished(arg0 context.Context, arg1 []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventsPublished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventsPublished indicates an expected call of MarkOutboxEventsPublished.
+func (mr *MockTransactionMockRecorder) MarkOutboxEventsPublished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventsPublished", reflect.TypeOf((*MockTransaction)(nil).MarkOutboxEventsPublished), arg0, arg1)
+}
+
+// MarkScheduledTweetFailed mocks base method.
+func (m *MockTransaction) MarkScheduledTweetFailed(arg0 context.Context, arg1 database.MarkScheduledTweetFailedParams) (database.ScheduledTweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkScheduledTweetFailed", arg0, arg1)
+	ret0, _ := ret[0].(database.ScheduledTweets)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateLikeRelation indicates an expected call of CreateLikeRelation.
-func (mr *MockTransactionMockRecorder) CreateLikeRelation(arg0, arg1 interface{}) *gomock.Call {
+// MarkScheduledTweetFailed indicates an expected call of MarkScheduledTweetFailed.
+func (mr *MockTransactionMockRecorder) MarkScheduledTweetFailed(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLikeRelation", reflect.TypeOf((*MockTransaction)(nil).CreateLikeRelation), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkScheduledTweetFailed", reflect.TypeOf((*MockTransaction)(nil).MarkScheduledTweetFailed), arg0, arg1)
 }
 
-// CreateRelations mocks base method.
-func (m *MockTransaction) CreateRelations(arg0 context.Context, arg1 database.CreateRelationsParams) (database.Relations, error) {
+// MarkWebhookDelivered mocks base method.
+func (m *MockTransaction) MarkWebhookDelivered(arg0 context.Context, arg1 database.MarkWebhookDeliveredParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRelations", arg0, arg1)
-	ret0, _ := ret[0].(database.Relations)
+	ret := m.ctrl.Call(m, "MarkWebhookDelivered", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDelivered indicates an expected call of MarkWebhookDelivered.
+func (mr *MockTransactionMockRecorder) MarkWebhookDelivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDelivered", reflect.TypeOf((*MockTransaction)(nil).MarkWebhookDelivered), arg0, arg1)
+}
+
+// PinTweet mocks base method.
+func (m *MockTransaction) PinTweet(arg0 context.Context, arg1 database.PinTweetParams) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinTweet", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateRelations indicates an expected call of CreateRelations.
-func (mr *MockTransactionMockRecorder) CreateRelations(arg0, arg1 interface{}) *gomock.Call {
+// PinTweet indicates an expected call of PinTweet.
+func (mr *MockTransactionMockRecorder) PinTweet(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRelations", reflect.TypeOf((*MockTransaction)(nil).CreateRelations), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinTweet", reflect.TypeOf((*MockTransaction)(nil).PinTweet), arg0, arg1)
 }
 
-// CreateTweet mocks base method.
-func (m *MockTransaction) CreateTweet(arg0 context.Context, arg1 database.CreateTweetParams) (database.Tweets, error) {
+// PromoteOldestParticipant mocks base method.
+func (m *MockTransaction) PromoteOldestParticipant(arg0 context.Context, arg1 int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTweet", arg0, arg1)
-	ret0, _ := ret[0].(database.Tweets)
+	ret := m.ctrl.Call(m, "PromoteOldestParticipant", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTweet indicates an expected call of CreateTweet.
-func (mr *MockTransactionMockRecorder) CreateTweet(arg0, arg1 interface{}) *gomock.Call {
+// PromoteOldestParticipant indicates an expected call of PromoteOldestParticipant.
+func (mr *MockTransactionMockRecorder) PromoteOldestParticipant(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTweet", reflect.TypeOf((*MockTransaction)(nil).CreateTweet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteOldestParticipant", reflect.TypeOf((*MockTransaction)(nil).PromoteOldestParticipant), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockTransaction) CreateUser(arg0 context.Context, arg1 database.CreateUserParams) (database.Users, error) {
+// PruneExpiredFanIns mocks base method.
+func (m *MockTransaction) PruneExpiredFanIns(arg0 context.Context, arg1 time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(database.Users)
+	ret := m.ctrl.Call(m, "PruneExpiredFanIns", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockTransactionMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// PruneExpiredFanIns indicates an expected call of PruneExpiredFanIns.
+func (mr *MockTransactionMockRecorder) PruneExpiredFanIns(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockTransaction)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneExpiredFanIns", reflect.TypeOf((*MockTransaction)(nil).PruneExpiredFanIns), arg0, arg1)
 }
 
-// DecrementFollower mocks base method.
-func (m *MockTransaction) DecrementFollower(arg0 context.Context, arg1 string) (database.Users, error) {
+// PruneExpiredHomeTimelines mocks base method.
+func (m *MockTransaction) PruneExpiredHomeTimelines(arg0 context.Context, arg1 time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DecrementFollower", arg0, arg1)
-	ret0, _ := ret[0].(database.Users)
+	ret := m.ctrl.Call(m, "PruneExpiredHomeTimelines", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DecrementFollower indicates an expected call of DecrementFollower.
-func (mr *MockTransactionMockRecorder) DecrementFollower(arg0, arg1 interface{}) *gomock.Call {
+// PruneExpiredHomeTimelines indicates an expected call of PruneExpiredHomeTimelines.
+func (mr *MockTransactionMockRecorder) PruneExpiredHomeTimelines(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementFollower", reflect.TypeOf((*MockTransaction)(nil).DecrementFollower), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneExpiredHomeTimelines", reflect.TypeOf((*MockTransaction)(nil).PruneExpiredHomeTimelines), arg0, arg1)
 }
 
-// DecrementFollowing mocks base method.
-func (m *MockTransaction) DecrementFollowing(arg0 context.Context, arg1 string) (database.Users, error) {
+// PruneHomeTimeline mocks base method.
+func (m *MockTransaction) PruneHomeTimeline(arg0 context.Context, arg1 database.PruneHomeTimelineParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DecrementFollowing", arg0, arg1)
-	ret0, _ := ret[0].(database.Users)
+	ret := m.ctrl.Call(m, "PruneHomeTimeline", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DecrementFollowing indicates an expected call of DecrementFollowing.
-func (mr *MockTransactionMockRecorder) DecrementFollowing(arg0, arg1 interface{}) *gomock.Call {
+// PruneHomeTimeline indicates an expected call of PruneHomeTimeline.
+func (mr *MockTransactionMockRecorder) PruneHomeTimeline(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementFollowing", reflect.TypeOf((*MockTransaction)(nil).DecrementFollowing), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneHomeTimeline", reflect.TypeOf((*MockTransaction)(nil).PruneHomeTimeline), arg0, arg1)
 }
 
-// DecrementLike mocks base method.
-func (m *MockTransaction) DecrementLike(arg0 context.Context, arg1 int64) (database.Tweets, error) {
+// PrunePublishedOutboxEvents mocks base method.
+func (m *MockTransaction) PrunePublishedOutboxEvents(arg0 context.Context, arg1 time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DecrementLike", arg0, arg1)
-	ret0, _ := ret[0].(database.Tweets)
+	ret := m.ctrl.Call(m, "PrunePublishedOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DecrementLike indicates an expected call of DecrementLike.
-func (mr *MockTransactionMockRecorder) DecrementLike(arg0, arg1 interface{}) *gomock.Call {
+// PrunePublishedOutboxEvents indicates an expected call of PrunePublishedOutboxEvents.
+func (mr *MockTransactionMockRecorder) PrunePublishedOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementLike", reflect.TypeOf((*MockTransaction)(nil).DecrementLike), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrunePublishedOutboxEvents", reflect.TypeOf((*MockTransaction)(nil).PrunePublishedOutboxEvents), arg0, arg1)
 }
 
-// DeleteLikeRelation mocks base method.
-func (m *MockTransaction) DeleteLikeRelation(arg0 context.Context, arg1 database.DeleteLikeRelationParams) error {
+// PublishDraftTx mocks base method.
+func (m *MockTransaction) PublishDraftTx(arg0 context.Context, arg1 database.PublishDraftTxParams) (database.CreateTweetTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLikeRelation", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "PublishDraftTx", arg0, arg1)
+	ret0, _ := ret[0].(database.CreateTweetTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteLikeRelation indicates an expected call of DeleteLikeRelation.
-func (mr *MockTransactionMockRecorder) DeleteLikeRelation(arg0, arg1 interface{}) *gomock.Call {
+// PublishDraftTx indicates an expected call of PublishDraftTx.
+func (mr *MockTransactionMockRecorder) PublishDraftTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLikeRelation", reflect.TypeOf((*MockTransaction)(nil).DeleteLikeRelation), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDraftTx", reflect.TypeOf((*MockTransaction)(nil).PublishDraftTx), arg0, arg1)
 }
 
-// DeleteRelation mocks base method.
-func (m *MockTransaction) DeleteRelation(arg0 context.Context, arg1 database.DeleteRelationParams) error {
+// PublishScheduledTweetTx mocks base method.
+func (m *MockTransaction) PublishScheduledTweetTx(arg0 context.Context) (database.PublishScheduledTweetTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRelation", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "PublishScheduledTweetTx", arg0)
+	ret0, _ := ret[0].(database.PublishScheduledTweetTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteRelation indicates an expected call of DeleteRelation.
-func (mr *MockTransactionMockRecorder) DeleteRelation(arg0, arg1 interface{}) *gomock.Call {
+// PublishScheduledTweetTx indicates an expected call of PublishScheduledTweetTx.
+func (mr *MockTransactionMockRecorder) PublishScheduledTweetTx(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRelation", reflect.TypeOf((*MockTransaction)(nil).DeleteRelation), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduledTweetTx", reflect.TypeOf((*MockTransaction)(nil).PublishScheduledTweetTx), arg0)
 }
 
-// DeleteTweet mocks base method.
-func (m *MockTransaction) DeleteTweet(arg0 context.Context, arg1 int64) error {
+// RegisterDeviceTx mocks base method.
+func (m *MockTransaction) RegisterDeviceTx(arg0 context.Context, arg1 database.RegisterDeviceTxParams) (database.DeviceTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTweet", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "RegisterDeviceTx", arg0, arg1)
+	ret0, _ := ret[0].(database.DeviceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteTweet indicates an expected call of DeleteTweet.
-func (mr *MockTransactionMockRecorder) DeleteTweet(arg0, arg1 interface{}) *gomock.Call {
+// RegisterDeviceTx indicates an expected call of RegisterDeviceTx.
+func (mr *MockTransactionMockRecorder) RegisterDeviceTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTweet", reflect.TypeOf((*MockTransaction)(nil).DeleteTweet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDeviceTx", reflect.TypeOf((*MockTransaction)(nil).RegisterDeviceTx), arg0, arg1)
 }
 
-// FollowTx mocks base method.
-func (m *MockTransaction) FollowTx(arg0 context.Context, arg1 database.FollowInputArgs) (database.FollowInputResult, error) {
+// RelayOutboxTx mocks base method.
+func (m *MockTransaction) RelayOutboxTx(arg0 context.Context, arg1 int32, arg2 func(database.Outbox) error) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FollowTx", arg0, arg1)
-	ret0, _ := ret[0].(database.FollowInputResult)
+	ret := m.ctrl.Call(m, "RelayOutboxTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FollowTx indicates an expected call of FollowTx.
-func (mr *MockTransactionMockRecorder) FollowTx(arg0, arg1 interface{}) *gomock.Call {
+// RelayOutboxTx indicates an expected call of RelayOutboxTx.
+func (mr *MockTransactionMockRecorder) RelayOutboxTx(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowTx", reflect.TypeOf((*MockTransaction)(nil).FollowTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayOutboxTx", reflect.TypeOf((*MockTransaction)(nil).RelayOutboxTx), arg0, arg1, arg2)
 }
 
-// GetFollower mocks base method.
-func (m *MockTransaction) GetFollower(arg0 context.Context, arg1 database.GetFollowerParams) ([]database.Relations, error) {
+// RemoveConversationParticipant mocks base method.
+func (m *MockTransaction) RemoveConversationParticipant(arg0 context.Context, arg1 database.RemoveConversationParticipantParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFollower", arg0, arg1)
-	ret0, _ := ret[0].([]database.Relations)
+	ret := m.ctrl.Call(m, "RemoveConversationParticipant", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFollower indicates an expected call of GetFollower.
-func (mr *MockTransactionMockRecorder) GetFollower(arg0, arg1 interface{}) *gomock.Call {
+// RemoveConversationParticipant indicates an expected call of RemoveConversationParticipant.
+func (mr *MockTransactionMockRecorder) RemoveConversationParticipant(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollower", reflect.TypeOf((*MockTransaction)(nil).GetFollower), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveConversationParticipant", reflect.TypeOf((*MockTransaction)(nil).RemoveConversationParticipant), arg0, arg1)
 }
 
-// GetFollowing mocks base method.
-func (m *MockTransaction) GetFollowing(arg0 context.Context, arg1 database.GetFollowingParams) ([]database.Relations, error) {
+// RemoveGroupMemberTx mocks base method.
+func (m *MockTransaction) RemoveGroupMemberTx(arg0 context.Context, arg1 database.GroupMemberTxParams) (database.GroupChangeTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFollowing", arg0, arg1)
-	ret0, _ := ret[0].([]database.Relations)
+	ret := m.ctrl.Call(m, "RemoveGroupMemberTx", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupChangeTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFollowing indicates an expected call of GetFollowing.
-func (mr *MockTransactionMockRecorder) GetFollowing(arg0, arg1 interface{}) *gomock.Call {
+// RemoveGroupMemberTx indicates an expected call of RemoveGroupMemberTx.
+func (mr *MockTransactionMockRecorder) RemoveGroupMemberTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowing", reflect.TypeOf((*MockTransaction)(nil).GetFollowing), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGroupMemberTx", reflect.TypeOf((*MockTransaction)(nil).RemoveGroupMemberTx), arg0, arg1)
 }
 
-// GetLikeRelation mocks base method.
-func (m *MockTransaction) GetLikeRelation(arg0 context.Context, arg1 database.GetLikeRelationParams) (database.LikeRelations, error) {
+// RenameConversation mocks base method.
+func (m *MockTransaction) RenameConversation(arg0 context.Context, arg1 database.RenameConversationParams) (database.Conversations, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLikeRelation", arg0, arg1)
-	ret0, _ := ret[0].(database.LikeRelations)
+	ret := m.ctrl.Call(m, "RenameConversation", arg0, arg1)
+	ret0, _ := ret[0].(database.Conversations)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLikeRelation indicates an expected call of GetLikeRelation.
-func (mr *MockTransactionMockRecorder) GetLikeRelation(arg0, arg1 interface{}) *gomock.Call {
+// RenameConversation indicates an expected call of RenameConversation.
+func (mr *MockTransactionMockRecorder) RenameConversation(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLikeRelation", reflect.TypeOf((*MockTransaction)(nil).GetLikeRelation), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameConversation", reflect.TypeOf((*MockTransaction)(nil).RenameConversation), arg0, arg1)
 }
 
-// GetListTweets mocks base method.
-func (m *MockTransaction) GetListTweets(arg0 context.Context, arg1 database.GetListTweetsParams) ([]database.Tweets, error) {
+// RenameGroupTx mocks base method.
+func (m *MockTransaction) RenameGroupTx(arg0 context.Context, arg1 database.RenameGroupTxParams) (database.GroupChangeTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListTweets", arg0, arg1)
-	ret0, _ := ret[0].([]database.Tweets)
+	ret := m.ctrl.Call(m, "RenameGroupTx", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupChangeTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetListTweets indicates an expected call of GetListTweets.
-func (mr *MockTransactionMockRecorder) GetListTweets(arg0, arg1 interface{}) *gomock.Call {
+// RenameGroupTx indicates an expected call of RenameGroupTx.
+func (mr *MockTransactionMockRecorder) RenameGroupTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTweets", reflect.TypeOf((*MockTransaction)(nil).GetListTweets), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameGroupTx", reflect.TypeOf((*MockTransaction)(nil).RenameGroupTx), arg0, arg1)
 }
 
-// GetRelations mocks base method.
-func (m *MockTransaction) GetRelations(arg0 context.Context, arg1 database.GetRelationsParams) (database.Relations, error) {
+// RescheduleTweet mocks base method.
+func (m *MockTransaction) RescheduleTweet(arg0 context.Context, arg1 database.RescheduleTweetParams) (database.ScheduledTweets, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRelations", arg0, arg1)
-	ret0, _ := ret[0].(database.Relations)
+	ret := m.ctrl.Call(m, "RescheduleTweet", arg0, arg1)
+	ret0, _ := ret[0].(database.ScheduledTweets)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRelations indicates an expected call of GetRelations.
-func (mr *MockTransactionMockRecorder) GetRelations(arg0, arg1 interface{}) *gomock.Call {
+// RescheduleTweet indicates an expected call of RescheduleTweet.
+func (mr *MockTransactionMockRecorder) RescheduleTweet(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelations", reflect.TypeOf((*MockTransaction)(nil).GetRelations), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleTweet", reflect.TypeOf((*MockTransaction)(nil).RescheduleTweet), arg0, arg1)
 }
 
-// GetTweet mocks base method.
-func (m *MockTransaction) GetTweet(arg0 context.Context, arg1 int64) (database.Tweets, error) {
+// RetryWebhookDelivery mocks base method.
+func (m *MockTransaction) RetryWebhookDelivery(arg0 context.Context, arg1 database.RetryWebhookDeliveryParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTweet", arg0, arg1)
-	ret0, _ := ret[0].(database.Tweets)
+	ret := m.ctrl.Call(m, "RetryWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTweet indicates an expected call of GetTweet.
-func (mr *MockTransactionMockRecorder) GetTweet(arg0, arg1 interface{}) *gomock.Call {
+// RetryWebhookDelivery indicates an expected call of RetryWebhookDelivery.
+func (mr *MockTransactionMockRecorder) RetryWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTweet", reflect.TypeOf((*MockTransaction)(nil).GetTweet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryWebhookDelivery", reflect.TypeOf((*MockTransaction)(nil).RetryWebhookDelivery), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockTransaction) GetUser(arg0 context.Context, arg1 string) (database.Users, error) {
+// SearchTweets mocks base method.
+func (m *MockTransaction) SearchTweets(arg0 context.Context, arg1 database.SearchTweetsParams) ([]database.SearchTweetsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(database.Users)
+	ret := m.ctrl.Call(m, "SearchTweets", arg0, arg1)
+	ret0, _ := ret[0].([]database.SearchTweetsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockTransactionMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// SearchTweets indicates an expected call of SearchTweets.
+func (mr *MockTransactionMockRecorder) SearchTweets(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockTransaction)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTweets", reflect.TypeOf((*MockTransaction)(nil).SearchTweets), arg0, arg1)
 }
 
-// IncrementFollower mocks base method.
-func (m *MockTransaction) IncrementFollower(arg0 context.Context, arg1 string) (database.Users, error) {
+// SearchUsers mocks base method.
+func (m *MockTransaction) SearchUsers(arg0 context.Context, arg1 database.SearchUsersParams) ([]database.SearchUsersRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IncrementFollower", arg0, arg1)
-	ret0, _ := ret[0].(database.Users)
+	ret := m.ctrl.Call(m, "SearchUsers", arg0, arg1)
+	ret0, _ := ret[0].([]database.SearchUsersRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IncrementFollower indicates an expected call of IncrementFollower.
-func (mr *MockTransactionMockRecorder) IncrementFollower(arg0, arg1 interface{}) *gomock.Call {
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockTransactionMockRecorder) SearchUsers(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementFollower", reflect.TypeOf((*MockTransaction)(nil).IncrementFollower), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockTransaction)(nil).SearchUsers), arg0, arg1)
 }
 
-// IncrementFollowing mocks base method.
-func (m *MockTransaction) IncrementFollowing(arg0 context.Context, arg1 string) (database.Users, error) {
+// SendDirectMessageTx mocks base method.
+func (m *MockTransaction) SendDirectMessageTx(arg0 context.Context, arg1 database.SendDirectMessageTxParams) (database.SendDirectMessageTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IncrementFollowing", arg0, arg1)
-	ret0, _ := ret[0].(database.Users)
+	ret := m.ctrl.Call(m, "SendDirectMessageTx", arg0, arg1)
+	ret0, _ := ret[0].(database.SendDirectMessageTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IncrementFollowing indicates an expected call of IncrementFollowing.
-func (mr *MockTransactionMockRecorder) IncrementFollowing(arg0, arg1 interface{}) *gomock.Call {
+// SendDirectMessageTx indicates an expected call of SendDirectMessageTx.
+func (mr *MockTransactionMockRecorder) SendDirectMessageTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementFollowing", reflect.TypeOf((*MockTransaction)(nil).IncrementFollowing), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDirectMessageTx", reflect.TypeOf((*MockTransaction)(nil).SendDirectMessageTx), arg0, arg1)
 }
 
-// IncrementLike mocks base method.
-func (m *MockTransaction) IncrementLike(arg0 context.Context, arg1 int64) (database.Tweets, error) {
+// SendEnvelopesTx mocks base method.
+func (m *MockTransaction) SendEnvelopesTx(arg0 context.Context, arg1 database.SendEnvelopesTxParams) ([]database.SentEnvelope, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IncrementLike", arg0, arg1)
-	ret0, _ := ret[0].(database.Tweets)
+	ret := m.ctrl.Call(m, "SendEnvelopesTx", arg0, arg1)
+	ret0, _ := ret[0].([]database.SentEnvelope)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IncrementLike indicates an expected call of IncrementLike.
-func (mr *MockTransactionMockRecorder) IncrementLike(arg0, arg1 interface{}) *gomock.Call {
+// SendEnvelopesTx indicates an expected call of SendEnvelopesTx.
+func (mr *MockTransactionMockRecorder) SendEnvelopesTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLike", reflect.TypeOf((*MockTransaction)(nil).IncrementLike), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEnvelopesTx", reflect.TypeOf((*MockTransaction)(nil).SendEnvelopesTx), arg0, arg1)
 }
 
-// LikeTweetTx mocks base method.
-func (m *MockTransaction) LikeTweetTx(arg0 context.Context, arg1 database.CreateLikeRelationParams) error {
+// SetConversationLastMessage mocks base method.
+func (m *MockTransaction) SetConversationLastMessage(arg0 context.Context, arg1 database.SetConversationLastMessageParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LikeTweetTx", arg0, arg1)
+	ret := m.ctrl.Call(m, "SetConversationLastMessage", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// LikeTweetTx indicates an expected call of LikeTweetTx.
-func (mr *MockTransactionMockRecorder) LikeTweetTx(arg0, arg1 interface{}) *gomock.Call {
+// SetConversationLastMessage indicates an expected call of SetConversationLastMessage.
+func (mr *MockTransactionMockRecorder) SetConversationLastMessage(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LikeTweetTx", reflect.TypeOf((*MockTransaction)(nil).LikeTweetTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConversationLastMessage", reflect.TypeOf((*MockTransaction)(nil).SetConversationLastMessage), arg0, arg1)
+}
+
+// SetConversationParticipantRole mocks base method.
+func (m *MockTransaction) SetConversationParticipantRole(arg0 context.Context, arg1 database.SetConversationParticipantRoleParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetConversationParticipantRole", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetConversationParticipantRole indicates an expected call of SetConversationParticipantRole.
+func (mr *MockTransactionMockRecorder) SetConversationParticipantRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConversationParticipantRole", reflect.TypeOf((*MockTransaction)(nil).SetConversationParticipantRole), arg0, arg1)
+}
+
+// SetGroupRoleTx mocks base method.
+func (m *MockTransaction) SetGroupRoleTx(arg0 context.Context, arg1 database.SetGroupRoleTxParams) (database.GroupChangeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGroupRoleTx", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupChangeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetGroupRoleTx indicates an expected call of SetGroupRoleTx.
+func (mr *MockTransactionMockRecorder) SetGroupRoleTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGroupRoleTx", reflect.TypeOf((*MockTransaction)(nil).SetGroupRoleTx), arg0, arg1)
+}
+
+// SetProtected mocks base method.
+func (m *MockTransaction) SetProtected(arg0 context.Context, arg1 database.SetProtectedParams) (database.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProtected", arg0, arg1)
+	ret0, _ := ret[0].(database.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProtected indicates an expected call of SetProtected.
+func (mr *MockTransactionMockRecorder) SetProtected(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProtected", reflect.TypeOf((*MockTransaction)(nil).SetProtected), arg0, arg1)
+}
+
+// TryLockOutboxRelay mocks base method.
+func (m *MockTransaction) TryLockOutboxRelay(arg0 context.Context, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLockOutboxRelay", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLockOutboxRelay indicates an expected call of TryLockOutboxRelay.
+func (mr *MockTransactionMockRecorder) TryLockOutboxRelay(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLockOutboxRelay", reflect.TypeOf((*MockTransaction)(nil).TryLockOutboxRelay), arg0, arg1)
 }
 
 // UnfollowTx mocks base method.
@@ -389,6 +2431,36 @@ func (mr *MockTransactionMockRecorder) UnlikeTweetTx(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlikeTweetTx", reflect.TypeOf((*MockTransaction)(nil).UnlikeTweetTx), arg0, arg1)
 }
 
+// UnpinTweet mocks base method.
+func (m *MockTransaction) UnpinTweet(arg0 context.Context, arg1 database.UnpinTweetParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinTweet", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpinTweet indicates an expected call of UnpinTweet.
+func (mr *MockTransactionMockRecorder) UnpinTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinTweet", reflect.TypeOf((*MockTransaction)(nil).UnpinTweet), arg0, arg1)
+}
+
+// UpdateDraft mocks base method.
+func (m *MockTransaction) UpdateDraft(arg0 context.Context, arg1 database.UpdateDraftParams) (database.Drafts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDraft", arg0, arg1)
+	ret0, _ := ret[0].(database.Drafts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDraft indicates an expected call of UpdateDraft.
+func (mr *MockTransactionMockRecorder) UpdateDraft(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDraft", reflect.TypeOf((*MockTransaction)(nil).UpdateDraft), arg0, arg1)
+}
+
 // UpdateEmail mocks base method.
 func (m *MockTransaction) UpdateEmail(arg0 context.Context, arg1 database.UpdateEmailParams) (database.Users, error) {
 	m.ctrl.T.Helper()
@@ -433,3 +2505,108 @@ func (mr *MockTransactionMockRecorder) UpdatePassword(arg0, arg1 interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockTransaction)(nil).UpdatePassword), arg0, arg1)
 }
+
+// UpdateSignedPrekey mocks base method.
+func (m *MockTransaction) UpdateSignedPrekey(arg0 context.Context, arg1 database.UpdateSignedPrekeyParams) (database.Devices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSignedPrekey", arg0, arg1)
+	ret0, _ := ret[0].(database.Devices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSignedPrekey indicates an expected call of UpdateSignedPrekey.
+func (mr *MockTransactionMockRecorder) UpdateSignedPrekey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSignedPrekey", reflect.TypeOf((*MockTransaction)(nil).UpdateSignedPrekey), arg0, arg1)
+}
+
+// UpdateTweet mocks base method.
+func (m *MockTransaction) UpdateTweet(arg0 context.Context, arg1 database.UpdateTweetParams) (database.Tweets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTweet", arg0, arg1)
+	ret0, _ := ret[0].(database.Tweets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTweet indicates an expected call of UpdateTweet.
+func (mr *MockTransactionMockRecorder) UpdateTweet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTweet", reflect.TypeOf((*MockTransaction)(nil).UpdateTweet), arg0, arg1)
+}
+
+// UpsertDMSettings mocks base method.
+func (m *MockTransaction) UpsertDMSettings(arg0 context.Context, arg1 database.UpsertDMSettingsParams) (database.DmSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDMSettings", arg0, arg1)
+	ret0, _ := ret[0].(database.DmSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertDMSettings indicates an expected call of UpsertDMSettings.
+func (mr *MockTransactionMockRecorder) UpsertDMSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDMSettings", reflect.TypeOf((*MockTransaction)(nil).UpsertDMSettings), arg0, arg1)
+}
+
+// UpsertDirectConversation mocks base method.
+func (m *MockTransaction) UpsertDirectConversation(arg0 context.Context, arg1 sql.NullString) (database.Conversations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDirectConversation", arg0, arg1)
+	ret0, _ := ret[0].(database.Conversations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertDirectConversation indicates an expected call of UpsertDirectConversation.
+func (mr *MockTransactionMockRecorder) UpsertDirectConversation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDirectConversation", reflect.TypeOf((*MockTransaction)(nil).UpsertDirectConversation), arg0, arg1)
+}
+
+// UpsertNotification mocks base method.
+func (m *MockTransaction) UpsertNotification(arg0 context.Context, arg1 database.UpsertNotificationParams) (database.Notifications, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotification", arg0, arg1)
+	ret0, _ := ret[0].(database.Notifications)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotification indicates an expected call of UpsertNotification.
+func (mr *MockTransactionMockRecorder) UpsertNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotification", reflect.TypeOf((*MockTransaction)(nil).UpsertNotification), arg0, arg1)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockTransaction) UpsertNotificationPreferences(arg0 context.Context, arg1 database.UpsertNotificationPreferencesParams) (database.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockTransactionMockRecorder) UpsertNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockTransaction)(nil).UpsertNotificationPreferences), arg0, arg1)
+}
+
+// VotePollTx mocks base method.
+func (m *MockTransaction) VotePollTx(arg0 context.Context, arg1 database.VotePollTxParams) (database.TweetPoll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VotePollTx", arg0, arg1)
+	ret0, _ := ret[0].(database.TweetPoll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VotePollTx indicates an expected call of VotePollTx.
+func (mr *MockTransactionMockRecorder) VotePollTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VotePollTx", reflect.TypeOf((*MockTransaction)(nil).VotePollTx), arg0, arg1)
+}
@@ -0,0 +1,15 @@
+-- name: CreateBlock :execrows
+INSERT INTO blocks
+(blocker_username, blocked_username)
+VALUES ($1,$2)
+ON CONFLICT (blocker_username, blocked_username) DO NOTHING;
+
+-- name: DeleteBlock :execrows
+DELETE FROM blocks
+WHERE blocker_username = $1 AND blocked_username = $2;
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+  SELECT 1 FROM blocks
+  WHERE blocker_username = $1 AND blocked_username = $2
+);
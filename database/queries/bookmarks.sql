@@ -0,0 +1,35 @@
+-- name: CreateBookmark :execrows
+INSERT INTO bookmarks
+(username, tweet_id)
+VALUES ($1,$2)
+ON CONFLICT (username, tweet_id) DO NOTHING;
+
+-- name: DeleteBookmark :execrows
+DELETE FROM bookmarks
+WHERE username = $1 AND tweet_id = $2;
+
+-- name: ListBookmarks :many
+(
+  SELECT bookmarks.id AS bookmark_id, tweets.id, tweets.tweet, tweets.username, tweets.likes, tweets.created_at, tweets.edited_at, tweets.in_reply_to_id, tweets.retweet_of_id
+  FROM bookmarks
+  JOIN tweets ON tweets.id = bookmarks.tweet_id
+  WHERE bookmarks.username = sqlc.arg(username) AND bookmarks.id > sqlc.arg(after_id) AND bookmarks.id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY bookmarks.id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT bookmarks.id AS bookmark_id, tweets.id, tweets.tweet, tweets.username, tweets.likes, tweets.created_at, tweets.edited_at, tweets.in_reply_to_id, tweets.retweet_of_id
+  FROM bookmarks
+  JOIN tweets ON tweets.id = bookmarks.tweet_id
+  WHERE bookmarks.username = sqlc.arg(username) AND bookmarks.id > sqlc.arg(after_id) AND bookmarks.id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY bookmarks.id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY bookmark_id DESC;
+
+-- name: ListBookmarkedTweetIDs :many
+SELECT tweet_id FROM bookmarks
+WHERE username = sqlc.arg(username) AND tweet_id = ANY(sqlc.arg(tweet_ids)::bigint[]);
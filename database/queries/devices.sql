@@ -0,0 +1,69 @@
+-- name: CreateDevice :one
+INSERT INTO devices
+(username, name, identity_key, signing_key, signed_prekey_id, signed_prekey, signed_prekey_signature)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+RETURNING *;
+
+-- name: GetDevice :one
+SELECT * FROM devices
+WHERE id = $1 LIMIT 1;
+
+-- name: ListDevices :many
+SELECT * FROM devices
+WHERE username = $1
+ORDER BY id;
+
+-- name: CountDevices :one
+SELECT count(*) FROM devices
+WHERE username = $1;
+
+-- name: DeleteDevice :execrows
+DELETE FROM devices
+WHERE id = $1 AND username = $2;
+
+-- name: UpdateSignedPrekey :one
+UPDATE devices SET
+signed_prekey_id = $3,
+signed_prekey = $4,
+signed_prekey_signature = $5,
+updated_at = now()
+WHERE id = $1 AND username = $2
+RETURNING *;
+
+-- name: AddOneTimePrekey :execrows
+INSERT INTO one_time_prekeys
+(device_id, key_id, public_key)
+VALUES ($1,$2,$3)
+ON CONFLICT (device_id, key_id) DO NOTHING;
+
+-- name: CountOneTimePrekeys :many
+SELECT device_id, count(*) FROM one_time_prekeys
+WHERE device_id = ANY(sqlc.arg(device_ids)::bigint[])
+GROUP BY device_id;
+
+-- name: ClaimOneTimePrekey :one
+DELETE FROM one_time_prekeys
+WHERE (device_id, key_id) = (
+  SELECT claimed.device_id, claimed.key_id FROM one_time_prekeys AS claimed
+  WHERE claimed.device_id = $1
+  ORDER BY claimed.key_id
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CreateEnvelope :one
+INSERT INTO envelopes
+(recipient_device_id, sender_username, sender_device_id, ciphertext)
+VALUES ($1,$2,$3,$4)
+RETURNING *;
+
+-- name: ListPendingEnvelopes :many
+SELECT * FROM envelopes
+WHERE recipient_device_id = $1
+ORDER BY id
+LIMIT $2;
+
+-- name: DeleteEnvelopes :execrows
+DELETE FROM envelopes
+WHERE recipient_device_id = sqlc.arg(recipient_device_id) AND id = ANY(sqlc.arg(ids)::bigint[]);
@@ -0,0 +1,124 @@
+-- name: UpsertDirectConversation :one
+INSERT INTO conversations
+(direct_key)
+VALUES ($1)
+ON CONFLICT (direct_key) DO UPDATE SET
+direct_key = EXCLUDED.direct_key
+RETURNING *;
+
+-- name: AddConversationParticipant :exec
+INSERT INTO conversation_participants
+(conversation_id, username)
+VALUES ($1,$2)
+ON CONFLICT (conversation_id, username) DO NOTHING;
+
+-- name: GetConversationParticipant :one
+SELECT * FROM conversation_participants
+WHERE conversation_id = $1 AND username = $2 LIMIT 1;
+
+-- name: ListConversationParticipants :many
+SELECT * FROM conversation_participants
+WHERE conversation_id = ANY(sqlc.arg(conversation_ids)::bigint[])
+ORDER BY conversation_id, joined_at, username;
+
+-- name: CreateMessage :one
+INSERT INTO messages
+(conversation_id, sender_username, body, kind)
+VALUES ($1,$2,$3,$4)
+RETURNING *;
+
+-- name: SetConversationLastMessage :exec
+UPDATE conversations SET
+last_message_id = $2
+WHERE id = $1;
+
+-- name: HasSentMessage :one
+SELECT EXISTS (
+  SELECT 1 FROM messages
+  WHERE conversation_id = $1 AND sender_username = $2
+);
+
+-- name: MarkConversationRead :one
+UPDATE conversation_participants SET
+last_read_message_id = GREATEST(last_read_message_id, LEAST(sqlc.arg(message_id)::bigint, (
+  SELECT last_message_id FROM conversations WHERE conversations.id = sqlc.arg(conversation_id)
+)))
+WHERE conversation_id = sqlc.arg(conversation_id) AND username = sqlc.arg(username)
+RETURNING *;
+
+-- name: ListConversations :many
+(
+  SELECT conversations.id, conversations.is_group, conversations.title, conversations.last_message_id,
+  conversation_participants.last_read_message_id, messages.sender_username, messages.body, messages.kind, messages.created_at,
+  (SELECT count(*) FROM messages AS unread
+    WHERE unread.conversation_id = conversations.id AND unread.id > conversation_participants.last_read_message_id
+    AND unread.sender_username <> sqlc.arg(username)) AS unread_count
+  FROM conversation_participants
+  JOIN conversations ON conversations.id = conversation_participants.conversation_id
+  JOIN messages ON messages.id = conversations.last_message_id
+  WHERE conversation_participants.username = sqlc.arg(username)
+  AND conversations.last_message_id > sqlc.arg(after_id) AND conversations.last_message_id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY conversations.last_message_id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT conversations.id, conversations.is_group, conversations.title, conversations.last_message_id,
+  conversation_participants.last_read_message_id, messages.sender_username, messages.body, messages.kind, messages.created_at,
+  (SELECT count(*) FROM messages AS unread
+    WHERE unread.conversation_id = conversations.id AND unread.id > conversation_participants.last_read_message_id
+    AND unread.sender_username <> sqlc.arg(username)) AS unread_count
+  FROM conversation_participants
+  JOIN conversations ON conversations.id = conversation_participants.conversation_id
+  JOIN messages ON messages.id = conversations.last_message_id
+  WHERE conversation_participants.username = sqlc.arg(username)
+  AND conversations.last_message_id > sqlc.arg(after_id) AND conversations.last_message_id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY conversations.last_message_id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY last_message_id DESC;
+
+-- name: CountUnreadConversations :one
+SELECT count(*) FROM conversation_participants
+JOIN conversations ON conversations.id = conversation_participants.conversation_id
+WHERE conversation_participants.username = $1
+AND EXISTS (
+  SELECT 1 FROM messages
+  WHERE messages.conversation_id = conversations.id AND messages.id > conversation_participants.last_read_message_id
+  AND messages.sender_username <> $1
+);
+
+-- name: ListMessages :many
+(
+  SELECT * FROM messages
+  WHERE conversation_id = sqlc.arg(conversation_id)
+  AND id > sqlc.arg(after_id) AND id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT * FROM messages
+  WHERE conversation_id = sqlc.arg(conversation_id)
+  AND id > sqlc.arg(after_id) AND id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY id DESC;
+
+-- name: GetDMSettings :one
+SELECT * FROM dm_settings
+WHERE username = $1 LIMIT 1;
+
+-- name: UpsertDMSettings :one
+INSERT INTO dm_settings
+(username, allow_from)
+VALUES ($1,$2)
+ON CONFLICT (username) DO UPDATE SET
+allow_from = EXCLUDED.allow_from,
+updated_at = now()
+RETURNING *;
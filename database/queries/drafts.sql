@@ -0,0 +1,26 @@
+-- name: CreateDraft :one
+INSERT INTO drafts
+(username, tweet, media_ids)
+VALUES ($1,$2,$3)
+RETURNING *;
+
+-- name: UpdateDraft :one
+UPDATE drafts SET
+tweet = $1, media_ids = $2, updated_at = now()
+WHERE id = $3 AND username = $4
+RETURNING *;
+
+-- name: ListDrafts :many
+SELECT * FROM drafts
+WHERE username = $1
+ORDER BY updated_at DESC, id DESC;
+
+-- name: GetDraftForUpdate :one
+SELECT * FROM drafts
+WHERE id = $1 AND username = $2
+LIMIT 1
+FOR UPDATE;
+
+-- name: DeleteDraft :execrows
+DELETE FROM drafts
+WHERE id = $1 AND username = $2;
@@ -0,0 +1,31 @@
+-- name: CreateFollowRequest :execrows
+INSERT INTO follow_requests
+(requester_username, target_username)
+VALUES ($1,$2)
+ON CONFLICT (requester_username, target_username) DO NOTHING;
+
+-- name: DeleteFollowRequest :execrows
+DELETE FROM follow_requests
+WHERE requester_username = $1 AND target_username = $2;
+
+-- name: ListFollowRequests :many
+(
+  SELECT follow_requests.id AS request_id, users.username, users.name, users.followers_count, users.following_count
+  FROM follow_requests
+  JOIN users ON users.username = follow_requests.requester_username
+  WHERE follow_requests.target_username = sqlc.arg(username) AND follow_requests.id > sqlc.arg(after_id) AND follow_requests.id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY follow_requests.id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT follow_requests.id AS request_id, users.username, users.name, users.followers_count, users.following_count
+  FROM follow_requests
+  JOIN users ON users.username = follow_requests.requester_username
+  WHERE follow_requests.target_username = sqlc.arg(username) AND follow_requests.id > sqlc.arg(after_id) AND follow_requests.id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY follow_requests.id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY request_id DESC;
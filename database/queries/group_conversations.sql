@@ -0,0 +1,45 @@
+-- name: CreateGroupConversation :one
+INSERT INTO conversations
+(is_group, title, created_by)
+VALUES (true,$1,$2)
+RETURNING *;
+
+-- name: GetConversationForUpdate :one
+SELECT * FROM conversations
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: RenameConversation :one
+UPDATE conversations SET
+title = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: AddGroupParticipant :execrows
+INSERT INTO conversation_participants
+(conversation_id, username, role)
+VALUES ($1,$2,$3)
+ON CONFLICT (conversation_id, username) DO NOTHING;
+
+-- name: RemoveConversationParticipant :execrows
+DELETE FROM conversation_participants
+WHERE conversation_id = $1 AND username = $2;
+
+-- name: SetConversationParticipantRole :execrows
+UPDATE conversation_participants SET
+role = $3
+WHERE conversation_id = $1 AND username = $2;
+
+-- name: CountConversationAdmins :one
+SELECT count(*) FROM conversation_participants
+WHERE conversation_id = $1 AND role = 'admin';
+
+-- name: PromoteOldestParticipant :execrows
+UPDATE conversation_participants SET
+role = 'admin'
+WHERE conversation_id = $1 AND username = (
+  SELECT oldest.username FROM conversation_participants AS oldest
+  WHERE oldest.conversation_id = $1
+  ORDER BY oldest.joined_at, oldest.username
+  LIMIT 1
+);
@@ -0,0 +1,103 @@
+-- name: CreateTimelineFanout :exec
+INSERT INTO timeline_fanouts
+(tweet_id, author_username)
+VALUES ($1,$2);
+
+-- name: GetPendingFanoutForUpdate :one
+SELECT * FROM timeline_fanouts
+WHERE NOT fan_in
+ORDER BY tweet_id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkFanoutFanIn :exec
+UPDATE timeline_fanouts SET
+fan_in = true
+WHERE tweet_id = $1;
+
+-- name: DeleteTimelineFanout :exec
+DELETE FROM timeline_fanouts
+WHERE tweet_id = $1;
+
+-- name: AddToHomeTimeline :exec
+INSERT INTO home_timelines
+(username, tweet_id, author_username)
+VALUES ($1,$2,$3)
+ON CONFLICT (username, tweet_id) DO NOTHING;
+
+-- name: LockFollowerRelations :exec
+SELECT 1 FROM relations
+WHERE followed_username = $1
+FOR SHARE;
+
+-- name: FanOutTweet :execrows
+INSERT INTO home_timelines
+(username, tweet_id, author_username)
+SELECT follower_username, sqlc.arg(tweet_id)::bigint, sqlc.arg(author_username)::varchar FROM relations
+WHERE followed_username = sqlc.arg(author_username)
+ON CONFLICT (username, tweet_id) DO NOTHING;
+
+-- name: BackfillHomeTimeline :execrows
+INSERT INTO home_timelines
+(username, tweet_id, author_username)
+SELECT sqlc.arg(username)::varchar, id, tweets.username FROM tweets
+WHERE tweets.username = sqlc.arg(author_username)
+ORDER BY id DESC
+LIMIT sqlc.arg(backfill_size)
+ON CONFLICT (username, tweet_id) DO NOTHING;
+
+-- name: PruneHomeTimeline :execrows
+DELETE FROM home_timelines
+WHERE username = $1 AND author_username = $2;
+
+-- name: PruneExpiredHomeTimelines :execrows
+DELETE FROM home_timelines
+WHERE created_at < $1;
+
+-- name: PruneExpiredFanIns :execrows
+DELETE FROM timeline_fanouts
+WHERE fan_in AND created_at < $1;
+
+-- name: GetMaterializedHomeTimeline :many
+SELECT * FROM tweets
+WHERE id IN (
+  (
+    SELECT tweet_id FROM home_timelines
+    WHERE home_timelines.username = sqlc.arg(username)
+    AND tweet_id > sqlc.arg(after_id) AND tweet_id < sqlc.arg(before_id)
+    AND NOT sqlc.arg(reverse)::boolean
+    ORDER BY tweet_id DESC
+    LIMIT sqlc.arg(page_size)
+  )
+  UNION ALL
+  (
+    SELECT tweet_id FROM home_timelines
+    WHERE home_timelines.username = sqlc.arg(username)
+    AND tweet_id > sqlc.arg(after_id) AND tweet_id < sqlc.arg(before_id)
+    AND sqlc.arg(reverse)::boolean
+    ORDER BY tweet_id ASC
+    LIMIT sqlc.arg(page_size)
+  )
+  UNION ALL
+  (
+    SELECT timeline_fanouts.tweet_id FROM timeline_fanouts
+    JOIN relations ON relations.followed_username = timeline_fanouts.author_username
+    WHERE relations.follower_username = sqlc.arg(username) AND timeline_fanouts.fan_in
+    AND timeline_fanouts.tweet_id > sqlc.arg(after_id) AND timeline_fanouts.tweet_id < sqlc.arg(before_id)
+    AND NOT sqlc.arg(reverse)::boolean
+    ORDER BY timeline_fanouts.tweet_id DESC
+    LIMIT sqlc.arg(page_size)
+  )
+  UNION ALL
+  (
+    SELECT timeline_fanouts.tweet_id FROM timeline_fanouts
+    JOIN relations ON relations.followed_username = timeline_fanouts.author_username
+    WHERE relations.follower_username = sqlc.arg(username) AND timeline_fanouts.fan_in
+    AND timeline_fanouts.tweet_id > sqlc.arg(after_id) AND timeline_fanouts.tweet_id < sqlc.arg(before_id)
+    AND sqlc.arg(reverse)::boolean
+    ORDER BY timeline_fanouts.tweet_id ASC
+    LIMIT sqlc.arg(page_size)
+  )
+)
+ORDER BY CASE WHEN sqlc.arg(reverse)::boolean THEN id END ASC, id DESC
+LIMIT sqlc.arg(page_size);
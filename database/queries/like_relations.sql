@@ -10,4 +10,48 @@ WHERE username = $1 AND tweet_id = $2;
 
 -- name: GetLikeRelation :one
 SELECT * FROM like_relations
-WHERE username = $1 AND tweet_id = $2;
\ No newline at end of file
+WHERE username = $1 AND tweet_id = $2;
+
+-- name: ListTweetLikers :many
+(
+  SELECT like_relations.id AS like_id, users.username, users.name, users.followers_count, users.following_count
+  FROM like_relations
+  JOIN users ON users.username = like_relations.username
+  WHERE like_relations.tweet_id = sqlc.arg(tweet_id) AND like_relations.id > sqlc.arg(after_id) AND like_relations.id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY like_relations.id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT like_relations.id AS like_id, users.username, users.name, users.followers_count, users.following_count
+  FROM like_relations
+  JOIN users ON users.username = like_relations.username
+  WHERE like_relations.tweet_id = sqlc.arg(tweet_id) AND like_relations.id > sqlc.arg(after_id) AND like_relations.id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY like_relations.id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY like_id DESC;
+
+-- name: ListLikedTweets :many
+(
+  SELECT like_relations.id AS like_id, tweets.id, tweets.tweet, tweets.username, tweets.likes, tweets.created_at, tweets.edited_at, tweets.in_reply_to_id, tweets.retweet_of_id
+  FROM like_relations
+  JOIN tweets ON tweets.id = like_relations.tweet_id
+  WHERE like_relations.username = sqlc.arg(username) AND like_relations.id > sqlc.arg(after_id) AND like_relations.id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY like_relations.id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT like_relations.id AS like_id, tweets.id, tweets.tweet, tweets.username, tweets.likes, tweets.created_at, tweets.edited_at, tweets.in_reply_to_id, tweets.retweet_of_id
+  FROM like_relations
+  JOIN tweets ON tweets.id = like_relations.tweet_id
+  WHERE like_relations.username = sqlc.arg(username) AND like_relations.id > sqlc.arg(after_id) AND like_relations.id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY like_relations.id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY like_id DESC;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: CreateMedia :one
+INSERT INTO media
+(username, mime_type, size_bytes, width, height, storage_key, thumbnail_key)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+RETURNING *;
+
+-- name: GetMedia :one
+SELECT * FROM media
+WHERE id = $1 LIMIT 1;
+
+-- name: AttachMedia :one
+UPDATE media SET
+tweet_id = $1, position = $2
+WHERE id = $3 AND username = $4 AND tweet_id IS NULL
+RETURNING *;
+
+-- name: ListMediaByTweetIDs :many
+SELECT * FROM media
+WHERE tweet_id = ANY(sqlc.arg(tweet_ids)::bigint[])
+ORDER BY tweet_id, position;
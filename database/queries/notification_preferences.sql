@@ -0,0 +1,19 @@
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences
+WHERE username = $1 LIMIT 1;
+
+-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences
+(username, likes, follows, mentions, replies, only_following, quiet_hours_start, quiet_hours_end, time_zone)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+ON CONFLICT (username) DO UPDATE SET
+likes = EXCLUDED.likes,
+follows = EXCLUDED.follows,
+mentions = EXCLUDED.mentions,
+replies = EXCLUDED.replies,
+only_following = EXCLUDED.only_following,
+quiet_hours_start = EXCLUDED.quiet_hours_start,
+quiet_hours_end = EXCLUDED.quiet_hours_end,
+time_zone = EXCLUDED.time_zone,
+updated_at = now()
+RETURNING *;
@@ -0,0 +1,67 @@
+-- name: UpsertNotification :one
+INSERT INTO notifications
+(username, type, group_key, tweet_id)
+VALUES ($1,$2,$3,$4)
+ON CONFLICT (username, group_key) WHERE read_at IS NULL DO UPDATE SET
+seq = nextval('notifications_seq'),
+updated_at = now()
+RETURNING *;
+
+-- name: AddNotificationActor :exec
+INSERT INTO notification_actors
+(notification_id, actor_username)
+VALUES ($1,$2)
+ON CONFLICT (notification_id, actor_username) DO UPDATE SET
+created_at = now();
+
+-- name: ListMentionableUsernames :many
+SELECT username FROM users
+WHERE username = ANY(sqlc.arg(usernames)::varchar[]) AND username <> sqlc.arg(author)::varchar
+AND NOT EXISTS (
+  SELECT 1 FROM blocks
+  WHERE blocks.blocker_username = users.username AND blocks.blocked_username = sqlc.arg(author)
+);
+
+-- name: ListNotifications :many
+(
+  SELECT * FROM notifications
+  WHERE username = sqlc.arg(username) AND seq > sqlc.arg(after_id) AND seq < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY seq DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT * FROM notifications
+  WHERE username = sqlc.arg(username) AND seq > sqlc.arg(after_id) AND seq < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY seq ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY seq DESC;
+
+-- name: ListNotificationActors :many
+SELECT notification_id, actor_username, total FROM (
+  SELECT notification_id, actor_username,
+  count(*) OVER (PARTITION BY notification_id) AS total,
+  row_number() OVER (PARTITION BY notification_id ORDER BY created_at DESC, actor_username) AS position
+  FROM notification_actors
+  WHERE notification_id = ANY(sqlc.arg(notification_ids)::bigint[])
+) AS actors
+WHERE position <= sqlc.arg(actor_limit)
+ORDER BY notification_id, position;
+
+-- name: CountUnreadNotifications :one
+SELECT count(*) FROM notifications
+WHERE username = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications SET
+read_at = COALESCE(read_at, now())
+WHERE id = $1 AND username = $2
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications SET
+read_at = now()
+WHERE username = $1 AND read_at IS NULL;
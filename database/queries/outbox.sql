@@ -0,0 +1,35 @@
+-- name: InsertOutboxEvent :exec
+INSERT INTO outbox
+(event_type, aggregate_key, payload)
+VALUES ($1,$2,$3);
+
+-- name: ListUnpublishedOutboxEvents :many
+SELECT * FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE;
+
+-- name: TryLockOutboxRelay :one
+SELECT pg_try_advisory_xact_lock(sqlc.arg(lock_key)::bigint) AS locked;
+
+-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox SET
+published_at = now()
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: PrunePublishedOutboxEvents :execrows
+DELETE FROM outbox
+WHERE published_at < $1;
+
+-- name: HasProcessedEvent :one
+SELECT EXISTS (
+  SELECT 1 FROM processed_events
+  WHERE consumer = $1 AND event_id = $2
+);
+
+-- name: MarkEventProcessed :exec
+INSERT INTO processed_events
+(consumer, event_id)
+VALUES ($1,$2)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,68 @@
+-- name: CreatePoll :one
+INSERT INTO polls
+(tweet_id, closes_at)
+VALUES ($1,$2)
+RETURNING *;
+
+-- name: CreatePollOption :one
+INSERT INTO poll_options
+(poll_id, position, label)
+VALUES ($1,$2,$3)
+RETURNING *;
+
+-- name: GetPoll :one
+SELECT * FROM polls
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPollForVote :one
+SELECT * FROM polls
+WHERE id = $1 LIMIT 1
+FOR SHARE;
+
+-- name: ListPollsByTweetIDs :many
+SELECT * FROM polls
+WHERE tweet_id = ANY(sqlc.arg(tweet_ids)::bigint[]);
+
+-- name: ListPollOptions :many
+SELECT * FROM poll_options
+WHERE poll_id = $1
+ORDER BY position;
+
+-- name: ListPollOptionsByPollIDs :many
+SELECT * FROM poll_options
+WHERE poll_id = ANY(sqlc.arg(poll_ids)::bigint[])
+ORDER BY poll_id, position;
+
+-- name: ListPollVotesByUser :many
+SELECT * FROM poll_votes
+WHERE username = sqlc.arg(username) AND poll_id = ANY(sqlc.arg(poll_ids)::bigint[]);
+
+-- name: IncrementPollOptionVotes :one
+UPDATE poll_options SET
+votes = votes + 1
+WHERE id = $1 AND poll_id = $2
+RETURNING *;
+
+-- name: CreatePollVote :execrows
+INSERT INTO poll_votes
+(poll_id, option_id, username)
+VALUES ($1,$2,$3)
+ON CONFLICT (poll_id, username) DO NOTHING;
+
+-- name: GetDuePollForUpdate :one
+SELECT * FROM polls
+WHERE closes_at <= now() AND closed_at IS NULL
+ORDER BY closes_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: FinalizePollOptions :exec
+UPDATE poll_options SET
+votes = (SELECT count(*) FROM poll_votes WHERE poll_votes.option_id = poll_options.id)
+WHERE poll_id = $1;
+
+-- name: ClosePoll :one
+UPDATE polls SET
+closed_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,38 @@
+-- name: ListRankingCandidates :many
+SELECT tweets.id, tweets.tweet, tweets.username, tweets.likes, tweets.created_at, tweets.edited_at, tweets.in_reply_to_id, tweets.retweet_of_id,
+  (SELECT count(*) FROM tweets AS replies WHERE replies.in_reply_to_id = tweets.id) AS reply_count,
+  (SELECT count(*) FROM tweets AS retweets WHERE retweets.retweet_of_id = tweets.id) AS retweet_count
+FROM tweets
+WHERE tweets.id IN (
+  (
+    SELECT tweet_id FROM home_timelines
+    WHERE home_timelines.username = sqlc.arg(username)
+    ORDER BY tweet_id DESC
+    LIMIT sqlc.arg(candidate_limit)
+  )
+  UNION ALL
+  (
+    SELECT timeline_fanouts.tweet_id FROM timeline_fanouts
+    JOIN relations ON relations.followed_username = timeline_fanouts.author_username
+    WHERE relations.follower_username = sqlc.arg(username) AND timeline_fanouts.fan_in
+    ORDER BY timeline_fanouts.tweet_id DESC
+    LIMIT sqlc.arg(candidate_limit)
+  )
+)
+AND tweets.created_at > sqlc.arg(since)
+ORDER BY tweets.id DESC
+LIMIT sqlc.arg(candidate_limit);
+
+-- name: ListAuthorAffinities :many
+SELECT author_username, count(*) AS interactions FROM (
+  SELECT tweets.username AS author_username FROM like_relations
+  JOIN tweets ON tweets.id = like_relations.tweet_id
+  WHERE like_relations.username = sqlc.arg(username) AND like_relations.created_at > sqlc.arg(since)
+  AND tweets.username = ANY(sqlc.arg(authors)::varchar[])
+  UNION ALL
+  SELECT parents.username FROM tweets
+  JOIN tweets AS parents ON parents.id = tweets.in_reply_to_id OR parents.id = tweets.retweet_of_id
+  WHERE tweets.username = sqlc.arg(username) AND tweets.created_at > sqlc.arg(since)
+  AND parents.username = ANY(sqlc.arg(authors)::varchar[])
+) AS interactions
+GROUP BY author_username;
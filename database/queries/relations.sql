@@ -9,18 +9,50 @@ SELECT * FROM relations
 WHERE follower_username = $1 AND followed_username = $2
 LIMIT 1;
 
--- name: GetFollowing :many
-SELECT * FROM relations
-WHERE follower_username = $1
-ORDER BY id DESC
-LIMIT $2 OFFSET $3;
-
--- name: GetFollower :many
-SELECT * FROM relations
-WHERE followed_username = $1
-ORDER BY id DESC
-LIMIT $2 OFFSET $3;
-
 -- name: DeleteRelation :exec
 DELETE FROM relations
-WHERE follower_username = $1 AND followed_username = $2;
\ No newline at end of file
+WHERE follower_username = $1 AND followed_username = $2;
+
+-- name: ListFollowers :many
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.follower_username
+  WHERE relations.followed_username = sqlc.arg(username) AND relations.id > sqlc.arg(after_id) AND relations.id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY relations.id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.follower_username
+  WHERE relations.followed_username = sqlc.arg(username) AND relations.id > sqlc.arg(after_id) AND relations.id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY relations.id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY relation_id DESC;
+
+-- name: ListFollowing :many
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.followed_username
+  WHERE relations.follower_username = sqlc.arg(username) AND relations.id > sqlc.arg(after_id) AND relations.id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY relations.id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.followed_username
+  WHERE relations.follower_username = sqlc.arg(username) AND relations.id > sqlc.arg(after_id) AND relations.id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY relations.id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY relation_id DESC;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- name: CreateScheduledTweet :one
+INSERT INTO scheduled_tweets
+(username, tweet, media_ids, publish_at)
+VALUES ($1,$2,$3,$4)
+RETURNING *;
+
+-- name: ListScheduledTweets :many
+SELECT * FROM scheduled_tweets
+WHERE username = $1
+ORDER BY publish_at, id;
+
+-- name: RescheduleTweet :one
+UPDATE scheduled_tweets SET
+publish_at = $1, failed_at = NULL, error = ''
+WHERE id = $2 AND username = $3
+RETURNING *;
+
+-- name: CancelScheduledTweet :execrows
+DELETE FROM scheduled_tweets
+WHERE id = $1 AND username = $2;
+
+-- name: DeleteScheduledTweet :exec
+DELETE FROM scheduled_tweets
+WHERE id = $1;
+
+-- name: GetDueScheduledTweetForUpdate :one
+SELECT * FROM scheduled_tweets
+WHERE publish_at <= now() AND failed_at IS NULL
+ORDER BY publish_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkScheduledTweetFailed :one
+UPDATE scheduled_tweets SET
+failed_at = now(), error = $1
+WHERE id = $2
+RETURNING *;
@@ -0,0 +1,100 @@
+-- name: SearchTweets :many
+WITH search AS (
+  SELECT websearch_to_tsquery('english', sqlc.arg(terms)) && sqlc.arg(hashtags)::text::tsquery AS query
+), matches AS (
+  SELECT tweets.*, (
+    CASE WHEN sqlc.arg(match_all)::boolean THEN 1 ELSE ts_rank(tweet_search.search_vector, search.query) END
+  )::real AS rank
+  FROM tweets
+  JOIN tweet_search ON tweet_search.tweet_id = tweets.id
+  CROSS JOIN search
+  WHERE (sqlc.arg(match_all)::boolean OR tweet_search.search_vector @@ search.query)
+  AND tweets.retweet_of_id IS NULL
+  AND (sqlc.arg(from_username)::text = '' OR tweets.username = sqlc.arg(from_username))
+  AND tweets.created_at >= sqlc.arg(since) AND tweets.created_at < sqlc.arg(until)
+  AND coalesce(tweets.likes, 0) >= sqlc.arg(min_likes)
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocks.blocker_username = tweets.username AND blocks.blocked_username = sqlc.arg(viewer))
+    OR (blocks.blocker_username = sqlc.arg(viewer) AND blocks.blocked_username = tweets.username)
+  )
+  AND (
+    tweets.username = sqlc.arg(viewer)
+    OR NOT EXISTS (SELECT 1 FROM users WHERE users.username = tweets.username AND users.protected)
+    OR EXISTS (
+      SELECT 1 FROM relations
+      WHERE relations.follower_username = sqlc.arg(viewer) AND relations.followed_username = tweets.username
+    )
+  )
+)
+SELECT * FROM matches
+WHERE (rank, id) < (sqlc.arg(before_rank)::real, sqlc.arg(before_id)::bigint)
+ORDER BY rank DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: SearchUsers :many
+WITH matches AS (
+  SELECT users.username, users.name, users.followers_count, users.following_count, (
+    greatest(word_similarity(sqlc.arg(query)::text, users.username), word_similarity(sqlc.arg(query)::text, users.name))
+    + CASE WHEN lower(users.username) = lower(sqlc.arg(query)::text) THEN 1
+      WHEN users.username ILIKE sqlc.arg(prefix)::text THEN 0.5
+      ELSE 0 END
+  ) AS similarity
+  FROM users
+  WHERE (
+    sqlc.arg(query)::text <% users.username
+    OR sqlc.arg(query)::text <% users.name
+    OR users.username ILIKE sqlc.arg(prefix)::text
+  )
+  AND users.username <> sqlc.arg(viewer)
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocks.blocker_username = users.username AND blocks.blocked_username = sqlc.arg(viewer))
+    OR (blocks.blocker_username = sqlc.arg(viewer) AND blocks.blocked_username = users.username)
+  )
+  ORDER BY similarity DESC, users.username
+  LIMIT sqlc.arg(candidate_limit)
+), relatives AS (
+  SELECT users.username, users.name, users.followers_count, users.following_count, (
+    greatest(word_similarity(sqlc.arg(query)::text, users.username), word_similarity(sqlc.arg(query)::text, users.name))
+    + CASE WHEN lower(users.username) = lower(sqlc.arg(query)::text) THEN 1 ELSE 0.5 END
+  ) AS similarity
+  FROM users
+  WHERE users.username ILIKE sqlc.arg(prefix)::text
+  AND users.username IN (
+    SELECT relations.followed_username FROM relations WHERE relations.follower_username = sqlc.arg(viewer)
+    UNION
+    SELECT relations.follower_username FROM relations WHERE relations.followed_username = sqlc.arg(viewer)
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocks.blocker_username = users.username AND blocks.blocked_username = sqlc.arg(viewer))
+    OR (blocks.blocker_username = sqlc.arg(viewer) AND blocks.blocked_username = users.username)
+  )
+  ORDER BY similarity DESC, users.username
+  LIMIT sqlc.arg(candidate_limit)
+), candidates AS (
+  SELECT * FROM matches
+  UNION
+  SELECT * FROM relatives
+), related AS (
+  SELECT candidates.username, candidates.name, candidates.followers_count, candidates.following_count, candidates.similarity,
+  EXISTS (
+    SELECT 1 FROM relations
+    WHERE relations.follower_username = sqlc.arg(viewer) AND relations.followed_username = candidates.username
+  ) AS following,
+  EXISTS (
+    SELECT 1 FROM relations
+    WHERE relations.follower_username = candidates.username AND relations.followed_username = sqlc.arg(viewer)
+  ) AS followed_by
+  FROM candidates
+)
+SELECT username, name, followers_count, following_count, following, followed_by, (
+  similarity
+  + log(1 + coalesce(followers_count, 0)::float8) / 10
+  + CASE WHEN following THEN 0.3 ELSE 0 END
+  + CASE WHEN followed_by THEN 0.2 ELSE 0 END
+)::real AS score
+FROM related
+ORDER BY score DESC, username
+LIMIT sqlc.arg(page_size);
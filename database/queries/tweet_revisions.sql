@@ -0,0 +1,10 @@
+-- name: CreateTweetRevision :one
+INSERT INTO tweet_revisions
+(tweet_id, tweet, created_at)
+VALUES ($1,$2,$3)
+RETURNING *;
+
+-- name: ListTweetRevisions :many
+SELECT * FROM tweet_revisions
+WHERE tweet_id = $1
+ORDER BY id DESC;
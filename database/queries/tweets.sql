@@ -1,7 +1,7 @@
 -- name: CreateTweet :one
 INSERT INTO tweets
-(tweet, username)
-VALUES ($1,$2)
+(tweet, username, in_reply_to_id, retweet_of_id)
+VALUES ($1,$2,$3,$4)
 RETURNING *;
 
 -- name: GetTweet :one
@@ -20,12 +20,45 @@ likes = likes - 1
 WHERE id = $1
 RETURNING *;
 
--- name: GetListTweets :many
-SELECT * FROM tweets
-WHERE username = $1
-ORDER BY id DESC
-LIMIT $2 OFFSET $3;
-
 -- name: DeleteTweet :exec
 DELETE FROM tweets
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetTweetForUpdate :one
+SELECT * FROM tweets
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: UpdateTweet :one
+UPDATE tweets SET
+tweet = $1, edited_at = now()
+WHERE id = $2
+RETURNING *;
+
+-- name: ListUserTweets :many
+(
+  SELECT *
+  FROM tweets
+  WHERE username = sqlc.arg(username)
+  AND (sqlc.arg(include_replies)::boolean OR in_reply_to_id IS NULL)
+  AND (sqlc.arg(include_retweets)::boolean OR retweet_of_id IS NULL)
+  AND (NOT sqlc.arg(media_only)::boolean OR EXISTS (SELECT 1 FROM media WHERE media.tweet_id = tweets.id))
+  AND id > sqlc.arg(after_id) AND id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT *
+  FROM tweets
+  WHERE username = sqlc.arg(username)
+  AND (sqlc.arg(include_replies)::boolean OR in_reply_to_id IS NULL)
+  AND (sqlc.arg(include_retweets)::boolean OR retweet_of_id IS NULL)
+  AND (NOT sqlc.arg(media_only)::boolean OR EXISTS (SELECT 1 FROM media WHERE media.tweet_id = tweets.id))
+  AND id > sqlc.arg(after_id) AND id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY id DESC;
@@ -8,6 +8,11 @@ RETURNING *;
 SELECT * FROM users
 WHERE username = $1 LIMIT 1;
 
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: UpdateEmail :one
 UPDATE users SET 
 email = $1
@@ -48,4 +53,21 @@ RETURNING *;
 UPDATE users SET
 followers_count = followers_count - 1
 WHERE username = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: PinTweet :one
+UPDATE users SET
+pinned_tweet_id = $1
+WHERE username = $2
+RETURNING *;
+
+-- name: UnpinTweet :execrows
+UPDATE users SET
+pinned_tweet_id = NULL
+WHERE username = $1 AND pinned_tweet_id = $2;
+
+-- name: SetProtected :one
+UPDATE users SET
+protected = $1
+WHERE username = $2
+RETURNING *;
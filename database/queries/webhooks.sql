@@ -0,0 +1,81 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks
+(username, url, secret, events)
+VALUES ($1,$2,$3,$4)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 AND username = $2 LIMIT 1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+WHERE username = $1
+ORDER BY id;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1 AND username = $2;
+
+-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries
+(webhook_id, event, payload)
+SELECT id, sqlc.arg(event)::varchar, sqlc.arg(payload)::jsonb FROM webhooks
+WHERE username = ANY(sqlc.arg(usernames)::varchar[]) AND sqlc.arg(event) = ANY(events);
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries SET
+next_attempt_at = sqlc.arg(lease_until)
+FROM webhooks
+WHERE webhooks.id = webhook_deliveries.webhook_id AND webhook_deliveries.id IN (
+  SELECT pending.id FROM webhook_deliveries AS pending
+  WHERE pending.delivered_at IS NULL AND pending.dead_at IS NULL AND pending.next_attempt_at <= now()
+  ORDER BY pending.next_attempt_at, pending.id
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event, webhook_deliveries.payload, webhook_deliveries.attempts, webhooks.url, webhooks.secret;
+
+-- name: MarkWebhookDelivered :exec
+UPDATE webhook_deliveries SET
+attempts = attempts + 1,
+last_status = $2,
+last_error = NULL,
+delivered_at = now()
+WHERE id = $1;
+
+-- name: FailWebhookDelivery :exec
+UPDATE webhook_deliveries SET
+attempts = attempts + 1,
+last_status = sqlc.arg(last_status),
+last_error = sqlc.arg(last_error),
+next_attempt_at = sqlc.arg(next_attempt_at),
+dead_at = CASE WHEN sqlc.arg(dead)::boolean THEN now() END
+WHERE id = sqlc.arg(id);
+
+-- name: ListDeadWebhookDeliveries :many
+(
+  SELECT * FROM webhook_deliveries
+  WHERE webhook_id = sqlc.arg(webhook_id) AND dead_at IS NOT NULL
+  AND id > sqlc.arg(after_id) AND id < sqlc.arg(before_id)
+  AND NOT sqlc.arg(reverse)::boolean
+  ORDER BY id DESC
+  LIMIT sqlc.arg(page_size)
+)
+UNION ALL
+(
+  SELECT * FROM webhook_deliveries
+  WHERE webhook_id = sqlc.arg(webhook_id) AND dead_at IS NOT NULL
+  AND id > sqlc.arg(after_id) AND id < sqlc.arg(before_id)
+  AND sqlc.arg(reverse)::boolean
+  ORDER BY id ASC
+  LIMIT sqlc.arg(page_size)
+)
+ORDER BY id DESC;
+
+-- name: RetryWebhookDelivery :execrows
+UPDATE webhook_deliveries SET
+attempts = 0,
+next_attempt_at = now(),
+dead_at = NULL
+WHERE id = $1 AND webhook_id = $2 AND dead_at IS NOT NULL;
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+type BlockTxParams struct {
+	BlockerUsername string `json:"blocker_username"`
+	BlockedUsername string `json:"blocked_username"`
+}
+
+// BlockTx blocks the user and removes the follow relations and pending follow
+// requests in both directions, keeping the follower counters and home timelines in sync
+func (dbt *DBTransaction) BlockTx(c context.Context, arg BlockTxParams) error {
+	return dbt.execTransaction(c, func(q *Queries) error {
+		_, err := q.CreateBlock(c, CreateBlockParams{
+			BlockerUsername: arg.BlockerUsername,
+			BlockedUsername: arg.BlockedUsername,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = dropRelation(c, q, arg.BlockerUsername, arg.BlockedUsername); err != nil {
+			return err
+		}
+		if err = dropRelation(c, q, arg.BlockedUsername, arg.BlockerUsername); err != nil {
+			return err
+		}
+
+		_, err = q.DeleteFollowRequest(c, DeleteFollowRequestParams{
+			RequesterUsername: arg.BlockerUsername,
+			TargetUsername:    arg.BlockedUsername,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.DeleteFollowRequest(c, DeleteFollowRequestParams{
+			RequesterUsername: arg.BlockedUsername,
+			TargetUsername:    arg.BlockerUsername,
+		})
+		return err
+	})
+}
+
+func dropRelation(c context.Context, q *Queries, follower, followed string) error {
+	_, err := q.GetRelations(c, GetRelationsParams{
+		FollowerUsername: follower,
+		FollowedUsername: followed,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	err = q.DeleteRelation(c, DeleteRelationParams{
+		FollowerUsername: follower,
+		FollowedUsername: followed,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = q.DecrementFollowing(c, follower); err != nil {
+		return err
+	}
+	if _, err = q.DecrementFollower(c, followed); err != nil {
+		return err
+	}
+
+	_, err = q.PruneHomeTimeline(c, PruneHomeTimelineParams{
+		Username:       follower,
+		AuthorUsername: followed,
+	})
+	return err
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTx(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	blocker := CreateRandomUser(t)
+	blocked := CreateRandomUser(t)
+
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{Username: blocker.Username, FollowUser: blocked.Username})
+	require.NoError(t, err)
+	_, err = dbt.FollowTx(context.Background(), FollowInputArgs{Username: blocked.Username, FollowUser: blocker.Username})
+	require.NoError(t, err)
+
+	arg := BlockTxParams{BlockerUsername: blocker.Username, BlockedUsername: blocked.Username}
+	require.NoError(t, dbt.BlockTx(context.Background(), arg))
+	//blocking twice is a no-op
+	require.NoError(t, dbt.BlockTx(context.Background(), arg))
+
+	isBlocked, err := dbt.IsBlocked(context.Background(), IsBlockedParams{BlockerUsername: blocker.Username, BlockedUsername: blocked.Username})
+	require.NoError(t, err)
+	require.True(t, isBlocked)
+	isBlocked, err = dbt.IsBlocked(context.Background(), IsBlockedParams{BlockerUsername: blocked.Username, BlockedUsername: blocker.Username})
+	require.NoError(t, err)
+	require.False(t, isBlocked)
+
+	_, err = dbt.GetRelations(context.Background(), GetRelationsParams{FollowerUsername: blocker.Username, FollowedUsername: blocked.Username})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = dbt.GetRelations(context.Background(), GetRelationsParams{FollowerUsername: blocked.Username, FollowedUsername: blocker.Username})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	blockerAfter, err := dbt.GetUser(context.Background(), blocker.Username)
+	require.NoError(t, err)
+	require.Equal(t, blocker.FollowersCount.Int32, blockerAfter.FollowersCount.Int32)
+	require.Equal(t, blocker.FollowingCount.Int32, blockerAfter.FollowingCount.Int32)
+
+	unblocked, err := dbt.DeleteBlock(context.Background(), DeleteBlockParams{BlockerUsername: blocker.Username, BlockedUsername: blocked.Username})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unblocked)
+}
+
+func TestListUserTweetsFilters(t *testing.T) {
+	original := CreateTweet(t)
+	user := CreateRandomUser(t)
+
+	plain, err := testQueries.CreateTweet(context.Background(), CreateTweetParams{Tweet: tweets, Username: user.Username})
+	require.NoError(t, err)
+	reply, err := testQueries.CreateTweet(context.Background(), CreateTweetParams{
+		Tweet:       tweets,
+		Username:    user.Username,
+		InReplyToID: sql.NullInt64{Int64: original.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	retweet, err := testQueries.CreateTweet(context.Background(), CreateTweetParams{
+		Tweet:       original.Tweet,
+		Username:    user.Username,
+		RetweetOfID: sql.NullInt64{Int64: original.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	list := func(includeReplies, includeRetweets, mediaOnly bool) []int64 {
+		rows, err := testQueries.ListUserTweets(context.Background(), ListUserTweetsParams{
+			Username:        user.Username,
+			BeforeID:        math.MaxInt64,
+			IncludeReplies:  includeReplies,
+			IncludeRetweets: includeRetweets,
+			MediaOnly:       mediaOnly,
+			PageSize:        10,
+		})
+		require.NoError(t, err)
+		ids := []int64{}
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+		return ids
+	}
+
+	require.Equal(t, []int64{retweet.ID, reply.ID, plain.ID}, list(true, true, false))
+	require.Equal(t, []int64{retweet.ID, plain.ID}, list(false, true, false))
+	require.Equal(t, []int64{reply.ID, plain.ID}, list(true, false, false))
+	require.Empty(t, list(true, true, true))
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarks(t *testing.T) {
+	user := CreateRandomUser(t)
+	first := CreateTweet(t)
+	second := CreateTweet(t)
+
+	for _, tweet := range []Tweets{first, second} {
+		added, err := testQueries.CreateBookmark(context.Background(), CreateBookmarkParams{
+			Username: user.Username,
+			TweetID:  tweet.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), added)
+	}
+
+	//adding again is a no-op
+	added, err := testQueries.CreateBookmark(context.Background(), CreateBookmarkParams{
+		Username: user.Username,
+		TweetID:  first.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, added)
+
+	page, err := testQueries.ListBookmarks(context.Background(), ListBookmarksParams{
+		Username: user.Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, second.ID, page[0].ID)
+
+	page, err = testQueries.ListBookmarks(context.Background(), ListBookmarksParams{
+		Username: user.Username,
+		BeforeID: page[0].BookmarkID,
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, first.ID, page[0].ID)
+
+	ids, err := testQueries.ListBookmarkedTweetIDs(context.Background(), ListBookmarkedTweetIDsParams{
+		Username: user.Username,
+		TweetIds: []int64{first.ID, second.ID, second.ID + 1},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{first.ID, second.ID}, ids)
+
+	//deleting the tweet removes the bookmark with it
+	require.NoError(t, testQueries.DeleteTweet(context.Background(), first.ID))
+	page, err = testQueries.ListBookmarks(context.Background(), ListBookmarksParams{
+		Username: user.Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, second.ID, page[0].ID)
+
+	removed, err := testQueries.DeleteBookmark(context.Background(), DeleteBookmarkParams{
+		Username: user.Username,
+		TweetID:  second.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), removed)
+}
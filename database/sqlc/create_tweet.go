@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrMediaUnavailable = errors.New("media doesn't exist, isn't yours, or is already attached to a tweet")
+
+type CreateTweetTxParams struct {
+	Username string  `json:"username"`
+	Tweet    string  `json:"tweet"`
+	MediaIDs []int64 `json:"media_ids"`
+	// InReplyToID and RetweetOfID are optional
+	InReplyToID sql.NullInt64 `json:"in_reply_to_id"`
+	RetweetOfID sql.NullInt64 `json:"retweet_of_id"`
+	// Poll is optional
+	Poll *CreatePollTxParams `json:"poll"`
+}
+
+type CreateTweetTxResult struct {
+	Tweet Tweets     `json:"tweet"`
+	Media []Media    `json:"media"`
+	Poll  *TweetPoll `json:"poll"`
+	// Notified are the users notified of a reply or a mention
+	Notified []TweetNotification `json:"notified"`
+}
+
+// CreateTweetTx creates the tweet, attaches the uploaded media and creates the poll if any,
+// every media must belong to the author and not be attached yet
+func (dbt *DBTransaction) CreateTweetTx(c context.Context, arg CreateTweetTxParams) (CreateTweetTxResult, error) {
+	var res CreateTweetTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res, err = publishTweet(c, q, arg)
+		return err
+	})
+
+	return res, err
+}
+
+// publishTweet is the single place tweets get published, so scheduled tweets and
+// anything else that posts on behalf of a user go through the same steps
+func publishTweet(c context.Context, q *Queries, arg CreateTweetTxParams) (CreateTweetTxResult, error) {
+	var res CreateTweetTxResult
+
+	tweet, err := q.CreateTweet(c, CreateTweetParams{
+		Tweet:       arg.Tweet,
+		Username:    arg.Username,
+		InReplyToID: arg.InReplyToID,
+		RetweetOfID: arg.RetweetOfID,
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Tweet = tweet
+
+	//the author sees it right away, followers once the fan-out worker picks it up
+	err = q.AddToHomeTimeline(c, AddToHomeTimelineParams{
+		Username:       arg.Username,
+		TweetID:        tweet.ID,
+		AuthorUsername: arg.Username,
+	})
+	if err != nil {
+		return res, err
+	}
+	err = q.CreateTimelineFanout(c, CreateTimelineFanoutParams{
+		TweetID:        tweet.ID,
+		AuthorUsername: arg.Username,
+	})
+	if err != nil {
+		return res, err
+	}
+	err = enqueueWebhooks(c, q, WebhookTweetCreated, []string{arg.Username}, newTweetWebhookData(tweet))
+	if err != nil {
+		return res, err
+	}
+
+	//attach media in the order they were given
+	res.Media = []Media{}
+	for i, id := range arg.MediaIDs {
+		media, err := q.AttachMedia(c, AttachMediaParams{
+			TweetID:  sql.NullInt64{Int64: tweet.ID, Valid: true},
+			Position: int32(i),
+			ID:       id,
+			Username: arg.Username,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return res, ErrMediaUnavailable
+			}
+			return res, err
+		}
+		res.Media = append(res.Media, media)
+	}
+
+	//a retweet carries the original text, its mentions were notified already
+	if !arg.RetweetOfID.Valid {
+		res.Notified, err = notifyTweet(c, q, tweet)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	if arg.Poll != nil {
+		poll, err := attachPoll(c, q, tweet.ID, *arg.Poll)
+		if err != nil {
+			return res, err
+		}
+		res.Poll = &poll
+	}
+
+	return res, nil
+}
@@ -10,8 +10,29 @@ type Transaction interface {
 	Querier
 	FollowTx(c context.Context, arg FollowInputArgs) (FollowInputResult,error)
 	UnfollowTx(c context.Context, arg FollowInputArgs) error
-	LikeTweetTx(c context.Context, arg CreateLikeRelationParams) error
+	ApproveFollowRequestTx(c context.Context, arg FollowInputArgs) (FollowInputResult, error)
+	LikeTweetTx(c context.Context, arg CreateLikeRelationParams) (Tweets, error)
 	UnlikeTweetTx(c context.Context, arg DeleteLikeRelationParams) error
+	CreateTweetTx(c context.Context, arg CreateTweetTxParams) (CreateTweetTxResult, error)
+	EditTweetTx(c context.Context, arg EditTweetTxParams) (Tweets, error)
+	PublishScheduledTweetTx(c context.Context) (PublishScheduledTweetTxResult, error)
+	PublishDraftTx(c context.Context, arg PublishDraftTxParams) (CreateTweetTxResult, error)
+	VotePollTx(c context.Context, arg VotePollTxParams) (TweetPoll, error)
+	ClosePollTx(c context.Context) (TweetPoll, error)
+	BlockTx(c context.Context, arg BlockTxParams) error
+	FanoutTweetTx(c context.Context, celebrityThreshold int32) (TimelineFanouts, error)
+	RelayOutboxTx(c context.Context, batchSize int32, publish func(Outbox) error) (int, error)
+	SendDirectMessageTx(c context.Context, arg SendDirectMessageTxParams) (SendDirectMessageTxResult, error)
+	CreateGroupConversationTx(c context.Context, arg CreateGroupConversationTxParams) (GroupChangeTxResult, error)
+	AddGroupMembersTx(c context.Context, arg AddGroupMembersTxParams) (GroupChangeTxResult, error)
+	RemoveGroupMemberTx(c context.Context, arg GroupMemberTxParams) (GroupChangeTxResult, error)
+	LeaveGroupTx(c context.Context, arg GroupMemberTxParams) (GroupChangeTxResult, error)
+	RenameGroupTx(c context.Context, arg RenameGroupTxParams) (GroupChangeTxResult, error)
+	SetGroupRoleTx(c context.Context, arg SetGroupRoleTxParams) (GroupChangeTxResult, error)
+	RegisterDeviceTx(c context.Context, arg RegisterDeviceTxParams) (DeviceTxResult, error)
+	AddPrekeysTx(c context.Context, arg AddPrekeysTxParams) (DeviceTxResult, error)
+	ClaimPrekeyBundlesTx(c context.Context, arg ClaimPrekeyBundlesTxParams) ([]PrekeyBundle, error)
+	SendEnvelopesTx(c context.Context, arg SendEnvelopesTxParams) ([]SentEnvelope, error)
 }
 
 type DBTransaction struct {
@@ -0,0 +1,247 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// MaxDevices caps the devices a user can publish keys for
+const MaxDevices = 10
+
+var ErrTooManyDevices = fmt.Errorf("users can't have more than %d devices", MaxDevices)
+
+// SignedPrekey is a medium-term X25519 prekey with the device's Ed25519 signature
+// over it
+type SignedPrekey struct {
+	KeyID     int64  `json:"key_id"`
+	PublicKey []byte `json:"public_key"`
+	Signature []byte `json:"signature"`
+}
+
+// Prekey is a one-time X25519 prekey
+type Prekey struct {
+	KeyID     int64  `json:"key_id"`
+	PublicKey []byte `json:"public_key"`
+}
+
+// ownDevice returns the device when it belongs to username, sql.ErrNoRows otherwise
+func ownDevice(c context.Context, q Querier, deviceID int64, username string) (Devices, error) {
+	device, err := q.GetDevice(c, deviceID)
+	if err != nil {
+		return Devices{}, err
+	}
+	if device.Username != username {
+		return Devices{}, sql.ErrNoRows
+	}
+	return device, nil
+}
+
+func addPrekeys(c context.Context, q *Queries, deviceID int64, prekeys []Prekey) (int64, error) {
+	for _, prekey := range prekeys {
+		_, err := q.AddOneTimePrekey(c, AddOneTimePrekeyParams{
+			DeviceID:  deviceID,
+			KeyID:     prekey.KeyID,
+			PublicKey: prekey.PublicKey,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	counts, err := q.CountOneTimePrekeys(c, []int64{deviceID})
+	if err != nil || len(counts) == 0 {
+		return 0, err
+	}
+	return counts[0].Count, nil
+}
+
+type RegisterDeviceTxParams struct {
+	Username       string       `json:"username"`
+	Name           string       `json:"name"`
+	IdentityKey    []byte       `json:"identity_key"`
+	SigningKey     []byte       `json:"signing_key"`
+	SignedPrekey   SignedPrekey `json:"signed_prekey"`
+	OneTimePrekeys []Prekey     `json:"one_time_prekeys"`
+}
+
+type DeviceTxResult struct {
+	Device      Devices `json:"device"`
+	PrekeyCount int64   `json:"prekey_count"`
+}
+
+// RegisterDeviceTx publishes the keys of a new device. The keys are expected to be
+// checked already, they're stored as they come. The user's row stays locked until
+// commit so concurrent registrations can't both pass the MaxDevices check.
+func (dbt *DBTransaction) RegisterDeviceTx(c context.Context, arg RegisterDeviceTxParams) (DeviceTxResult, error) {
+	var res DeviceTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		if _, err := q.GetUserForUpdate(c, arg.Username); err != nil {
+			return err
+		}
+
+		devices, err := q.CountDevices(c, arg.Username)
+		if err != nil {
+			return err
+		}
+		if devices >= MaxDevices {
+			return ErrTooManyDevices
+		}
+
+		res.Device, err = q.CreateDevice(c, CreateDeviceParams{
+			Username:              arg.Username,
+			Name:                  arg.Name,
+			IdentityKey:           arg.IdentityKey,
+			SigningKey:            arg.SigningKey,
+			SignedPrekeyID:        arg.SignedPrekey.KeyID,
+			SignedPrekey:          arg.SignedPrekey.PublicKey,
+			SignedPrekeySignature: arg.SignedPrekey.Signature,
+		})
+		if err != nil {
+			return err
+		}
+
+		res.PrekeyCount, err = addPrekeys(c, q, res.Device.ID, arg.OneTimePrekeys)
+		return err
+	})
+
+	return res, err
+}
+
+type AddPrekeysTxParams struct {
+	DeviceID int64    `json:"device_id"`
+	Username string   `json:"username"`
+	Prekeys  []Prekey `json:"prekeys"`
+}
+
+// AddPrekeysTx tops up the one-time prekeys of the user's device. Key ids already
+// uploaded are skipped.
+func (dbt *DBTransaction) AddPrekeysTx(c context.Context, arg AddPrekeysTxParams) (DeviceTxResult, error) {
+	var res DeviceTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res.Device, err = ownDevice(c, q, arg.DeviceID, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		res.PrekeyCount, err = addPrekeys(c, q, res.Device.ID, arg.Prekeys)
+		return err
+	})
+
+	return res, err
+}
+
+type ClaimPrekeyBundlesTxParams struct {
+	Requester string `json:"requester"`
+	Username  string `json:"username"`
+}
+
+// PrekeyBundle is what a device needs to start a session with another one.
+// OneTimePrekey is nil once the device ran out of them.
+type PrekeyBundle struct {
+	Device        Devices         `json:"device"`
+	OneTimePrekey *OneTimePrekeys `json:"one_time_prekey"`
+}
+
+// ClaimPrekeyBundlesTx hands out a bundle for every device of the user, each with
+// one of its one-time prekeys which is never handed out again. Only users allowed
+// to message the user get them, users can always get the bundles of their own
+// devices.
+func (dbt *DBTransaction) ClaimPrekeyBundlesTx(c context.Context, arg ClaimPrekeyBundlesTxParams) ([]PrekeyBundle, error) {
+	var bundles []PrekeyBundle
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		if arg.Requester != arg.Username {
+			if _, err := q.GetUser(c, arg.Username); err != nil {
+				return err
+			}
+			if err := canMessage(c, q, arg.Requester, arg.Username, 0); err != nil {
+				return err
+			}
+		}
+
+		devices, err := q.ListDevices(c, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		bundles = make([]PrekeyBundle, len(devices))
+		for i, device := range devices {
+			bundles[i].Device = device
+
+			prekey, err := q.ClaimOneTimePrekey(c, device.ID)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			bundles[i].OneTimePrekey = &prekey
+		}
+		return nil
+	})
+
+	return bundles, err
+}
+
+type EnvelopeInput struct {
+	DeviceID   int64  `json:"device_id"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type SendEnvelopesTxParams struct {
+	Sender         string          `json:"sender"`
+	SenderDeviceID int64           `json:"sender_device_id"`
+	Envelopes      []EnvelopeInput `json:"envelopes"`
+}
+
+// SentEnvelope is a stored envelope with the user owning the device it's for
+type SentEnvelope struct {
+	Envelope  Envelopes `json:"envelope"`
+	Recipient string    `json:"recipient"`
+}
+
+// SendEnvelopesTx stores ciphertext for each device it's encrypted to, all or none.
+// Devices of other users are held to the same rules as direct messages, a user's
+// own devices always get theirs.
+func (dbt *DBTransaction) SendEnvelopesTx(c context.Context, arg SendEnvelopesTxParams) ([]SentEnvelope, error) {
+	var sent []SentEnvelope
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		if _, err := ownDevice(c, q, arg.SenderDeviceID, arg.Sender); err != nil {
+			return err
+		}
+
+		allowed := map[string]bool{arg.Sender: true}
+		sent = make([]SentEnvelope, len(arg.Envelopes))
+		for i, input := range arg.Envelopes {
+			device, err := q.GetDevice(c, input.DeviceID)
+			if err != nil {
+				return err
+			}
+			if !allowed[device.Username] {
+				if err := canMessage(c, q, arg.Sender, device.Username, 0); err != nil {
+					return err
+				}
+				allowed[device.Username] = true
+			}
+
+			sent[i].Recipient = device.Username
+			sent[i].Envelope, err = q.CreateEnvelope(c, CreateEnvelopeParams{
+				RecipientDeviceID: device.ID,
+				SenderUsername:    arg.Sender,
+				SenderDeviceID:    arg.SenderDeviceID,
+				Ciphertext:        input.Ciphertext,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return sent, err
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+func registerDevice(t *testing.T, dbt Transaction, username string, prekeys ...Prekey) DeviceTxResult {
+	registered, err := dbt.RegisterDeviceTx(context.Background(), RegisterDeviceTxParams{
+		Username:       username,
+		Name:           util.GetRandomString(6),
+		IdentityKey:    []byte(util.GetRandomString(32)),
+		SigningKey:     []byte(util.GetRandomString(32)),
+		SignedPrekey:   SignedPrekey{KeyID: 1, PublicKey: []byte(util.GetRandomString(32)), Signature: []byte(util.GetRandomString(64))},
+		OneTimePrekeys: prekeys,
+	})
+	require.NoError(t, err)
+	return registered
+}
+
+func TestRegisterDeviceConcurrently(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	user := CreateRandomUser(t)
+
+	// twice the cap registering at once, the user's row lock lets MaxDevices through
+	errs := make(chan error, 2*MaxDevices)
+	for i := 0; i < 2*MaxDevices; i++ {
+		go func() {
+			_, err := dbt.RegisterDeviceTx(context.Background(), RegisterDeviceTxParams{
+				Username:     user.Username,
+				Name:         util.GetRandomString(6),
+				IdentityKey:  []byte(util.GetRandomString(32)),
+				SigningKey:   []byte(util.GetRandomString(32)),
+				SignedPrekey: SignedPrekey{KeyID: 1, PublicKey: []byte(util.GetRandomString(32)), Signature: []byte(util.GetRandomString(64))},
+			})
+			errs <- err
+		}()
+	}
+
+	registered := 0
+	for i := 0; i < 2*MaxDevices; i++ {
+		err := <-errs
+		if err == nil {
+			registered++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTooManyDevices)
+	}
+	require.Equal(t, MaxDevices, registered)
+
+	devices, err := dbt.CountDevices(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(MaxDevices), devices)
+}
+
+func TestPrekeyBundles(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	alice := CreateRandomUser(t)
+	bob := CreateRandomUser(t)
+
+	device := registerDevice(t, dbt, bob.Username,
+		Prekey{KeyID: 1, PublicKey: []byte(util.GetRandomString(32))},
+		Prekey{KeyID: 2, PublicKey: []byte(util.GetRandomString(32))},
+	)
+	require.Equal(t, int64(2), device.PrekeyCount)
+
+	// uploading a key id again leaves the first upload
+	added, err := dbt.AddPrekeysTx(context.Background(), AddPrekeysTxParams{
+		DeviceID: device.Device.ID,
+		Username: bob.Username,
+		Prekeys:  []Prekey{{KeyID: 2, PublicKey: []byte(util.GetRandomString(32))}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), added.PrekeyCount)
+
+	// each one-time prekey is handed out once, oldest key id first
+	for _, keyID := range []int64{1, 2} {
+		bundles, err := dbt.ClaimPrekeyBundlesTx(context.Background(), ClaimPrekeyBundlesTxParams{Requester: alice.Username, Username: bob.Username})
+		require.NoError(t, err)
+		require.Len(t, bundles, 1)
+		require.Equal(t, keyID, bundles[0].OneTimePrekey.KeyID)
+	}
+	bundles, err := dbt.ClaimPrekeyBundlesTx(context.Background(), ClaimPrekeyBundlesTxParams{Requester: alice.Username, Username: bob.Username})
+	require.NoError(t, err)
+	require.Nil(t, bundles[0].OneTimePrekey)
+	require.Equal(t, device.Device.SignedPrekey, bundles[0].Device.SignedPrekey)
+
+	err = dbt.BlockTx(context.Background(), BlockTxParams{BlockerUsername: bob.Username, BlockedUsername: alice.Username})
+	require.NoError(t, err)
+	_, err = dbt.ClaimPrekeyBundlesTx(context.Background(), ClaimPrekeyBundlesTxParams{Requester: alice.Username, Username: bob.Username})
+	require.ErrorIs(t, err, ErrDirectMessageBlocked)
+}
+
+func TestSendEnvelopes(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	alice := CreateRandomUser(t)
+	bob := CreateRandomUser(t)
+	aliceDevice := registerDevice(t, dbt, alice.Username).Device
+	bobDevice := registerDevice(t, dbt, bob.Username).Device
+
+	// only from one of the sender's own devices
+	_, err := dbt.SendEnvelopesTx(context.Background(), SendEnvelopesTxParams{
+		Sender:         alice.Username,
+		SenderDeviceID: bobDevice.ID,
+		Envelopes:      []EnvelopeInput{{DeviceID: bobDevice.ID, Ciphertext: []byte("sealed")}},
+	})
+	require.Error(t, err)
+
+	sent, err := dbt.SendEnvelopesTx(context.Background(), SendEnvelopesTxParams{
+		Sender:         alice.Username,
+		SenderDeviceID: aliceDevice.ID,
+		Envelopes:      []EnvelopeInput{{DeviceID: bobDevice.ID, Ciphertext: []byte("sealed")}},
+	})
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	require.Equal(t, bob.Username, sent[0].Recipient)
+
+	pending, err := dbt.ListPendingEnvelopes(context.Background(), ListPendingEnvelopesParams{RecipientDeviceID: bobDevice.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, []byte("sealed"), pending[0].Ciphertext)
+
+	acked, err := dbt.DeleteEnvelopes(context.Background(), DeleteEnvelopesParams{RecipientDeviceID: bobDevice.ID, Ids: []int64{pending[0].ID}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), acked)
+}
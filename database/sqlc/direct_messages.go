@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Message kinds, system messages record membership changes
+const (
+	MessageText   = "text"
+	MessageSystem = "system"
+)
+
+// Who can start a conversation with a user
+const (
+	DMAllowEveryone  = "everyone"
+	DMAllowFollowing = "following"
+)
+
+var (
+	ErrDirectMessageBlocked = errors.New("one of you has blocked the other")
+	ErrDirectMessagesClosed = errors.New("user only takes messages from people they follow")
+)
+
+// DirectKey identifies the one-to-one conversation between two users, whoever
+// writes first
+func DirectKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
+
+// LoadDMSettings returns the DM settings of username, everyone can message users
+// who never set theirs
+func LoadDMSettings(c context.Context, q Querier, username string) (DmSettings, error) {
+	settings, err := q.GetDMSettings(c, username)
+	if err == sql.ErrNoRows {
+		return DmSettings{Username: username, AllowFrom: DMAllowEveryone}, nil
+	}
+	return settings, err
+}
+
+// canMessage reports why sender can't write to recipient in a conversation, if they
+// can't. Recipients taking messages from people they follow only can still be
+// answered once they wrote in the conversation themselves.
+func canMessage(c context.Context, q Querier, sender, recipient string, conversationID int64) error {
+	for _, arg := range []IsBlockedParams{
+		{BlockerUsername: recipient, BlockedUsername: sender},
+		{BlockerUsername: sender, BlockedUsername: recipient},
+	} {
+		blocked, err := q.IsBlocked(c, arg)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrDirectMessageBlocked
+		}
+	}
+
+	settings, err := LoadDMSettings(c, q, recipient)
+	if err != nil {
+		return err
+	}
+	if settings.AllowFrom == DMAllowEveryone {
+		return nil
+	}
+
+	_, err = q.GetRelations(c, GetRelationsParams{
+		FollowerUsername: recipient,
+		FollowedUsername: sender,
+	})
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	wrote, err := q.HasSentMessage(c, HasSentMessageParams{
+		ConversationID: conversationID,
+		SenderUsername: recipient,
+	})
+	if err != nil {
+		return err
+	}
+	if !wrote {
+		return ErrDirectMessagesClosed
+	}
+	return nil
+}
+
+// postMessage adds a message to the conversation, the sender has read up to it
+func postMessage(c context.Context, q *Queries, conversationID int64, sender, body, kind string) (Messages, error) {
+	message, err := q.CreateMessage(c, CreateMessageParams{
+		ConversationID: conversationID,
+		SenderUsername: sender,
+		Body:           body,
+		Kind:           kind,
+	})
+	if err != nil {
+		return message, err
+	}
+
+	err = q.SetConversationLastMessage(c, SetConversationLastMessageParams{
+		ID:            conversationID,
+		LastMessageID: message.ID,
+	})
+	if err != nil {
+		return message, err
+	}
+
+	_, err = q.MarkConversationRead(c, MarkConversationReadParams{
+		MessageID:      message.ID,
+		ConversationID: conversationID,
+		Username:       sender,
+	})
+	//the sender of a system message may have just left
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	return message, err
+}
+
+// SendDirectMessageTxParams addresses the message either to Recipient, starting the
+// conversation with them if needed, or to an existing ConversationID
+type SendDirectMessageTxParams struct {
+	Sender         string `json:"sender"`
+	Recipient      string `json:"recipient"`
+	ConversationID int64  `json:"conversation_id"`
+	Body           string `json:"body"`
+}
+
+type SendDirectMessageTxResult struct {
+	Message      Messages `json:"message"`
+	Participants []string `json:"participants"`
+}
+
+// SendDirectMessageTx stores the message and moves the sender's read receipt past it.
+// It returns sql.ErrNoRows when the sender isn't in the conversation. Group members
+// can always write in their group, the DM settings only hold for one-to-one ones.
+func (dbt *DBTransaction) SendDirectMessageTx(c context.Context, arg SendDirectMessageTxParams) (SendDirectMessageTxResult, error) {
+	var res SendDirectMessageTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		conversationID := arg.ConversationID
+		isGroup := false
+		if conversationID == 0 {
+			conversation, err := q.UpsertDirectConversation(c, sql.NullString{
+				String: DirectKey(arg.Sender, arg.Recipient),
+				Valid:  true,
+			})
+			if err != nil {
+				return err
+			}
+			conversationID = conversation.ID
+
+			for _, username := range []string{arg.Sender, arg.Recipient} {
+				err = q.AddConversationParticipant(c, AddConversationParticipantParams{
+					ConversationID: conversationID,
+					Username:       username,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		} else {
+			_, err := q.GetConversationParticipant(c, GetConversationParticipantParams{
+				ConversationID: conversationID,
+				Username:       arg.Sender,
+			})
+			if err != nil {
+				return err
+			}
+
+			conversation, err := q.GetConversationForUpdate(c, conversationID)
+			if err != nil {
+				return err
+			}
+			isGroup = conversation.IsGroup
+		}
+
+		participants, err := q.ListConversationParticipants(c, []int64{conversationID})
+		if err != nil {
+			return err
+		}
+		res.Participants = make([]string, len(participants))
+		for i, participant := range participants {
+			res.Participants[i] = participant.Username
+			//joining a group is agreeing to hear from its members
+			if participant.Username == arg.Sender || isGroup {
+				continue
+			}
+			if err := canMessage(c, q, arg.Sender, participant.Username, conversationID); err != nil {
+				return err
+			}
+		}
+
+		res.Message, err = postMessage(c, q, conversationID, arg.Sender, arg.Body, MessageText)
+		return err
+	})
+
+	return res, err
+}
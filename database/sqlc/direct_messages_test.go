@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sendDirectMessage(t *testing.T, dbt Transaction, sender, recipient string) SendDirectMessageTxResult {
+	sent, err := dbt.SendDirectMessageTx(context.Background(), SendDirectMessageTxParams{
+		Sender:    sender,
+		Recipient: recipient,
+		Body:      "hello " + recipient,
+	})
+	require.NoError(t, err)
+	return sent
+}
+
+func TestDirectConversation(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	alice := CreateRandomUser(t)
+	bob := CreateRandomUser(t)
+
+	first := sendDirectMessage(t, dbt, alice.Username, bob.Username)
+	second := sendDirectMessage(t, dbt, bob.Username, alice.Username)
+
+	// both write in the same conversation, whoever started it
+	require.Equal(t, first.Message.ConversationID, second.Message.ConversationID)
+	require.ElementsMatch(t, []string{alice.Username, bob.Username}, second.Participants)
+
+	conversations, err := dbt.ListConversations(context.Background(), ListConversationsParams{
+		Username: alice.Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, conversations, 1)
+	require.Equal(t, second.Message.ID, conversations[0].LastMessageID)
+	require.Equal(t, int64(1), conversations[0].UnreadCount)
+
+	unread, err := dbt.CountUnreadConversations(context.Background(), alice.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unread)
+
+	receipt, err := dbt.MarkConversationRead(context.Background(), MarkConversationReadParams{
+		MessageID:      math.MaxInt64,
+		ConversationID: second.Message.ConversationID,
+		Username:       alice.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, second.Message.ID, receipt.LastReadMessageID)
+
+	// receipts don't move back
+	receipt, err = dbt.MarkConversationRead(context.Background(), MarkConversationReadParams{
+		MessageID:      first.Message.ID,
+		ConversationID: second.Message.ConversationID,
+		Username:       alice.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, second.Message.ID, receipt.LastReadMessageID)
+
+	unread, err = dbt.CountUnreadConversations(context.Background(), alice.Username)
+	require.NoError(t, err)
+	require.Zero(t, unread)
+}
+
+func TestDirectMessagePermissions(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	picky := CreateRandomUser(t)
+	friend := CreateRandomUser(t)
+	stranger := CreateRandomUser(t)
+
+	_, err := dbt.UpsertDMSettings(context.Background(), UpsertDMSettingsParams{
+		Username:  picky.Username,
+		AllowFrom: DMAllowFollowing,
+	})
+	require.NoError(t, err)
+
+	_, err = dbt.FollowTx(context.Background(), FollowInputArgs{Username: picky.Username, FollowUser: friend.Username})
+	require.NoError(t, err)
+
+	_, err = dbt.SendDirectMessageTx(context.Background(), SendDirectMessageTxParams{
+		Sender: stranger.Username, Recipient: picky.Username, Body: "hey",
+	})
+	require.Equal(t, ErrDirectMessagesClosed, err)
+
+	sendDirectMessage(t, dbt, friend.Username, picky.Username)
+
+	// once picky wrote to the stranger, the stranger can answer
+	sent := sendDirectMessage(t, dbt, picky.Username, stranger.Username)
+	_, err = dbt.SendDirectMessageTx(context.Background(), SendDirectMessageTxParams{
+		Sender: stranger.Username, ConversationID: sent.Message.ConversationID, Body: "hi back",
+	})
+	require.NoError(t, err)
+
+	err = dbt.BlockTx(context.Background(), BlockTxParams{BlockerUsername: stranger.Username, BlockedUsername: picky.Username})
+	require.NoError(t, err)
+
+	_, err = dbt.SendDirectMessageTx(context.Background(), SendDirectMessageTxParams{
+		Sender: picky.Username, Recipient: stranger.Username, Body: "hello?",
+	})
+	require.Equal(t, ErrDirectMessageBlocked, err)
+
+	// only participants can write in a conversation
+	_, err = dbt.SendDirectMessageTx(context.Background(), SendDirectMessageTxParams{
+		Sender: friend.Username, ConversationID: sent.Message.ConversationID, Body: "me too",
+	})
+	require.Error(t, err)
+}
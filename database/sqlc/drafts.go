@@ -0,0 +1,49 @@
+package database
+
+import "context"
+
+type PublishDraftTxParams struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// Validate runs against the locked draft before anything is written,
+	// returning an error aborts the publish and keeps the draft
+	Validate func(draft Drafts) error `json:"-"`
+}
+
+// PublishDraftTx turns a draft into a tweet and deletes the draft, either both happen or neither
+func (dbt *DBTransaction) PublishDraftTx(c context.Context, arg PublishDraftTxParams) (CreateTweetTxResult, error) {
+	var res CreateTweetTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		draft, err := q.GetDraftForUpdate(c, GetDraftForUpdateParams{
+			ID:       arg.ID,
+			Username: arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.Validate != nil {
+			if err = arg.Validate(draft); err != nil {
+				return err
+			}
+		}
+
+		res, err = publishTweet(c, q, CreateTweetTxParams{
+			Username: draft.Username,
+			Tweet:    draft.Tweet,
+			MediaIDs: draft.MediaIds,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.DeleteDraft(c, DeleteDraftParams{
+			ID:       draft.ID,
+			Username: draft.Username,
+		})
+		return err
+	})
+
+	return res, err
+}
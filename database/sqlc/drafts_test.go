@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"math"
+	"errors"
+	"testing"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+func CreateRandomDraft(t *testing.T, user Users, mediaIDs []int64) Drafts {
+	arg := CreateDraftParams{
+		Username: user.Username,
+		Tweet:    util.GetRandomString(20),
+		MediaIds: mediaIDs,
+	}
+
+	draft, err := testQueries.CreateDraft(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, draft.ID)
+	require.Equal(t, arg.Tweet, draft.Tweet)
+
+	return draft
+}
+
+func TestUpdateDraft(t *testing.T) {
+	user := CreateRandomUser(t)
+	draft := CreateRandomDraft(t, user, []int64{})
+
+	updated, err := testQueries.UpdateDraft(context.Background(), UpdateDraftParams{
+		Tweet:    "updated",
+		MediaIds: []int64{},
+		ID:       draft.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "updated", updated.Tweet)
+	require.True(t, updated.UpdatedAt.After(draft.UpdatedAt))
+
+	drafts, err := testQueries.ListDrafts(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, drafts, 1)
+}
+
+func TestPublishDraftTx(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	user := CreateRandomUser(t)
+	media := CreateRandomMedia(t, user)
+	draft := CreateRandomDraft(t, user, []int64{media.ID})
+
+	res, err := dbt.PublishDraftTx(context.Background(), PublishDraftTxParams{
+		ID:       draft.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, draft.Tweet, res.Tweet.Tweet)
+	require.Len(t, res.Media, 1)
+
+	drafts, err := dbt.ListDrafts(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, drafts)
+}
+
+func TestPublishDraftTxRollback(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	user := CreateRandomUser(t)
+	draft := CreateRandomDraft(t, user, []int64{})
+
+	invalid := errors.New("invalid")
+	_, err := dbt.PublishDraftTx(context.Background(), PublishDraftTxParams{
+		ID:       draft.ID,
+		Username: user.Username,
+		Validate: func(Drafts) error { return invalid },
+	})
+	require.ErrorIs(t, err, invalid)
+
+	//someone else's draft is not found
+	_, err = dbt.PublishDraftTx(context.Background(), PublishDraftTxParams{
+		ID:       draft.ID,
+		Username: CreateRandomUser(t).Username,
+	})
+	require.Error(t, err)
+
+	drafts, err := dbt.ListDrafts(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, drafts, 1)
+
+	tweets, err := dbt.ListUserTweets(context.Background(), ListUserTweetsParams{
+		Username: user.Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, tweets)
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotTweetOwner    = errors.New("tweet belongs to another user")
+	ErrEditWindowClosed = errors.New("tweet can no longer be edited")
+)
+
+type EditTweetTxParams struct {
+	ID         int64         `json:"id"`
+	Username   string        `json:"username"`
+	Tweet      string        `json:"tweet"`
+	EditWindow time.Duration `json:"edit_window"`
+}
+
+// EditTweetTx keeps the current text as a revision before replacing it. The tweet row is
+// locked so concurrent edits can't both save the same previous version.
+func (dbt *DBTransaction) EditTweetTx(c context.Context, arg EditTweetTxParams) (Tweets, error) {
+	var res Tweets
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		tweet, err := q.GetTweetForUpdate(c, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if tweet.Username != arg.Username {
+			return ErrNotTweetOwner
+		}
+		if time.Since(tweet.CreatedAt) > arg.EditWindow {
+			return ErrEditWindowClosed
+		}
+
+		//the replaced version was written when the tweet was created or last edited
+		versionCreatedAt := tweet.CreatedAt
+		if tweet.EditedAt.Valid {
+			versionCreatedAt = tweet.EditedAt.Time
+		}
+		_, err = q.CreateTweetRevision(c, CreateTweetRevisionParams{
+			TweetID:   tweet.ID,
+			Tweet:     tweet.Tweet,
+			CreatedAt: versionCreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err = q.UpdateTweet(c, UpdateTweetParams{
+			Tweet: arg.Tweet,
+			ID:    arg.ID,
+		})
+		return err
+	})
+
+	return res, err
+}
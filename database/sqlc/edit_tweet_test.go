@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditTweetTx(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	tweet := CreateTweet(t)
+	require.False(t, tweet.EditedAt.Valid)
+
+	texts := []string{"first edit", "second edit"}
+	for _, text := range texts {
+		edited, err := dbt.EditTweetTx(context.Background(), EditTweetTxParams{
+			ID:         tweet.ID,
+			Username:   tweet.Username,
+			Tweet:      text,
+			EditWindow: time.Hour,
+		})
+		require.NoError(t, err)
+		require.Equal(t, text, edited.Tweet)
+		require.True(t, edited.EditedAt.Valid)
+	}
+
+	revisions, err := dbt.ListTweetRevisions(context.Background(), tweet.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, texts[0], revisions[0].Tweet)
+	require.Equal(t, tweet.Tweet, revisions[1].Tweet)
+	require.WithinDuration(t, tweet.CreatedAt, revisions[1].CreatedAt, time.Second)
+}
+
+func TestEditTweetTxNotOwner(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	tweet := CreateTweet(t)
+	other := CreateRandomUser(t)
+
+	_, err := dbt.EditTweetTx(context.Background(), EditTweetTxParams{
+		ID:         tweet.ID,
+		Username:   other.Username,
+		Tweet:      "not mine",
+		EditWindow: time.Hour,
+	})
+	require.ErrorIs(t, err, ErrNotTweetOwner)
+}
+
+func TestEditTweetTxWindowClosed(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	tweet := CreateTweet(t)
+
+	_, err := dbt.EditTweetTx(context.Background(), EditTweetTxParams{
+		ID:         tweet.ID,
+		Username:   tweet.Username,
+		Tweet:      "too late",
+		EditWindow: 0,
+	})
+	require.ErrorIs(t, err, ErrEditWindowClosed)
+
+	revisions, err := dbt.ListTweetRevisions(context.Background(), tweet.ID)
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+}
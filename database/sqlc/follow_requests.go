@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ApproveFollowRequestTx turns the request of arg.Username to follow the protected
+// arg.FollowUser into a follow. It fails with sql.ErrNoRows when there's no such request.
+func (dbt *DBTransaction) ApproveFollowRequestTx(c context.Context, arg FollowInputArgs) (FollowInputResult, error) {
+	var res FollowInputResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		deleted, err := q.DeleteFollowRequest(c, DeleteFollowRequestParams{
+			RequesterUsername: arg.Username,
+			TargetUsername:    arg.FollowUser,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+
+		res, err = follow(c, q, arg)
+		return err
+	})
+
+	return res, err
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowRequests(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	target := CreateRandomUser(t)
+	requester := CreateRandomUser(t)
+	other := CreateRandomUser(t)
+
+	for _, username := range []string{requester.Username, other.Username} {
+		created, err := dbt.CreateFollowRequest(context.Background(), CreateFollowRequestParams{RequesterUsername: username, TargetUsername: target.Username})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), created)
+	}
+	//asking twice is a no-op
+	created, err := dbt.CreateFollowRequest(context.Background(), CreateFollowRequestParams{RequesterUsername: requester.Username, TargetUsername: target.Username})
+	require.NoError(t, err)
+	require.Zero(t, created)
+
+	requests, err := dbt.ListFollowRequests(context.Background(), ListFollowRequestsParams{Username: target.Username, BeforeID: math.MaxInt64, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	require.Equal(t, other.Username, requests[0].Username)
+	require.Equal(t, requester.Username, requests[1].Username)
+
+	res, err := dbt.ApproveFollowRequestTx(context.Background(), FollowInputArgs{Username: requester.Username, FollowUser: target.Username})
+	require.NoError(t, err)
+	require.Equal(t, target.FollowersCount.Int32+1, res.FollowedFollowerCount)
+	_, err = dbt.GetRelations(context.Background(), GetRelationsParams{FollowerUsername: requester.Username, FollowedUsername: target.Username})
+	require.NoError(t, err)
+
+	//the request is gone once approved
+	_, err = dbt.ApproveFollowRequestTx(context.Background(), FollowInputArgs{Username: requester.Username, FollowUser: target.Username})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	//blocking drops what's pending
+	require.NoError(t, dbt.BlockTx(context.Background(), BlockTxParams{BlockerUsername: target.Username, BlockedUsername: other.Username}))
+	requests, err = dbt.ListFollowRequests(context.Background(), ListFollowRequestsParams{Username: target.Username, BeforeID: math.MaxInt64, PageSize: 10})
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
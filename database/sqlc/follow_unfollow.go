@@ -19,39 +19,84 @@ func (dbt *DBTransaction) FollowTx(c context.Context, arg FollowInputArgs) (Foll
 	var res FollowInputResult
 
 	err := dbt.execTransaction(c, func(q *Queries) error {
-		//create relation
-		createArg := CreateRelationsParams{
-			FollowerUsername: arg.Username,
-			FollowedUsername: arg.FollowUser,
-		}
-		rel, err := q.CreateRelations(c, createArg)
-		if err != nil {
-			return err
-		}
-
-		res.FollowedUser = rel.FollowedUsername
-		res.FollowerUser = rel.FollowerUsername
+		var err error
+		res, err = follow(c, q, arg)
+		return err
+	})
 
-		//increment following
-		ifollowing, err := q.IncrementFollowing(c, arg.Username)
-		if err != nil {
-			return err
-		}
+	return res, err
+}
 
-		res.FollowerFollowingCount = ifollowing.FollowingCount.Int32
+// follow creates the relation with its counters, home timeline backfill, notification,
+// webhooks and event, it runs inside FollowTx and ApproveFollowRequestTx
+func follow(c context.Context, q *Queries, arg FollowInputArgs) (FollowInputResult, error) {
+	var res FollowInputResult
 
-		//increment follower
-		ifollower, err := q.IncrementFollower(c, arg.FollowUser)
-		if err != nil {
-			return err
-		}
+	//create relation
+	createArg := CreateRelationsParams{
+		FollowerUsername: arg.Username,
+		FollowedUsername: arg.FollowUser,
+	}
+	rel, err := q.CreateRelations(c, createArg)
+	if err != nil {
+		return res, err
+	}
+
+	res.FollowedUser = rel.FollowedUsername
+	res.FollowerUser = rel.FollowerUsername
+
+	//increment following
+	ifollowing, err := q.IncrementFollowing(c, arg.Username)
+	if err != nil {
+		return res, err
+	}
+
+	res.FollowerFollowingCount = ifollowing.FollowingCount.Int32
+
+	//increment follower
+	ifollower, err := q.IncrementFollower(c, arg.FollowUser)
+	if err != nil {
+		return res, err
+	}
+
+	res.FollowedFollowerCount = ifollower.FollowersCount.Int32
+
+	//bring the followed user's recent tweets into the home timeline
+	_, err = q.BackfillHomeTimeline(c, BackfillHomeTimelineParams{
+		Username: arg.Username,
+		AuthorUsername: arg.FollowUser,
+		BackfillSize: timelineBackfillSize,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	_, err = notify(c, q, notifyParams{
+		Username: arg.FollowUser,
+		Actor: arg.Username,
+		Type: NotificationFollow,
+	})
+	if err != nil {
+		return res, err
+	}
 
-		res.FollowedFollowerCount = ifollower.FollowersCount.Int32
+	err = enqueueWebhooks(c, q, WebhookFollowCreated, []string{arg.Username, arg.FollowUser}, FollowWebhookData{
+		Follower: arg.Username,
+		Followed: arg.FollowUser,
+	})
+	if err != nil {
+		return res, err
+	}
 
-		return nil
+	err = recordEvent(c, q, EventFollowCreated, followAggregateKey(arg.Username, arg.FollowUser), FollowEventData{
+		Follower: arg.Username,
+		Followed: arg.FollowUser,
 	})
+	if err != nil {
+		return res, err
+	}
 
-	return res, err
+	return res, nil
 }
 
 func (dbt *DBTransaction) UnfollowTx(c context.Context, arg FollowInputArgs) error {
@@ -80,6 +125,23 @@ func (dbt *DBTransaction) UnfollowTx(c context.Context, arg FollowInputArgs) err
 			return err
 		}
 
+		//drop their tweets from the home timeline
+		_, err = q.PruneHomeTimeline(c, PruneHomeTimelineParams{
+			Username: arg.Username,
+			AuthorUsername: arg.FollowUser,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordEvent(c, q, EventFollowDeleted, followAggregateKey(arg.Username, arg.FollowUser), FollowEventData{
+			Follower: arg.Username,
+			Followed: arg.FollowUser,
+		})
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 
@@ -0,0 +1,366 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxGroupParticipants caps the size of group conversations, their creator included
+const MaxGroupParticipants = 50
+
+// Roles within a group conversation
+const (
+	GroupAdmin  = "admin"
+	GroupMember = "member"
+)
+
+var (
+	ErrNotGroupConversation = errors.New("conversation isn't a group")
+	ErrNotGroupAdmin        = errors.New("only group admins can do that")
+	ErrGroupFull            = fmt.Errorf("groups can't have more than %d participants", MaxGroupParticipants)
+	ErrLastGroupAdmin       = errors.New("group needs another admin first")
+	// ErrNoGroupChange is returned when a change leaves the group as it was, nothing
+	// is recorded then
+	ErrNoGroupChange = errors.New("group is already like that")
+)
+
+// GroupChangeTxResult is the system message recording a change and everyone who
+// should hear of it, the users it removed included
+type GroupChangeTxResult struct {
+	Conversation Conversations `json:"conversation"`
+	Message      Messages      `json:"message"`
+	Participants []string      `json:"participants"`
+}
+
+// groupChange runs change on a group the actor is in, with the group locked so
+// changes to it happen one at a time. adminOnly turns members down.
+func groupChange(c context.Context, q *Queries, conversationID int64, actor string, adminOnly bool, change func(conversation *Conversations) (string, error)) (GroupChangeTxResult, error) {
+	var res GroupChangeTxResult
+
+	conversation, err := q.GetConversationForUpdate(c, conversationID)
+	if err != nil {
+		return res, err
+	}
+	participant, err := q.GetConversationParticipant(c, GetConversationParticipantParams{
+		ConversationID: conversationID,
+		Username:       actor,
+	})
+	if err != nil {
+		return res, err
+	}
+	if !conversation.IsGroup {
+		return res, ErrNotGroupConversation
+	}
+	if adminOnly && participant.Role != GroupAdmin {
+		return res, ErrNotGroupAdmin
+	}
+
+	//whoever is in the group before the change hears of it
+	before, err := q.ListConversationParticipants(c, []int64{conversationID})
+	if err != nil {
+		return res, err
+	}
+
+	body, err := change(&conversation)
+	if err != nil {
+		return res, err
+	}
+	res.Conversation = conversation
+
+	res.Message, err = postMessage(c, q, conversationID, actor, body, MessageSystem)
+	if err != nil {
+		return res, err
+	}
+
+	after, err := q.ListConversationParticipants(c, []int64{conversationID})
+	if err != nil {
+		return res, err
+	}
+	seen := map[string]bool{}
+	for _, participant := range append(before, after...) {
+		if !seen[participant.Username] {
+			seen[participant.Username] = true
+			res.Participants = append(res.Participants, participant.Username)
+		}
+	}
+	return res, nil
+}
+
+// addGroupMembers adds the users adder may add, the ones already in are skipped. It
+// returns the users added.
+func addGroupMembers(c context.Context, q *Queries, conversationID int64, adder string, usernames []string) ([]string, error) {
+	var added []string
+	for _, username := range usernames {
+		if username == adder {
+			continue
+		}
+		if err := canAddToGroup(c, q, adder, username); err != nil {
+			return nil, err
+		}
+
+		n, err := q.AddGroupParticipant(c, AddGroupParticipantParams{
+			ConversationID: conversationID,
+			Username:       username,
+			Role:           GroupMember,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			added = append(added, username)
+		}
+	}
+
+	participants, err := q.ListConversationParticipants(c, []int64{conversationID})
+	if err != nil {
+		return nil, err
+	}
+	if len(participants) > MaxGroupParticipants {
+		return nil, ErrGroupFull
+	}
+	return added, nil
+}
+
+// canAddToGroup holds adding people to groups to the rules of messaging them
+func canAddToGroup(c context.Context, q *Queries, adder, username string) error {
+	_, err := q.GetUser(c, username)
+	if err != nil {
+		return err
+	}
+	return canMessage(c, q, adder, username, 0)
+}
+
+type CreateGroupConversationTxParams struct {
+	Creator string   `json:"creator"`
+	Title   string   `json:"title"`
+	Members []string `json:"members"`
+}
+
+// CreateGroupConversationTx starts a group with its creator as admin. It returns
+// sql.ErrNoRows when a member doesn't exist.
+func (dbt *DBTransaction) CreateGroupConversationTx(c context.Context, arg CreateGroupConversationTxParams) (GroupChangeTxResult, error) {
+	var res GroupChangeTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		conversation, err := q.CreateGroupConversation(c, CreateGroupConversationParams{
+			Title:     sql.NullString{String: arg.Title, Valid: true},
+			CreatedBy: sql.NullString{String: arg.Creator, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddGroupParticipant(c, AddGroupParticipantParams{
+			ConversationID: conversation.ID,
+			Username:       arg.Creator,
+			Role:           GroupAdmin,
+		})
+		if err != nil {
+			return err
+		}
+
+		added, err := addGroupMembers(c, q, conversation.ID, arg.Creator, arg.Members)
+		if err != nil {
+			return err
+		}
+
+		body := fmt.Sprintf("%s created the group", arg.Creator)
+		if len(added) > 0 {
+			body += " with " + strings.Join(added, ", ")
+		}
+
+		res.Conversation = conversation
+		res.Message, err = postMessage(c, q, conversation.ID, arg.Creator, body, MessageSystem)
+		if err != nil {
+			return err
+		}
+		res.Participants = append([]string{arg.Creator}, added...)
+		return nil
+	})
+
+	return res, err
+}
+
+type AddGroupMembersTxParams struct {
+	ConversationID int64    `json:"conversation_id"`
+	Actor          string   `json:"actor"`
+	Usernames      []string `json:"usernames"`
+}
+
+// AddGroupMembersTx adds users to a group, admins only. Users already in it are left
+// as they are.
+func (dbt *DBTransaction) AddGroupMembersTx(c context.Context, arg AddGroupMembersTxParams) (GroupChangeTxResult, error) {
+	var res GroupChangeTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res, err = groupChange(c, q, arg.ConversationID, arg.Actor, true, func(conversation *Conversations) (string, error) {
+			added, err := addGroupMembers(c, q, conversation.ID, arg.Actor, arg.Usernames)
+			if err != nil {
+				return "", err
+			}
+			if len(added) == 0 {
+				return "", ErrNoGroupChange
+			}
+			return fmt.Sprintf("%s added %s", arg.Actor, strings.Join(added, ", ")), nil
+		})
+		return err
+	})
+
+	return res, err
+}
+
+type GroupMemberTxParams struct {
+	ConversationID int64  `json:"conversation_id"`
+	Actor          string `json:"actor"`
+	Username       string `json:"username"`
+}
+
+// RemoveGroupMemberTx takes a user out of a group, admins only
+func (dbt *DBTransaction) RemoveGroupMemberTx(c context.Context, arg GroupMemberTxParams) (GroupChangeTxResult, error) {
+	var res GroupChangeTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res, err = groupChange(c, q, arg.ConversationID, arg.Actor, true, func(conversation *Conversations) (string, error) {
+			removed, err := q.RemoveConversationParticipant(c, RemoveConversationParticipantParams{
+				ConversationID: conversation.ID,
+				Username:       arg.Username,
+			})
+			if err != nil {
+				return "", err
+			}
+			if removed == 0 {
+				return "", sql.ErrNoRows
+			}
+			return fmt.Sprintf("%s removed %s", arg.Actor, arg.Username), nil
+		})
+		return err
+	})
+
+	return res, err
+}
+
+// LeaveGroupTx takes the actor out of a group. When the last admin leaves, the
+// longest-standing member takes over.
+func (dbt *DBTransaction) LeaveGroupTx(c context.Context, arg GroupMemberTxParams) (GroupChangeTxResult, error) {
+	var res GroupChangeTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res, err = groupChange(c, q, arg.ConversationID, arg.Actor, false, func(conversation *Conversations) (string, error) {
+			_, err := q.RemoveConversationParticipant(c, RemoveConversationParticipantParams{
+				ConversationID: conversation.ID,
+				Username:       arg.Actor,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			admins, err := q.CountConversationAdmins(c, conversation.ID)
+			if err != nil {
+				return "", err
+			}
+			if admins == 0 {
+				if _, err := q.PromoteOldestParticipant(c, conversation.ID); err != nil {
+					return "", err
+				}
+			}
+			return fmt.Sprintf("%s left", arg.Actor), nil
+		})
+		return err
+	})
+
+	return res, err
+}
+
+type RenameGroupTxParams struct {
+	ConversationID int64  `json:"conversation_id"`
+	Actor          string `json:"actor"`
+	Title          string `json:"title"`
+}
+
+// RenameGroupTx sets the title of a group, admins only
+func (dbt *DBTransaction) RenameGroupTx(c context.Context, arg RenameGroupTxParams) (GroupChangeTxResult, error) {
+	var res GroupChangeTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res, err = groupChange(c, q, arg.ConversationID, arg.Actor, true, func(conversation *Conversations) (string, error) {
+			if conversation.Title.String == arg.Title {
+				return "", ErrNoGroupChange
+			}
+			renamed, err := q.RenameConversation(c, RenameConversationParams{
+				ID:    conversation.ID,
+				Title: sql.NullString{String: arg.Title, Valid: true},
+			})
+			if err != nil {
+				return "", err
+			}
+			*conversation = renamed
+			return fmt.Sprintf("%s renamed the group to %s", arg.Actor, arg.Title), nil
+		})
+		return err
+	})
+
+	return res, err
+}
+
+type SetGroupRoleTxParams struct {
+	ConversationID int64  `json:"conversation_id"`
+	Actor          string `json:"actor"`
+	Username       string `json:"username"`
+	Role           string `json:"role"`
+}
+
+// SetGroupRoleTx makes a member an admin or an admin a member, admins only. A group
+// always keeps an admin.
+func (dbt *DBTransaction) SetGroupRoleTx(c context.Context, arg SetGroupRoleTxParams) (GroupChangeTxResult, error) {
+	var res GroupChangeTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res, err = groupChange(c, q, arg.ConversationID, arg.Actor, true, func(conversation *Conversations) (string, error) {
+			participant, err := q.GetConversationParticipant(c, GetConversationParticipantParams{
+				ConversationID: conversation.ID,
+				Username:       arg.Username,
+			})
+			if err != nil {
+				return "", err
+			}
+			if participant.Role == arg.Role {
+				return "", ErrNoGroupChange
+			}
+
+			if arg.Role != GroupAdmin {
+				admins, err := q.CountConversationAdmins(c, conversation.ID)
+				if err != nil {
+					return "", err
+				}
+				if admins <= 1 {
+					return "", ErrLastGroupAdmin
+				}
+			}
+
+			_, err = q.SetConversationParticipantRole(c, SetConversationParticipantRoleParams{
+				ConversationID: conversation.ID,
+				Username:       arg.Username,
+				Role:           arg.Role,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			if arg.Role == GroupAdmin {
+				return fmt.Sprintf("%s made %s an admin", arg.Actor, arg.Username), nil
+			}
+			return fmt.Sprintf("%s made %s a member", arg.Actor, arg.Username), nil
+		})
+		return err
+	})
+
+	return res, err
+}
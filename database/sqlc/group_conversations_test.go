@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupConversation(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	alice := CreateRandomUser(t)
+	bob := CreateRandomUser(t)
+	carol := CreateRandomUser(t)
+
+	group, err := dbt.CreateGroupConversationTx(context.Background(), CreateGroupConversationTxParams{
+		Creator: alice.Username,
+		Title:   "weekend",
+		Members: []string{bob.Username},
+	})
+	require.NoError(t, err)
+	require.True(t, group.Conversation.IsGroup)
+	require.Equal(t, MessageSystem, group.Message.Kind)
+	require.ElementsMatch(t, []string{alice.Username, bob.Username}, group.Participants)
+	id := group.Conversation.ID
+
+	// only admins manage the group
+	_, err = dbt.AddGroupMembersTx(context.Background(), AddGroupMembersTxParams{
+		ConversationID: id,
+		Actor:          bob.Username,
+		Usernames:      []string{carol.Username},
+	})
+	require.ErrorIs(t, err, ErrNotGroupAdmin)
+
+	added, err := dbt.AddGroupMembersTx(context.Background(), AddGroupMembersTxParams{
+		ConversationID: id,
+		Actor:          alice.Username,
+		Usernames:      []string{carol.Username, bob.Username},
+	})
+	require.NoError(t, err)
+	require.Len(t, added.Participants, 3)
+
+	_, err = dbt.RenameGroupTx(context.Background(), RenameGroupTxParams{ConversationID: id, Actor: alice.Username, Title: "weekend"})
+	require.ErrorIs(t, err, ErrNoGroupChange)
+
+	renamed, err := dbt.RenameGroupTx(context.Background(), RenameGroupTxParams{ConversationID: id, Actor: alice.Username, Title: "trip"})
+	require.NoError(t, err)
+	require.Equal(t, "trip", renamed.Conversation.Title.String)
+
+	_, err = dbt.SetGroupRoleTx(context.Background(), SetGroupRoleTxParams{
+		ConversationID: id,
+		Actor:          alice.Username,
+		Username:       alice.Username,
+		Role:           GroupMember,
+	})
+	require.ErrorIs(t, err, ErrLastGroupAdmin)
+
+	_, err = dbt.RemoveGroupMemberTx(context.Background(), GroupMemberTxParams{ConversationID: id, Actor: alice.Username, Username: carol.Username})
+	require.NoError(t, err)
+
+	// the last admin leaving hands the group over to bob
+	left, err := dbt.LeaveGroupTx(context.Background(), GroupMemberTxParams{ConversationID: id, Actor: alice.Username, Username: alice.Username})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{alice.Username, bob.Username}, left.Participants)
+
+	participant, err := dbt.GetConversationParticipant(context.Background(), GetConversationParticipantParams{ConversationID: id, Username: bob.Username})
+	require.NoError(t, err)
+	require.Equal(t, GroupAdmin, participant.Role)
+
+	// group members can write without the one-to-one checks
+	sent, err := dbt.SendDirectMessageTx(context.Background(), SendDirectMessageTxParams{Sender: bob.Username, ConversationID: id, Body: "still here"})
+	require.NoError(t, err)
+	require.Equal(t, MessageText, sent.Message.Kind)
+}
@@ -0,0 +1,232 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+// drainFanouts runs the fan-out worker's transaction until nothing is pending
+func drainFanouts(t *testing.T, dbt Transaction, celebrityThreshold int32) {
+	for {
+		_, err := dbt.FanoutTweetTx(context.Background(), celebrityThreshold)
+		if err == ErrNoPendingFanout {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestFanoutTweetTx(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	reader := CreateRandomUser(t)
+	author := CreateRandomUser(t)
+	celebrity := CreateRandomUser(t)
+	for _, followed := range []Users{author, celebrity} {
+		_, err := dbt.FollowTx(context.Background(), FollowInputArgs{Username: reader.Username, FollowUser: followed.Username})
+		require.NoError(t, err)
+	}
+	//one more follower puts the celebrity over the threshold
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{Username: CreateRandomUser(t).Username, FollowUser: celebrity.Username})
+	require.NoError(t, err)
+
+	own, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{Username: reader.Username, Tweet: tweets})
+	require.NoError(t, err)
+	fannedOut, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{Username: author.Username, Tweet: tweets})
+	require.NoError(t, err)
+	fannedIn, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{Username: celebrity.Username, Tweet: tweets})
+	require.NoError(t, err)
+
+	read := func() []int64 {
+		timeline, err := dbt.GetMaterializedHomeTimeline(context.Background(), GetMaterializedHomeTimelineParams{
+			Username: reader.Username,
+			BeforeID: math.MaxInt64,
+			PageSize: 10,
+		})
+		require.NoError(t, err)
+		ids := []int64{}
+		for _, tweet := range timeline {
+			ids = append(ids, tweet.ID)
+		}
+		return ids
+	}
+
+	//the author's own tweet doesn't wait for the worker
+	require.Equal(t, []int64{own.Tweet.ID}, read())
+
+	drainFanouts(t, dbt, 2)
+	require.Equal(t, []int64{fannedIn.Tweet.ID, fannedOut.Tweet.ID, own.Tweet.ID}, read())
+
+	//only the regular author's tweet was copied
+	pruned, err := dbt.PruneHomeTimeline(context.Background(), PruneHomeTimelineParams{Username: reader.Username, AuthorUsername: celebrity.Username})
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	require.NoError(t, dbt.UnfollowTx(context.Background(), FollowInputArgs{Username: reader.Username, FollowUser: author.Username}))
+	require.NoError(t, dbt.UnfollowTx(context.Background(), FollowInputArgs{Username: reader.Username, FollowUser: celebrity.Username}))
+	require.Equal(t, []int64{own.Tweet.ID}, read())
+
+	//following again brings the tweets back
+	_, err = dbt.FollowTx(context.Background(), FollowInputArgs{Username: reader.Username, FollowUser: author.Username})
+	require.NoError(t, err)
+	require.Equal(t, []int64{fannedOut.Tweet.ID, own.Tweet.ID}, read())
+}
+
+func TestFanoutTweetTxWaitsForUnfollow(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	reader := CreateRandomUser(t)
+	author := CreateRandomUser(t)
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{Username: reader.Username, FollowUser: author.Username})
+	require.NoError(t, err)
+	_, err = dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{Username: author.Username, Tweet: tweets})
+	require.NoError(t, err)
+
+	copied := func() int {
+		var n int
+		err := testDB.QueryRow("SELECT count(*) FROM home_timelines WHERE username = $1 AND author_username = $2", reader.Username, author.Username).Scan(&n)
+		require.NoError(t, err)
+		return n
+	}
+	backfilled := copied()
+
+	//an unfollow deleted the relation but hasn't pruned nor committed yet
+	tx, err := testDB.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec("DELETE FROM relations WHERE follower_username = $1 AND followed_username = $2", reader.Username, author.Username)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		for {
+			_, err := dbt.FanoutTweetTx(context.Background(), math.MaxInt32)
+			if err != nil {
+				if err == ErrNoPendingFanout {
+					err = nil
+				}
+				done <- err
+				return
+			}
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	_, err = tx.Exec("DELETE FROM home_timelines WHERE username = $1 AND author_username = $2", reader.Username, author.Username)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, <-done)
+
+	//the fan-out saw the relation gone and copied nothing after the prune
+	require.NotZero(t, backfilled)
+	require.Zero(t, copied())
+}
+
+func TestPruneExpiredHomeTimelines(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	reader := CreateRandomUser(t)
+	author := CreateRandomUser(t)
+	_, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{Username: author.Username, Tweet: tweets})
+	require.NoError(t, err)
+	_, err = dbt.FollowTx(context.Background(), FollowInputArgs{Username: reader.Username, FollowUser: author.Username})
+	require.NoError(t, err)
+
+	_, err = testDB.Exec("UPDATE home_timelines SET created_at = now() - interval '2 hours' WHERE username = $1", reader.Username)
+	require.NoError(t, err)
+
+	pruned, err := dbt.PruneExpiredHomeTimelines(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, pruned, int64(1))
+
+	var left int
+	require.NoError(t, testDB.QueryRow("SELECT count(*) FROM home_timelines WHERE username = $1", reader.Username).Scan(&left))
+	require.Zero(t, left)
+}
+
+// seedFollowings creates a user following n others who tweeted twice each,
+// with the home timeline materialized as the fan-out worker would have
+func seedFollowings(tb testing.TB, n int) Users {
+	newUser := func() Users {
+		user, err := testQueries.CreateUser(context.Background(), CreateUserParams{
+			Username:       util.GetRandomString(12),
+			HashedPassword: util.GetRandomString(6),
+			Name:           util.GetRandomString(8),
+			Email:          util.GetRandomEmail(),
+		})
+		require.NoError(tb, err)
+		return user
+	}
+
+	reader := newUser()
+	for i := 0; i < n; i++ {
+		followed := newUser()
+		_, err := testQueries.CreateRelations(context.Background(), CreateRelationsParams{
+			FollowerUsername: reader.Username,
+			FollowedUsername: followed.Username,
+		})
+		require.NoError(tb, err)
+		for j := 0; j < 2; j++ {
+			_, err = testQueries.CreateTweet(context.Background(), CreateTweetParams{Tweet: tweets, Username: followed.Username})
+			require.NoError(tb, err)
+		}
+		_, err = testQueries.BackfillHomeTimeline(context.Background(), BackfillHomeTimelineParams{
+			Username:       reader.Username,
+			AuthorUsername: followed.Username,
+			BackfillSize:   timelineBackfillSize,
+		})
+		require.NoError(tb, err)
+	}
+	return reader
+}
+
+func BenchmarkHomeTimeline(b *testing.B) {
+	for _, followings := range []int{1000, 5000} {
+		reader := seedFollowings(b, followings)
+
+		b.Run(fmt.Sprintf("materialized/%d followings", followings), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := testQueries.GetMaterializedHomeTimeline(context.Background(), GetMaterializedHomeTimelineParams{
+					Username: reader.Username,
+					BeforeID: math.MaxInt64,
+					PageSize: 20,
+				})
+				require.NoError(b, err)
+			}
+		})
+
+		//the per-following fan-in GetHomeTimeline replaced, kept as a baseline
+		b.Run(fmt.Sprintf("fan-in/%d followings", followings), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				relations, err := testQueries.ListFollowing(context.Background(), ListFollowingParams{
+					Username: reader.Username,
+					BeforeID: math.MaxInt64,
+					PageSize: 10000,
+				})
+				require.NoError(b, err)
+
+				var feeds []Tweets
+				for _, relation := range relations {
+					list, err := testQueries.ListUserTweets(context.Background(), ListUserTweetsParams{
+						Username:        relation.Username,
+						IncludeReplies:  true,
+						IncludeRetweets: true,
+						BeforeID:        math.MaxInt64,
+						PageSize:        100,
+					})
+					require.NoError(b, err)
+					feeds = append(feeds, list...)
+				}
+				sort.Slice(feeds, func(i, j int) bool {
+					return feeds[i].ID > feeds[j].ID
+				})
+			}
+		})
+	}
+}
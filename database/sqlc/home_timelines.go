@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrNoPendingFanout = errors.New("no tweet waiting to be fanned out")
+
+// timelineBackfillSize is how many of the followed user's latest tweets a follow
+// copies into the follower's home timeline
+const timelineBackfillSize = 100
+
+// FanoutTweetTx pushes the oldest pending tweet into the home timeline of every follower
+// of its author. Authors with at least celebrityThreshold followers aren't fanned out,
+// their tweets are flagged to be merged in when timelines are read instead. The
+// followers' relations stay share locked until commit, an unfollow waits for the
+// fan-out and then prunes what it copied.
+func (dbt *DBTransaction) FanoutTweetTx(c context.Context, celebrityThreshold int32) (TimelineFanouts, error) {
+	var res TimelineFanouts
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		var err error
+		res, err = q.GetPendingFanoutForUpdate(c)
+		if err != nil {
+			return err
+		}
+
+		author, err := q.GetUser(c, res.AuthorUsername)
+		if err != nil {
+			return err
+		}
+
+		if author.FollowersCount.Int32 >= celebrityThreshold {
+			res.FanIn = true
+			return q.MarkFanoutFanIn(c, res.TweetID)
+		}
+
+		err = q.LockFollowerRelations(c, res.AuthorUsername)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.FanOutTweet(c, FanOutTweetParams{
+			TweetID:        res.TweetID,
+			AuthorUsername: res.AuthorUsername,
+		})
+		if err != nil {
+			return err
+		}
+		return q.DeleteTimelineFanout(c, res.TweetID)
+	})
+
+	if err == sql.ErrNoRows {
+		return res, ErrNoPendingFanout
+	}
+	return res, err
+}
@@ -7,6 +7,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createLikeRelation = `-- name: CreateLikeRelation :one
@@ -69,3 +71,159 @@ func (q *Queries) GetLikeRelation(ctx context.Context, arg GetLikeRelationParams
 	)
 	return i, err
 }
+
+const listLikedTweets = `-- name: ListLikedTweets :many
+(
+  SELECT like_relations.id AS like_id, tweets.id, tweets.tweet, tweets.username, tweets.likes, tweets.created_at, tweets.edited_at, tweets.in_reply_to_id, tweets.retweet_of_id
+  FROM like_relations
+  JOIN tweets ON tweets.id = like_relations.tweet_id
+  WHERE like_relations.username = $1 AND like_relations.id > $2 AND like_relations.id < $3
+  AND NOT $4::boolean
+  ORDER BY like_relations.id DESC
+  LIMIT $5
+)
+UNION ALL
+(
+  SELECT like_relations.id AS like_id, tweets.id, tweets.tweet, tweets.username, tweets.likes, tweets.created_at, tweets.edited_at, tweets.in_reply_to_id, tweets.retweet_of_id
+  FROM like_relations
+  JOIN tweets ON tweets.id = like_relations.tweet_id
+  WHERE like_relations.username = $1 AND like_relations.id > $2 AND like_relations.id < $3
+  AND $4::boolean
+  ORDER BY like_relations.id ASC
+  LIMIT $5
+)
+ORDER BY like_id DESC
+`
+
+type ListLikedTweetsParams struct {
+	Username string `json:"username"`
+	AfterID  int64  `json:"after_id"`
+	BeforeID int64  `json:"before_id"`
+	Reverse  bool   `json:"reverse"`
+	PageSize int32  `json:"page_size"`
+}
+
+type ListLikedTweetsRow struct {
+	LikeID      int64         `json:"like_id"`
+	ID          int64         `json:"id"`
+	Tweet       string        `json:"tweet"`
+	Username    string        `json:"username"`
+	Likes       sql.NullInt32 `json:"likes"`
+	CreatedAt   time.Time     `json:"created_at"`
+	EditedAt    sql.NullTime  `json:"edited_at"`
+	InReplyToID sql.NullInt64 `json:"in_reply_to_id"`
+	RetweetOfID sql.NullInt64 `json:"retweet_of_id"`
+}
+
+func (q *Queries) ListLikedTweets(ctx context.Context, arg ListLikedTweetsParams) ([]ListLikedTweetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLikedTweets,
+		arg.Username,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.Reverse,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLikedTweetsRow{}
+	for rows.Next() {
+		var i ListLikedTweetsRow
+		if err := rows.Scan(
+			&i.LikeID,
+			&i.ID,
+			&i.Tweet,
+			&i.Username,
+			&i.Likes,
+			&i.CreatedAt,
+			&i.EditedAt,
+			&i.InReplyToID,
+			&i.RetweetOfID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTweetLikers = `-- name: ListTweetLikers :many
+(
+  SELECT like_relations.id AS like_id, users.username, users.name, users.followers_count, users.following_count
+  FROM like_relations
+  JOIN users ON users.username = like_relations.username
+  WHERE like_relations.tweet_id = $1 AND like_relations.id > $2 AND like_relations.id < $3
+  AND NOT $4::boolean
+  ORDER BY like_relations.id DESC
+  LIMIT $5
+)
+UNION ALL
+(
+  SELECT like_relations.id AS like_id, users.username, users.name, users.followers_count, users.following_count
+  FROM like_relations
+  JOIN users ON users.username = like_relations.username
+  WHERE like_relations.tweet_id = $1 AND like_relations.id > $2 AND like_relations.id < $3
+  AND $4::boolean
+  ORDER BY like_relations.id ASC
+  LIMIT $5
+)
+ORDER BY like_id DESC
+`
+
+type ListTweetLikersParams struct {
+	TweetID  int64 `json:"tweet_id"`
+	AfterID  int64 `json:"after_id"`
+	BeforeID int64 `json:"before_id"`
+	Reverse  bool  `json:"reverse"`
+	PageSize int32 `json:"page_size"`
+}
+
+type ListTweetLikersRow struct {
+	LikeID         int64         `json:"like_id"`
+	Username       string        `json:"username"`
+	Name           string        `json:"name"`
+	FollowersCount sql.NullInt32 `json:"followers_count"`
+	FollowingCount sql.NullInt32 `json:"following_count"`
+}
+
+func (q *Queries) ListTweetLikers(ctx context.Context, arg ListTweetLikersParams) ([]ListTweetLikersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTweetLikers,
+		arg.TweetID,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.Reverse,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTweetLikersRow{}
+	for rows.Next() {
+		var i ListTweetLikersRow
+		if err := rows.Scan(
+			&i.LikeID,
+			&i.Username,
+			&i.Name,
+			&i.FollowersCount,
+			&i.FollowingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,4 +25,46 @@ func TestCreateLikeRelations(t *testing.T) {
 	require.Equal(t, tweet.ID, likeRelation.TweetID)
 	require.NotZero(t, likeRelation.ID)
 	require.NotZero(t, likeRelation.CreatedAt)
+}
+
+func TestListLikes(t *testing.T) {
+	tweet := CreateTweet(t)
+	likers := []Users{CreateRandomUser(t), CreateRandomUser(t)}
+
+	for _, liker := range likers {
+		_, err := testQueries.CreateLikeRelation(context.Background(), CreateLikeRelationParams{
+			Username: liker.Username,
+			TweetID: tweet.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	//newest like first
+	page, err := testQueries.ListTweetLikers(context.Background(), ListTweetLikersParams{
+		TweetID: tweet.ID,
+		BeforeID: math.MaxInt64,
+		PageSize: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, likers[1].Username, page[0].Username)
+
+	page, err = testQueries.ListTweetLikers(context.Background(), ListTweetLikersParams{
+		TweetID: tweet.ID,
+		BeforeID: page[0].LikeID,
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, likers[0].Username, page[0].Username)
+
+	liked, err := testQueries.ListLikedTweets(context.Background(), ListLikedTweetsParams{
+		Username: likers[0].Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, liked, 1)
+	require.Equal(t, tweet.ID, liked[0].ID)
+	require.Equal(t, tweet.Tweet, liked[0].Tweet)
 }
\ No newline at end of file
@@ -1,23 +1,52 @@
 package database
 
-import "context"
+import (
+	"context"
+	"database/sql"
+)
 
-func (dbt *DBTransaction) LikeTweetTx(c context.Context, arg CreateLikeRelationParams) error {
+// LikeTweetTx returns the liked tweet with its new like count, sql.ErrNoRows when
+// there's no such tweet
+func (dbt *DBTransaction) LikeTweetTx(c context.Context, arg CreateLikeRelationParams) (Tweets, error) {
+	var tweet Tweets
 	err := dbt.execTransaction(c, func(q *Queries) error {
 		_, err := q.CreateLikeRelation(c, arg)
 		if err != nil {
 			return err
 		}
 
-		_, err = q.IncrementLike(c, arg.TweetID)
+		tweet, err = q.IncrementLike(c, arg.TweetID)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		_, err = notify(c, q, notifyParams{
+			Username: tweet.Username,
+			Actor:    arg.Username,
+			Type:     NotificationLike,
+			TweetID:  sql.NullInt64{Int64: tweet.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = enqueueWebhooks(c, q, WebhookLikeCreated, []string{arg.Username, tweet.Username}, LikeWebhookData{
+			Username:    arg.Username,
+			TweetID:     tweet.ID,
+			TweetAuthor: tweet.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(c, q, EventLikeCreated, likeAggregateKey(arg.Username, tweet.ID), LikeEventData{
+			Username:    arg.Username,
+			TweetID:     tweet.ID,
+			TweetAuthor: tweet.Username,
+		})
 	})
 
-	return err
+	return tweet, err
 }
 
 func (dbt *DBTransaction) UnlikeTweetTx(c context.Context, arg DeleteLikeRelationParams) error {
@@ -27,12 +56,16 @@ func (dbt *DBTransaction) UnlikeTweetTx(c context.Context, arg DeleteLikeRelatio
 			return err
 		}
 
-		_, err = q.DecrementLike(c, arg.TweetID)
+		tweet, err := q.DecrementLike(c, arg.TweetID)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		return recordEvent(c, q, EventLikeDeleted, likeAggregateKey(arg.Username, tweet.ID), LikeEventData{
+			Username:    arg.Username,
+			TweetID:     tweet.ID,
+			TweetAuthor: tweet.Username,
+		})
 	})
 	return err
 }
\ No newline at end of file
@@ -13,12 +13,14 @@ func TestLikeTx(t *testing.T) {
 	user := CreateRandomUser(t)
 	tweet := CreateTweet(t)
 
-	err := dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{
+	liked, err := dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{
 		Username: user.Username,
 		TweetID: tweet.ID,
 	})
 
 	require.NoError(t, err)
+	require.Equal(t, tweet.ID, liked.ID)
+	require.Equal(t, tweet.Likes.Int32+1, liked.Likes.Int32)
 
 	rel, err := dbt.GetLikeRelation(context.Background(), GetLikeRelationParams{
 		Username: user.Username,
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+func CreateRandomMedia(t *testing.T, user Users) Media {
+	arg := CreateMediaParams{
+		Username: user.Username,
+		MimeType: "image/png",
+		SizeBytes: 100,
+		Width: 40,
+		Height: 20,
+		StorageKey: util.GetRandomString(10) + ".png",
+		ThumbnailKey: util.GetRandomString(10) + "_thumb.jpg",
+	}
+
+	media, err := testQueries.CreateMedia(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, media.ID)
+	require.Equal(t, arg.Username, media.Username)
+	require.Equal(t, arg.StorageKey, media.StorageKey)
+	require.False(t, media.TweetID.Valid)
+
+	return media
+}
+
+func TestCreateMedia(t *testing.T) {
+	CreateRandomMedia(t, CreateRandomUser(t))
+}
+
+func TestCreateTweetTxWithMedia(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	user := CreateRandomUser(t)
+	media1 := CreateRandomMedia(t, user)
+	media2 := CreateRandomMedia(t, user)
+
+	res, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{
+		Username: user.Username,
+		Tweet: util.GetRandomString(20),
+		MediaIDs: []int64{media2.ID, media1.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Media, 2)
+	require.Equal(t, media2.ID, res.Media[0].ID)
+	require.Equal(t, int32(1), res.Media[1].Position)
+
+	listed, err := dbt.ListMediaByTweetIDs(context.Background(), []int64{res.Tweet.ID})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, media2.ID, listed[0].ID)
+
+	//already attached media can't be reused
+	_, err = dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{
+		Username: user.Username,
+		Tweet: util.GetRandomString(20),
+		MediaIDs: []int64{media1.ID},
+	})
+	require.ErrorIs(t, err, ErrMediaUnavailable)
+}
+
+func TestCreateTweetTxMediaOfOtherUser(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	user := CreateRandomUser(t)
+	media := CreateRandomMedia(t, CreateRandomUser(t))
+
+	_, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{
+		Username: user.Username,
+		Tweet: util.GetRandomString(20),
+		MediaIDs: []int64{media.ID},
+	})
+	require.ErrorIs(t, err, ErrMediaUnavailable)
+
+	//the tweet is rolled back with the failed attach
+	tweets, err := dbt.ListUserTweets(context.Background(), ListUserTweetsParams{
+		Username: user.Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, tweets)
+}
@@ -6,9 +6,92 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type Blocks struct {
+	BlockerUsername string    `json:"blocker_username"`
+	BlockedUsername string    `json:"blocked_username"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type Bookmarks struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	TweetID   int64     `json:"tweet_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ConversationParticipants struct {
+	ConversationID    int64     `json:"conversation_id"`
+	Username          string    `json:"username"`
+	LastReadMessageID int64     `json:"last_read_message_id"`
+	JoinedAt          time.Time `json:"joined_at"`
+	Role              string    `json:"role"`
+}
+
+type Conversations struct {
+	ID            int64          `json:"id"`
+	DirectKey     sql.NullString `json:"direct_key"`
+	LastMessageID int64          `json:"last_message_id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	IsGroup       bool           `json:"is_group"`
+	Title         sql.NullString `json:"title"`
+	CreatedBy     sql.NullString `json:"created_by"`
+}
+
+type Devices struct {
+	ID                    int64     `json:"id"`
+	Username              string    `json:"username"`
+	Name                  string    `json:"name"`
+	IdentityKey           []byte    `json:"identity_key"`
+	SigningKey            []byte    `json:"signing_key"`
+	SignedPrekeyID        int64     `json:"signed_prekey_id"`
+	SignedPrekey          []byte    `json:"signed_prekey"`
+	SignedPrekeySignature []byte    `json:"signed_prekey_signature"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type DmSettings struct {
+	Username  string    `json:"username"`
+	AllowFrom string    `json:"allow_from"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Drafts struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Tweet     string    `json:"tweet"`
+	MediaIds  []int64   `json:"media_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Envelopes struct {
+	ID                int64     `json:"id"`
+	RecipientDeviceID int64     `json:"recipient_device_id"`
+	SenderUsername    string    `json:"sender_username"`
+	SenderDeviceID    int64     `json:"sender_device_id"`
+	Ciphertext        []byte    `json:"ciphertext"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type FollowRequests struct {
+	ID                int64     `json:"id"`
+	RequesterUsername string    `json:"requester_username"`
+	TargetUsername    string    `json:"target_username"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type HomeTimelines struct {
+	Username       string    `json:"username"`
+	TweetID        int64     `json:"tweet_id"`
+	AuthorUsername string    `json:"author_username"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
 type LikeRelations struct {
 	ID        int64     `json:"id"`
 	Username  string    `json:"username"`
@@ -16,6 +99,104 @@ type LikeRelations struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type Media struct {
+	ID           int64         `json:"id"`
+	Username     string        `json:"username"`
+	TweetID      sql.NullInt64 `json:"tweet_id"`
+	Position     int32         `json:"position"`
+	MimeType     string        `json:"mime_type"`
+	SizeBytes    int64         `json:"size_bytes"`
+	Width        int32         `json:"width"`
+	Height       int32         `json:"height"`
+	StorageKey   string        `json:"storage_key"`
+	ThumbnailKey string        `json:"thumbnail_key"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type Messages struct {
+	ID             int64     `json:"id"`
+	ConversationID int64     `json:"conversation_id"`
+	SenderUsername string    `json:"sender_username"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+	Kind           string    `json:"kind"`
+}
+
+type NotificationActors struct {
+	NotificationID int64     `json:"notification_id"`
+	ActorUsername  string    `json:"actor_username"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type NotificationPreferences struct {
+	Username        string        `json:"username"`
+	Likes           bool          `json:"likes"`
+	Follows         bool          `json:"follows"`
+	Mentions        bool          `json:"mentions"`
+	Replies         bool          `json:"replies"`
+	OnlyFollowing   bool          `json:"only_following"`
+	QuietHoursStart sql.NullInt32 `json:"quiet_hours_start"`
+	QuietHoursEnd   sql.NullInt32 `json:"quiet_hours_end"`
+	TimeZone        string        `json:"time_zone"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+type Notifications struct {
+	ID        int64         `json:"id"`
+	Username  string        `json:"username"`
+	Type      string        `json:"type"`
+	GroupKey  string        `json:"group_key"`
+	TweetID   sql.NullInt64 `json:"tweet_id"`
+	Seq       int64         `json:"seq"`
+	ReadAt    sql.NullTime  `json:"read_at"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type OneTimePrekeys struct {
+	DeviceID  int64  `json:"device_id"`
+	KeyID     int64  `json:"key_id"`
+	PublicKey []byte `json:"public_key"`
+}
+
+type Outbox struct {
+	ID           int64           `json:"id"`
+	EventType    string          `json:"event_type"`
+	AggregateKey string          `json:"aggregate_key"`
+	Payload      json.RawMessage `json:"payload"`
+	CreatedAt    time.Time       `json:"created_at"`
+	PublishedAt  sql.NullTime    `json:"published_at"`
+}
+
+type PollOptions struct {
+	ID       int64  `json:"id"`
+	PollID   int64  `json:"poll_id"`
+	Position int32  `json:"position"`
+	Label    string `json:"label"`
+	Votes    int32  `json:"votes"`
+}
+
+type PollVotes struct {
+	PollID    int64     `json:"poll_id"`
+	OptionID  int64     `json:"option_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Polls struct {
+	ID        int64        `json:"id"`
+	TweetID   int64        `json:"tweet_id"`
+	ClosesAt  time.Time    `json:"closes_at"`
+	ClosedAt  sql.NullTime `json:"closed_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type ProcessedEvents struct {
+	Consumer    string    `json:"consumer"`
+	EventID     int64     `json:"event_id"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
 type Relations struct {
 	ID               int64     `json:"id"`
 	FollowerUsername string    `json:"follower_username"`
@@ -23,12 +204,46 @@ type Relations struct {
 	CreatedAt        time.Time `json:"created_at"`
 }
 
+type ScheduledTweets struct {
+	ID        int64        `json:"id"`
+	Username  string       `json:"username"`
+	Tweet     string       `json:"tweet"`
+	MediaIds  []int64      `json:"media_ids"`
+	PublishAt time.Time    `json:"publish_at"`
+	FailedAt  sql.NullTime `json:"failed_at"`
+	Error     string       `json:"error"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type TimelineFanouts struct {
+	TweetID        int64     `json:"tweet_id"`
+	AuthorUsername string    `json:"author_username"`
+	FanIn          bool      `json:"fan_in"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type TweetRevisions struct {
+	ID         int64     `json:"id"`
+	TweetID    int64     `json:"tweet_id"`
+	Tweet      string    `json:"tweet"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type TweetSearch struct {
+	TweetID      int64       `json:"tweet_id"`
+	SearchVector interface{} `json:"search_vector"`
+}
+
 type Tweets struct {
-	ID        int64         `json:"id"`
-	Tweet     string        `json:"tweet"`
-	Username  string        `json:"username"`
-	Likes     sql.NullInt32 `json:"likes"`
-	CreatedAt time.Time     `json:"created_at"`
+	ID          int64         `json:"id"`
+	Tweet       string        `json:"tweet"`
+	Username    string        `json:"username"`
+	Likes       sql.NullInt32 `json:"likes"`
+	CreatedAt   time.Time     `json:"created_at"`
+	EditedAt    sql.NullTime  `json:"edited_at"`
+	InReplyToID sql.NullInt64 `json:"in_reply_to_id"`
+	RetweetOfID sql.NullInt64 `json:"retweet_of_id"`
 }
 
 type Users struct {
@@ -40,4 +255,29 @@ type Users struct {
 	FollowingCount    sql.NullInt32 `json:"following_count"`
 	ChangedPasswordAt time.Time     `json:"changed_password_at"`
 	CreatedAt         time.Time     `json:"created_at"`
+	PinnedTweetID     sql.NullInt64 `json:"pinned_tweet_id"`
+	Protected         bool          `json:"protected"`
+}
+
+type WebhookDeliveries struct {
+	ID            int64           `json:"id"`
+	WebhookID     int64           `json:"webhook_id"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int32           `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     sql.NullString  `json:"last_error"`
+	LastStatus    sql.NullInt32   `json:"last_status"`
+	DeliveredAt   sql.NullTime    `json:"delivered_at"`
+	DeadAt        sql.NullTime    `json:"dead_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type Webhooks struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// DefaultNotificationPreferences are the preferences of users who never set theirs
+func DefaultNotificationPreferences(username string) NotificationPreferences {
+	return NotificationPreferences{
+		Username: username,
+		Likes:    true,
+		Follows:  true,
+		Mentions: true,
+		Replies:  true,
+		TimeZone: "UTC",
+	}
+}
+
+// LoadNotificationPreferences returns the preferences of username, the defaults
+// when they never set theirs
+func LoadNotificationPreferences(c context.Context, q Querier, username string) (NotificationPreferences, error) {
+	prefs, err := q.GetNotificationPreferences(c, username)
+	if err == sql.ErrNoRows {
+		return DefaultNotificationPreferences(username), nil
+	}
+	return prefs, err
+}
+
+// Wants reports whether notifications of a type are turned on
+func (p NotificationPreferences) Wants(notificationType string) bool {
+	switch notificationType {
+	case NotificationLike:
+		return p.Likes
+	case NotificationFollow:
+		return p.Follows
+	case NotificationMention:
+		return p.Mentions
+	case NotificationReply:
+		return p.Replies
+	}
+	return true
+}
+
+// InQuietHours reports whether t falls in the quiet hours, read in the user's time zone
+func (p NotificationPreferences) InQuietHours(t time.Time) bool {
+	if !p.QuietHoursStart.Valid || !p.QuietHoursEnd.Valid {
+		return false
+	}
+	location, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := t.In(location)
+	minute := int32(local.Hour()*60 + local.Minute())
+
+	start, end := p.QuietHoursStart.Int32, p.QuietHoursEnd.Int32
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	//wraps past midnight, like 22:00 to 07:00
+	return minute >= start || minute < end
+}
+
+// AcceptsNotification reports whether the owner of prefs takes a notification of a type
+// from actor. Quiet hours only hold back real time deliveries and are left to callers.
+func AcceptsNotification(c context.Context, q Querier, prefs NotificationPreferences, notificationType, actor string) (bool, error) {
+	if prefs.Username == actor || !prefs.Wants(notificationType) {
+		return false, nil
+	}
+	if !prefs.OnlyFollowing {
+		return true, nil
+	}
+
+	_, err := q.GetRelations(c, GetRelationsParams{
+		FollowerUsername: prefs.Username,
+		FollowedUsername: actor,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
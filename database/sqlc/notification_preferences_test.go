@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPreferencesDefaults(t *testing.T) {
+	user := CreateRandomUser(t)
+
+	prefs, err := LoadNotificationPreferences(context.Background(), testQueries, user.Username)
+	require.NoError(t, err)
+	require.Equal(t, DefaultNotificationPreferences(user.Username), prefs)
+}
+
+func TestTurnedOffNotificationsAreSkipped(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	tweet := CreateTweet(t)
+	liker := CreateRandomUser(t)
+
+	prefs := DefaultNotificationPreferences(tweet.Username)
+	_, err := dbt.UpsertNotificationPreferences(context.Background(), UpsertNotificationPreferencesParams{
+		Username: tweet.Username,
+		Likes:    false,
+		Follows:  true,
+		Mentions: true,
+		Replies:  true,
+		TimeZone: prefs.TimeZone,
+	})
+	require.NoError(t, err)
+
+	_, err = dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{
+		Username: liker.Username,
+		TweetID:  tweet.ID,
+	})
+	require.NoError(t, err)
+
+	require.Empty(t, listAllNotifications(t, dbt, tweet.Username))
+}
+
+func TestOnlyFollowingNotifications(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	user := CreateRandomUser(t)
+	followed := CreateRandomUser(t)
+	stranger := CreateRandomUser(t)
+
+	_, err := dbt.UpsertNotificationPreferences(context.Background(), UpsertNotificationPreferencesParams{
+		Username:      user.Username,
+		Likes:         true,
+		Follows:       true,
+		Mentions:      true,
+		Replies:       true,
+		OnlyFollowing: true,
+		TimeZone:      "UTC",
+	})
+	require.NoError(t, err)
+
+	_, err = dbt.FollowTx(context.Background(), FollowInputArgs{
+		Username:   user.Username,
+		FollowUser: followed.Username,
+	})
+	require.NoError(t, err)
+
+	for _, author := range []Users{followed, stranger} {
+		_, err = dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{
+			Username: author.Username,
+			Tweet:    "hi @" + user.Username,
+		})
+		require.NoError(t, err)
+	}
+
+	notifications := listAllNotifications(t, dbt, user.Username)
+	require.Len(t, notifications, 1)
+
+	actors, err := dbt.ListNotificationActors(context.Background(), ListNotificationActorsParams{
+		NotificationIds: []int64{notifications[0].ID},
+		ActorLimit:      3,
+	})
+	require.NoError(t, err)
+	require.Len(t, actors, 1)
+	require.Equal(t, followed.Username, actors[0].ActorUsername)
+}
+
+func TestInQuietHours(t *testing.T) {
+	minutes := func(hour, minute int) sql.NullInt32 {
+		return sql.NullInt32{Int32: int32(hour*60 + minute), Valid: true}
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2022, 6, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	overnight := NotificationPreferences{QuietHoursStart: minutes(22, 0), QuietHoursEnd: minutes(7, 0), TimeZone: "UTC"}
+	require.True(t, overnight.InQuietHours(at(23, 30)))
+	require.True(t, overnight.InQuietHours(at(6, 59)))
+	require.False(t, overnight.InQuietHours(at(7, 0)))
+	require.False(t, overnight.InQuietHours(at(12, 0)))
+
+	daytime := NotificationPreferences{QuietHoursStart: minutes(9, 0), QuietHoursEnd: minutes(17, 0), TimeZone: "UTC"}
+	require.True(t, daytime.InQuietHours(at(9, 0)))
+	require.False(t, daytime.InQuietHours(at(17, 0)))
+
+	//22:00 in Jakarta is 15:00 UTC
+	jakarta := NotificationPreferences{QuietHoursStart: minutes(22, 0), QuietHoursEnd: minutes(7, 0), TimeZone: "Asia/Jakarta"}
+	require.True(t, jakarta.InQuietHours(at(15, 0)))
+	require.False(t, jakarta.InQuietHours(at(14, 59)))
+
+	require.False(t, DefaultNotificationPreferences("someone").InQuietHours(at(3, 0)))
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+)
+
+// Notification types
+const (
+	NotificationFollow  = "follow"
+	NotificationLike    = "like"
+	NotificationMention = "mention"
+	NotificationReply   = "reply"
+)
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@])@([A-Za-z0-9_]{1,15})`)
+
+// MentionedUsernames returns the usernames mentioned in a tweet, each once
+func MentionedUsernames(tweet string) []string {
+	usernames := []string{}
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(tweet, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			usernames = append(usernames, match[1])
+		}
+	}
+	return usernames
+}
+
+// TweetNotification is a user notified of a tweet, by a reply or a mention
+type TweetNotification struct {
+	Username string `json:"username"`
+	Type     string `json:"type"`
+}
+
+type notifyParams struct {
+	Username string
+	Actor    string
+	Type     string
+	TweetID  sql.NullInt64
+}
+
+// notify adds the actor to the unread notification of the same kind, so follows
+// and the likes on a tweet are grouped until the user reads them. It's skipped, and
+// false returned, when the user's preferences turn it down.
+func notify(c context.Context, q *Queries, arg notifyParams) (bool, error) {
+	prefs, err := LoadNotificationPreferences(c, q, arg.Username)
+	if err != nil {
+		return false, err
+	}
+	accepted, err := AcceptsNotification(c, q, prefs, arg.Type, arg.Actor)
+	if err != nil || !accepted {
+		return false, err
+	}
+
+	groupKey := arg.Type
+	if arg.TweetID.Valid {
+		groupKey += ":" + strconv.FormatInt(arg.TweetID.Int64, 10)
+	}
+	notification, err := q.UpsertNotification(c, UpsertNotificationParams{
+		Username: arg.Username,
+		Type:     arg.Type,
+		GroupKey: groupKey,
+		TweetID:  arg.TweetID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = q.AddNotificationActor(c, AddNotificationActorParams{
+		NotificationID: notification.ID,
+		ActorUsername:  arg.Actor,
+	})
+	return err == nil, err
+}
+
+// notifyTweet notifies the author of the tweet replied to and the users mentioned,
+// except the ones who blocked the author, and returns who was notified
+func notifyTweet(c context.Context, q *Queries, tweet Tweets) ([]TweetNotification, error) {
+	notified := []TweetNotification{}
+
+	if tweet.InReplyToID.Valid {
+		parent, err := q.GetTweet(c, tweet.InReplyToID.Int64)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := notify(c, q, notifyParams{
+			Username: parent.Username,
+			Actor:    tweet.Username,
+			Type:     NotificationReply,
+			TweetID:  tweet.InReplyToID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			notified = append(notified, TweetNotification{Username: parent.Username, Type: NotificationReply})
+		}
+	}
+
+	usernames := MentionedUsernames(tweet.Tweet)
+	if len(usernames) == 0 {
+		return notified, nil
+	}
+	mentioned, err := q.ListMentionableUsernames(c, ListMentionableUsernamesParams{
+		Usernames: usernames,
+		Author:    tweet.Username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, username := range mentioned {
+		ok, err := notify(c, q, notifyParams{
+			Username: username,
+			Actor:    tweet.Username,
+			Type:     NotificationMention,
+			TweetID:  sql.NullInt64{Int64: tweet.ID, Valid: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			notified = append(notified, TweetNotification{Username: username, Type: NotificationMention})
+		}
+	}
+	return notified, nil
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func listAllNotifications(t *testing.T, dbt Transaction, username string) []Notifications {
+	notifications, err := dbt.ListNotifications(context.Background(), ListNotificationsParams{
+		Username: username,
+		BeforeID: math.MaxInt64,
+		PageSize: 100,
+	})
+	require.NoError(t, err)
+	return notifications
+}
+
+func TestLikeNotificationsAreGrouped(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	tweet := CreateTweet(t)
+	first := CreateRandomUser(t)
+	second := CreateRandomUser(t)
+
+	for _, user := range []Users{first, second} {
+		_, err := dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{
+			Username: user.Username,
+			TweetID:  tweet.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	//liking your own tweet doesn't notify
+	_, err := dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{
+		Username: tweet.Username,
+		TweetID:  tweet.ID,
+	})
+	require.NoError(t, err)
+
+	notifications := listAllNotifications(t, dbt, tweet.Username)
+	require.Len(t, notifications, 1)
+	require.Equal(t, NotificationLike, notifications[0].Type)
+	require.Equal(t, tweet.ID, notifications[0].TweetID.Int64)
+	require.False(t, notifications[0].ReadAt.Valid)
+
+	actors, err := dbt.ListNotificationActors(context.Background(), ListNotificationActorsParams{
+		NotificationIds: []int64{notifications[0].ID},
+		ActorLimit:      3,
+	})
+	require.NoError(t, err)
+	require.Len(t, actors, 2)
+	require.Equal(t, second.Username, actors[0].ActorUsername)
+	require.Equal(t, first.Username, actors[1].ActorUsername)
+	require.Equal(t, int64(2), actors[0].Total)
+
+	unread, err := dbt.CountUnreadNotifications(context.Background(), tweet.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unread)
+
+	//once read, the next like starts a new group
+	read, err := dbt.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:       notifications[0].ID,
+		Username: tweet.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, read.ReadAt.Valid)
+
+	third := CreateRandomUser(t)
+	_, err = dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{
+		Username: third.Username,
+		TweetID:  tweet.ID,
+	})
+	require.NoError(t, err)
+
+	notifications = listAllNotifications(t, dbt, tweet.Username)
+	require.Len(t, notifications, 2)
+	require.False(t, notifications[0].ReadAt.Valid)
+	require.Greater(t, notifications[0].Seq, notifications[1].Seq)
+
+	marked, err := dbt.MarkAllNotificationsRead(context.Background(), tweet.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), marked)
+
+	unread, err = dbt.CountUnreadNotifications(context.Background(), tweet.Username)
+	require.NoError(t, err)
+	require.Zero(t, unread)
+}
+
+func TestFollowNotification(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	follower := CreateRandomUser(t)
+	followed := CreateRandomUser(t)
+
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{
+		Username:   follower.Username,
+		FollowUser: followed.Username,
+	})
+	require.NoError(t, err)
+
+	notifications := listAllNotifications(t, dbt, followed.Username)
+	require.Len(t, notifications, 1)
+	require.Equal(t, NotificationFollow, notifications[0].Type)
+	require.False(t, notifications[0].TweetID.Valid)
+
+	//someone else can't read it
+	_, err = dbt.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:       notifications[0].ID,
+		Username: follower.Username,
+	})
+	require.Error(t, err)
+}
+
+func TestMentionNotifications(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	author := CreateRandomUser(t)
+	mentioned := CreateRandomUser(t)
+	blocking := CreateRandomUser(t)
+
+	err := dbt.BlockTx(context.Background(), BlockTxParams{
+		BlockerUsername: blocking.Username,
+		BlockedUsername: author.Username,
+	})
+	require.NoError(t, err)
+
+	created, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{
+		Username: author.Username,
+		Tweet:    "hey @" + mentioned.Username + " @" + blocking.Username + " @" + author.Username + " @nobody_here_",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []TweetNotification{{Username: mentioned.Username, Type: NotificationMention}}, created.Notified)
+
+	notifications := listAllNotifications(t, dbt, mentioned.Username)
+	require.Len(t, notifications, 1)
+	require.Equal(t, NotificationMention, notifications[0].Type)
+	require.Equal(t, created.Tweet.ID, notifications[0].TweetID.Int64)
+
+	require.Empty(t, listAllNotifications(t, dbt, blocking.Username))
+	require.Empty(t, listAllNotifications(t, dbt, author.Username))
+}
+
+func TestMentionedUsernames(t *testing.T) {
+	require.Equal(t, []string{"alice", "bob_2"}, MentionedUsernames("@alice and @bob_2, also @alice again"))
+	require.Equal(t, []string{}, MentionedUsernames("mail me at me@example.com"))
+	require.Equal(t, []string{"carol"}, MentionedUsernames("(@carol)"))
+}
+
+func TestReplyNotification(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	parent := CreateTweet(t)
+	replier := CreateRandomUser(t)
+
+	created, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{
+		Username:    replier.Username,
+		Tweet:       tweets,
+		InReplyToID: sql.NullInt64{Int64: parent.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []TweetNotification{{Username: parent.Username, Type: NotificationReply}}, created.Notified)
+
+	notifications := listAllNotifications(t, dbt, parent.Username)
+	require.Len(t, notifications, 1)
+	require.Equal(t, NotificationReply, notifications[0].Type)
+	require.Equal(t, parent.ID, notifications[0].TweetID.Int64)
+}
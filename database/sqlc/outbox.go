@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Outbox event types
+const (
+	EventFollowCreated = "follow.created"
+	EventFollowDeleted = "follow.deleted"
+	EventLikeCreated   = "like.created"
+	EventLikeDeleted   = "like.deleted"
+)
+
+type FollowEventData struct {
+	Follower string `json:"follower"`
+	Followed string `json:"followed"`
+}
+
+type LikeEventData struct {
+	Username    string `json:"username"`
+	TweetID     int64  `json:"tweet_id"`
+	TweetAuthor string `json:"tweet_author"`
+}
+
+// outboxRelayLockKey is the transaction level advisory lock the relays take turns on
+const outboxRelayLockKey int64 = 0x6f7574626f78
+
+// followAggregateKey and likeAggregateKey keep every event about the same relation
+// in one ordered stream, brokers partition on them
+func followAggregateKey(follower, followed string) string {
+	return fmt.Sprintf("follow:%s:%s", follower, followed)
+}
+
+func likeAggregateKey(username string, tweetID int64) string {
+	return fmt.Sprintf("like:%s:%d", username, tweetID)
+}
+
+// recordEvent writes the event to the outbox in the transaction that caused it, so it
+// is published exactly when the change commits
+func recordEvent(c context.Context, q *Queries, eventType, aggregateKey string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return q.InsertOutboxEvent(c, InsertOutboxEventParams{
+		EventType:    eventType,
+		AggregateKey: aggregateKey,
+		Payload:      payload,
+	})
+}
+
+// RelayOutboxTx hands the oldest unpublished events to publish in id order and marks
+// the published ones. It stops at the first event publish fails on and returns that
+// error along with how many were published before it, the rest wait for the next call.
+// Only one relay publishes at a time, the others return zero right away instead of
+// publishing later batches first. An event is published again if the commit fails,
+// consumers have to be idempotent.
+func (dbt *DBTransaction) RelayOutboxTx(c context.Context, batchSize int32, publish func(Outbox) error) (int, error) {
+	var published []int64
+	var publishErr error
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		locked, err := q.TryLockOutboxRelay(c, outboxRelayLockKey)
+		if err != nil || !locked {
+			return err
+		}
+
+		events, err := q.ListUnpublishedOutboxEvents(c, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if publishErr = publish(event); publishErr != nil {
+				break
+			}
+			published = append(published, event.ID)
+		}
+		if len(published) == 0 {
+			return nil
+		}
+
+		return q.MarkOutboxEventsPublished(c, published)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(published), publishErr
+}
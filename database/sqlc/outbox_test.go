@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// relayAll publishes the whole outbox and returns the events recorded for aggregateKey
+func relayAll(t *testing.T, dbt Transaction, aggregateKey string) []Outbox {
+	var events []Outbox
+	for {
+		n, err := dbt.RelayOutboxTx(context.Background(), 100, func(event Outbox) error {
+			if event.AggregateKey == aggregateKey {
+				events = append(events, event)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		if n == 0 {
+			return events
+		}
+	}
+}
+
+func TestFollowRecordsOutboxEvents(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	follower := CreateRandomUser(t)
+	followed := CreateRandomUser(t)
+	arg := FollowInputArgs{Username: follower.Username, FollowUser: followed.Username}
+
+	_, err := dbt.FollowTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NoError(t, dbt.UnfollowTx(context.Background(), arg))
+
+	events := relayAll(t, dbt, followAggregateKey(follower.Username, followed.Username))
+	require.Len(t, events, 2)
+	require.Equal(t, EventFollowCreated, events[0].EventType)
+	require.Equal(t, EventFollowDeleted, events[1].EventType)
+	require.Less(t, events[0].ID, events[1].ID)
+
+	var data FollowEventData
+	require.NoError(t, json.Unmarshal(events[1].Payload, &data))
+	require.Equal(t, FollowEventData{Follower: follower.Username, Followed: followed.Username}, data)
+
+	// published events aren't handed out again
+	require.Empty(t, relayAll(t, dbt, followAggregateKey(follower.Username, followed.Username)))
+}
+
+func TestRelayOutboxStopsAtFailure(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	tweet := CreateTweet(t)
+	liker := CreateRandomUser(t)
+	key := likeAggregateKey(liker.Username, tweet.ID)
+
+	_, err := dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{Username: liker.Username, TweetID: tweet.ID})
+	require.NoError(t, err)
+	err = dbt.UnlikeTweetTx(context.Background(), DeleteLikeRelationParams{Username: liker.Username, TweetID: tweet.ID})
+	require.NoError(t, err)
+
+	// the unlike fails to publish, the like stays published and the unlike comes back
+	errDown := errors.New("broker down")
+	for {
+		n, err := dbt.RelayOutboxTx(context.Background(), 100, func(event Outbox) error {
+			if event.AggregateKey == key && event.EventType == EventLikeDeleted {
+				return errDown
+			}
+			return nil
+		})
+		if err == errDown {
+			break
+		}
+		require.NoError(t, err)
+		require.NotZero(t, n)
+	}
+
+	events := relayAll(t, dbt, key)
+	require.Len(t, events, 1)
+	require.Equal(t, EventLikeDeleted, events[0].EventType)
+}
+
+func TestRelayOutboxOneRelayAtATime(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	follower := CreateRandomUser(t)
+	followed := CreateRandomUser(t)
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{Username: follower.Username, FollowUser: followed.Username})
+	require.NoError(t, err)
+
+	// another relay holds the lock, this one leaves the events to it
+	tx, err := testDB.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock($1)", outboxRelayLockKey)
+	require.NoError(t, err)
+
+	n, err := dbt.RelayOutboxTx(context.Background(), 100, func(event Outbox) error {
+		t.Fatalf("relayed %v while another relay holds the lock", event.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	require.NoError(t, tx.Rollback())
+	require.Len(t, relayAll(t, dbt, followAggregateKey(follower.Username, followed.Username)), 1)
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedTweetClearedOnDelete(t *testing.T) {
+	tweet := CreateTweet(t)
+
+	user, err := testQueries.PinTweet(context.Background(), PinTweetParams{
+		PinnedTweetID: sql.NullInt64{Int64: tweet.ID, Valid: true},
+		Username:      tweet.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, tweet.ID, user.PinnedTweetID.Int64)
+
+	require.NoError(t, testQueries.DeleteTweet(context.Background(), tweet.ID))
+
+	user, err = testQueries.GetUser(context.Background(), tweet.Username)
+	require.NoError(t, err)
+	require.False(t, user.PinnedTweetID.Valid)
+}
+
+func TestUnpinTweet(t *testing.T) {
+	tweet := CreateTweet(t)
+	pinned := sql.NullInt64{Int64: tweet.ID, Valid: true}
+
+	_, err := testQueries.PinTweet(context.Background(), PinTweetParams{
+		PinnedTweetID: pinned,
+		Username:      tweet.Username,
+	})
+	require.NoError(t, err)
+
+	//only the pinned tweet can be unpinned
+	unpinned, err := testQueries.UnpinTweet(context.Background(), UnpinTweetParams{
+		Username:      tweet.Username,
+		PinnedTweetID: sql.NullInt64{Int64: tweet.ID + 1, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, unpinned)
+
+	unpinned, err = testQueries.UnpinTweet(context.Background(), UnpinTweetParams{
+		Username:      tweet.Username,
+		PinnedTweetID: pinned,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unpinned)
+}
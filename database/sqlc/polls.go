@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrPollClosed        = errors.New("poll is closed")
+	ErrAlreadyVoted      = errors.New("already voted on this poll")
+	ErrInvalidPollOption = errors.New("option doesn't belong to this poll")
+	ErrNoDuePoll         = errors.New("no poll is due to close")
+)
+
+type CreatePollTxParams struct {
+	Options  []string      `json:"options"`
+	Duration time.Duration `json:"duration"`
+}
+
+type TweetPoll struct {
+	Poll    Polls         `json:"poll"`
+	Options []PollOptions `json:"options"`
+}
+
+type VotePollTxParams struct {
+	PollID   int64  `json:"poll_id"`
+	OptionID int64  `json:"option_id"`
+	Username string `json:"username"`
+}
+
+// IsClosed reports whether votes are no longer accepted, the closing job may not have finalized it yet
+func (p Polls) IsClosed(now time.Time) bool {
+	return p.ClosedAt.Valid || !p.ClosesAt.After(now)
+}
+
+func attachPoll(c context.Context, q *Queries, tweetID int64, arg CreatePollTxParams) (TweetPoll, error) {
+	var res TweetPoll
+
+	poll, err := q.CreatePoll(c, CreatePollParams{
+		TweetID:  tweetID,
+		ClosesAt: time.Now().Add(arg.Duration),
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Poll = poll
+
+	res.Options = []PollOptions{}
+	for i, label := range arg.Options {
+		option, err := q.CreatePollOption(c, CreatePollOptionParams{
+			PollID:   poll.ID,
+			Position: int32(i),
+			Label:    label,
+		})
+		if err != nil {
+			return res, err
+		}
+		res.Options = append(res.Options, option)
+	}
+
+	return res, nil
+}
+
+// VotePollTx records the user's vote and bumps the live tally. The poll row is share locked
+// so a vote can't slip in while the closing job finalizes it, and the primary key on
+// (poll_id, username) is what makes it one vote per user.
+func (dbt *DBTransaction) VotePollTx(c context.Context, arg VotePollTxParams) (TweetPoll, error) {
+	var res TweetPoll
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		poll, err := q.GetPollForVote(c, arg.PollID)
+		if err != nil {
+			return err
+		}
+		if poll.IsClosed(time.Now()) {
+			return ErrPollClosed
+		}
+		res.Poll = poll
+
+		_, err = q.IncrementPollOptionVotes(c, IncrementPollOptionVotesParams{
+			ID:     arg.OptionID,
+			PollID: poll.ID,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrInvalidPollOption
+			}
+			return err
+		}
+
+		voted, err := q.CreatePollVote(c, CreatePollVoteParams{
+			PollID:   poll.ID,
+			OptionID: arg.OptionID,
+			Username: arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+		if voted == 0 {
+			return ErrAlreadyVoted
+		}
+
+		res.Options, err = q.ListPollOptions(c, poll.ID)
+		return err
+	})
+
+	return res, err
+}
+
+// ClosePollTx finalizes the oldest poll past its closing time, recounting the tallies from
+// the votes themselves. Like the scheduler it skips locked rows so replicas don't collide.
+func (dbt *DBTransaction) ClosePollTx(c context.Context) (TweetPoll, error) {
+	var res TweetPoll
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		poll, err := q.GetDuePollForUpdate(c)
+		if err != nil {
+			return err
+		}
+
+		if err = q.FinalizePollOptions(c, poll.ID); err != nil {
+			return err
+		}
+
+		res.Poll, err = q.ClosePoll(c, poll.ID)
+		if err != nil {
+			return err
+		}
+
+		res.Options, err = q.ListPollOptions(c, poll.ID)
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		return res, ErrNoDuePoll
+	}
+	return res, err
+}
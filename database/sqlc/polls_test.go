@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+func CreateRandomPoll(t *testing.T, user Users, duration time.Duration) TweetPoll {
+	dbt := NewTransaction(testDB)
+
+	res, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{
+		Username: user.Username,
+		Tweet:    util.GetRandomString(20),
+		Poll: &CreatePollTxParams{
+			Options:  []string{"yes", "no", "maybe"},
+			Duration: duration,
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, res.Poll)
+	require.Equal(t, res.Tweet.ID, res.Poll.Poll.TweetID)
+	require.Len(t, res.Poll.Options, 3)
+
+	return *res.Poll
+}
+
+func TestVotePollTx(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	poll := CreateRandomPoll(t, CreateRandomUser(t), time.Hour)
+	voter := CreateRandomUser(t)
+
+	arg := VotePollTxParams{
+		PollID:   poll.Poll.ID,
+		OptionID: poll.Options[1].ID,
+		Username: voter.Username,
+	}
+	res, err := dbt.VotePollTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), res.Options[1].Votes)
+
+	//second vote is rejected and the tally stays the same
+	arg.OptionID = poll.Options[0].ID
+	_, err = dbt.VotePollTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrAlreadyVoted)
+
+	options, err := dbt.ListPollOptions(context.Background(), poll.Poll.ID)
+	require.NoError(t, err)
+	require.Zero(t, options[0].Votes)
+	require.Equal(t, int32(1), options[1].Votes)
+
+	//option of another poll
+	other := CreateRandomPoll(t, CreateRandomUser(t), time.Hour)
+	_, err = dbt.VotePollTx(context.Background(), VotePollTxParams{
+		PollID:   poll.Poll.ID,
+		OptionID: other.Options[0].ID,
+		Username: CreateRandomUser(t).Username,
+	})
+	require.ErrorIs(t, err, ErrInvalidPollOption)
+
+	_, err = dbt.VotePollTx(context.Background(), VotePollTxParams{
+		PollID:   -1,
+		OptionID: poll.Options[0].ID,
+		Username: voter.Username,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestClosePollTx(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	poll := CreateRandomPoll(t, CreateRandomUser(t), time.Millisecond)
+
+	_, err := dbt.VotePollTx(context.Background(), VotePollTxParams{
+		PollID:   poll.Poll.ID,
+		OptionID: poll.Options[0].ID,
+		Username: CreateRandomUser(t).Username,
+	})
+	require.ErrorIs(t, err, ErrPollClosed)
+
+	//close every due poll, ours included
+	for {
+		_, err = dbt.ClosePollTx(context.Background())
+		if err == ErrNoDuePoll {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	polls, err := dbt.ListPollsByTweetIDs(context.Background(), []int64{poll.Poll.TweetID})
+	require.NoError(t, err)
+	require.Len(t, polls, 1)
+	require.True(t, polls[0].ClosedAt.Valid)
+}
@@ -6,32 +6,159 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddConversationParticipant(ctx context.Context, arg AddConversationParticipantParams) error
+	AddGroupParticipant(ctx context.Context, arg AddGroupParticipantParams) (int64, error)
+	AddNotificationActor(ctx context.Context, arg AddNotificationActorParams) error
+	AddOneTimePrekey(ctx context.Context, arg AddOneTimePrekeyParams) (int64, error)
+	AddToHomeTimeline(ctx context.Context, arg AddToHomeTimelineParams) error
+	AttachMedia(ctx context.Context, arg AttachMediaParams) (Media, error)
+	BackfillHomeTimeline(ctx context.Context, arg BackfillHomeTimelineParams) (int64, error)
+	CancelScheduledTweet(ctx context.Context, arg CancelScheduledTweetParams) (int64, error)
+	ClaimOneTimePrekey(ctx context.Context, deviceID int64) (OneTimePrekeys, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
+	ClosePoll(ctx context.Context, id int64) (Polls, error)
+	CountConversationAdmins(ctx context.Context, conversationID int64) (int64, error)
+	CountDevices(ctx context.Context, username string) (int64, error)
+	CountOneTimePrekeys(ctx context.Context, deviceIds []int64) ([]CountOneTimePrekeysRow, error)
+	CountUnreadConversations(ctx context.Context, username string) (int64, error)
+	CountUnreadNotifications(ctx context.Context, username string) (int64, error)
+	CreateBlock(ctx context.Context, arg CreateBlockParams) (int64, error)
+	CreateBookmark(ctx context.Context, arg CreateBookmarkParams) (int64, error)
+	CreateDevice(ctx context.Context, arg CreateDeviceParams) (Devices, error)
+	CreateDraft(ctx context.Context, arg CreateDraftParams) (Drafts, error)
+	CreateEnvelope(ctx context.Context, arg CreateEnvelopeParams) (Envelopes, error)
+	CreateFollowRequest(ctx context.Context, arg CreateFollowRequestParams) (int64, error)
+	CreateGroupConversation(ctx context.Context, arg CreateGroupConversationParams) (Conversations, error)
 	CreateLikeRelation(ctx context.Context, arg CreateLikeRelationParams) (LikeRelations, error)
+	CreateMedia(ctx context.Context, arg CreateMediaParams) (Media, error)
+	CreateMessage(ctx context.Context, arg CreateMessageParams) (Messages, error)
+	CreatePoll(ctx context.Context, arg CreatePollParams) (Polls, error)
+	CreatePollOption(ctx context.Context, arg CreatePollOptionParams) (PollOptions, error)
+	CreatePollVote(ctx context.Context, arg CreatePollVoteParams) (int64, error)
 	CreateRelations(ctx context.Context, arg CreateRelationsParams) (Relations, error)
+	CreateScheduledTweet(ctx context.Context, arg CreateScheduledTweetParams) (ScheduledTweets, error)
+	CreateTimelineFanout(ctx context.Context, arg CreateTimelineFanoutParams) error
 	CreateTweet(ctx context.Context, arg CreateTweetParams) (Tweets, error)
+	CreateTweetRevision(ctx context.Context, arg CreateTweetRevisionParams) (TweetRevisions, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (Users, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhooks, error)
 	DecrementFollower(ctx context.Context, username string) (Users, error)
 	DecrementFollowing(ctx context.Context, username string) (Users, error)
 	DecrementLike(ctx context.Context, id int64) (Tweets, error)
+	DeleteBlock(ctx context.Context, arg DeleteBlockParams) (int64, error)
+	DeleteBookmark(ctx context.Context, arg DeleteBookmarkParams) (int64, error)
+	DeleteDevice(ctx context.Context, arg DeleteDeviceParams) (int64, error)
+	DeleteDraft(ctx context.Context, arg DeleteDraftParams) (int64, error)
+	DeleteEnvelopes(ctx context.Context, arg DeleteEnvelopesParams) (int64, error)
+	DeleteFollowRequest(ctx context.Context, arg DeleteFollowRequestParams) (int64, error)
 	DeleteLikeRelation(ctx context.Context, arg DeleteLikeRelationParams) error
 	DeleteRelation(ctx context.Context, arg DeleteRelationParams) error
+	DeleteScheduledTweet(ctx context.Context, id int64) error
+	DeleteTimelineFanout(ctx context.Context, tweetID int64) error
 	DeleteTweet(ctx context.Context, id int64) error
-	GetFollower(ctx context.Context, arg GetFollowerParams) ([]Relations, error)
-	GetFollowing(ctx context.Context, arg GetFollowingParams) ([]Relations, error)
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error)
+	EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error)
+	FailWebhookDelivery(ctx context.Context, arg FailWebhookDeliveryParams) error
+	FanOutTweet(ctx context.Context, arg FanOutTweetParams) (int64, error)
+	FinalizePollOptions(ctx context.Context, pollID int64) error
+	GetConversationForUpdate(ctx context.Context, id int64) (Conversations, error)
+	GetConversationParticipant(ctx context.Context, arg GetConversationParticipantParams) (ConversationParticipants, error)
+	GetDMSettings(ctx context.Context, username string) (DmSettings, error)
+	GetDevice(ctx context.Context, id int64) (Devices, error)
+	GetDraftForUpdate(ctx context.Context, arg GetDraftForUpdateParams) (Drafts, error)
+	GetDuePollForUpdate(ctx context.Context) (Polls, error)
+	GetDueScheduledTweetForUpdate(ctx context.Context) (ScheduledTweets, error)
 	GetLikeRelation(ctx context.Context, arg GetLikeRelationParams) (LikeRelations, error)
-	GetListTweets(ctx context.Context, arg GetListTweetsParams) ([]Tweets, error)
+	GetMaterializedHomeTimeline(ctx context.Context, arg GetMaterializedHomeTimelineParams) ([]Tweets, error)
+	GetMedia(ctx context.Context, id int64) (Media, error)
+	GetNotificationPreferences(ctx context.Context, username string) (NotificationPreferences, error)
+	GetPendingFanoutForUpdate(ctx context.Context) (TimelineFanouts, error)
+	GetPoll(ctx context.Context, id int64) (Polls, error)
+	GetPollForVote(ctx context.Context, id int64) (Polls, error)
 	GetRelations(ctx context.Context, arg GetRelationsParams) (Relations, error)
 	GetTweet(ctx context.Context, id int64) (Tweets, error)
+	GetTweetForUpdate(ctx context.Context, id int64) (Tweets, error)
 	GetUser(ctx context.Context, username string) (Users, error)
+	GetUserForUpdate(ctx context.Context, username string) (Users, error)
+	GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhooks, error)
+	HasProcessedEvent(ctx context.Context, arg HasProcessedEventParams) (bool, error)
+	HasSentMessage(ctx context.Context, arg HasSentMessageParams) (bool, error)
 	IncrementFollower(ctx context.Context, username string) (Users, error)
 	IncrementFollowing(ctx context.Context, username string) (Users, error)
 	IncrementLike(ctx context.Context, id int64) (Tweets, error)
+	IncrementPollOptionVotes(ctx context.Context, arg IncrementPollOptionVotesParams) (PollOptions, error)
+	InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error
+	IsBlocked(ctx context.Context, arg IsBlockedParams) (bool, error)
+	ListAuthorAffinities(ctx context.Context, arg ListAuthorAffinitiesParams) ([]ListAuthorAffinitiesRow, error)
+	ListBookmarkedTweetIDs(ctx context.Context, arg ListBookmarkedTweetIDsParams) ([]int64, error)
+	ListBookmarks(ctx context.Context, arg ListBookmarksParams) ([]ListBookmarksRow, error)
+	ListConversationParticipants(ctx context.Context, conversationIds []int64) ([]ConversationParticipants, error)
+	ListConversations(ctx context.Context, arg ListConversationsParams) ([]ListConversationsRow, error)
+	ListDeadWebhookDeliveries(ctx context.Context, arg ListDeadWebhookDeliveriesParams) ([]WebhookDeliveries, error)
+	ListDevices(ctx context.Context, username string) ([]Devices, error)
+	ListDrafts(ctx context.Context, username string) ([]Drafts, error)
+	ListFollowRequests(ctx context.Context, arg ListFollowRequestsParams) ([]ListFollowRequestsRow, error)
+	ListFollowers(ctx context.Context, arg ListFollowersParams) ([]ListFollowersRow, error)
+	ListFollowing(ctx context.Context, arg ListFollowingParams) ([]ListFollowingRow, error)
+	ListLikedTweets(ctx context.Context, arg ListLikedTweetsParams) ([]ListLikedTweetsRow, error)
+	ListMediaByTweetIDs(ctx context.Context, tweetIds []int64) ([]Media, error)
+	ListMentionableUsernames(ctx context.Context, arg ListMentionableUsernamesParams) ([]string, error)
+	ListMessages(ctx context.Context, arg ListMessagesParams) ([]Messages, error)
+	ListNotificationActors(ctx context.Context, arg ListNotificationActorsParams) ([]ListNotificationActorsRow, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notifications, error)
+	ListPendingEnvelopes(ctx context.Context, arg ListPendingEnvelopesParams) ([]Envelopes, error)
+	ListPollOptions(ctx context.Context, pollID int64) ([]PollOptions, error)
+	ListPollOptionsByPollIDs(ctx context.Context, pollIds []int64) ([]PollOptions, error)
+	ListPollVotesByUser(ctx context.Context, arg ListPollVotesByUserParams) ([]PollVotes, error)
+	ListPollsByTweetIDs(ctx context.Context, tweetIds []int64) ([]Polls, error)
+	ListRankingCandidates(ctx context.Context, arg ListRankingCandidatesParams) ([]ListRankingCandidatesRow, error)
+	ListScheduledTweets(ctx context.Context, username string) ([]ScheduledTweets, error)
+	ListTweetLikers(ctx context.Context, arg ListTweetLikersParams) ([]ListTweetLikersRow, error)
+	ListTweetRevisions(ctx context.Context, tweetID int64) ([]TweetRevisions, error)
+	ListUnpublishedOutboxEvents(ctx context.Context, limit int32) ([]Outbox, error)
+	ListUserTweets(ctx context.Context, arg ListUserTweetsParams) ([]Tweets, error)
+	ListWebhooks(ctx context.Context, username string) ([]Webhooks, error)
+	LockFollowerRelations(ctx context.Context, followedUsername string) error
+	MarkAllNotificationsRead(ctx context.Context, username string) (int64, error)
+	MarkConversationRead(ctx context.Context, arg MarkConversationReadParams) (ConversationParticipants, error)
+	MarkEventProcessed(ctx context.Context, arg MarkEventProcessedParams) error
+	MarkFanoutFanIn(ctx context.Context, tweetID int64) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notifications, error)
+	MarkOutboxEventsPublished(ctx context.Context, ids []int64) error
+	MarkScheduledTweetFailed(ctx context.Context, arg MarkScheduledTweetFailedParams) (ScheduledTweets, error)
+	MarkWebhookDelivered(ctx context.Context, arg MarkWebhookDeliveredParams) error
+	PinTweet(ctx context.Context, arg PinTweetParams) (Users, error)
+	PromoteOldestParticipant(ctx context.Context, conversationID int64) (int64, error)
+	PruneExpiredFanIns(ctx context.Context, createdAt time.Time) (int64, error)
+	PruneExpiredHomeTimelines(ctx context.Context, createdAt time.Time) (int64, error)
+	PruneHomeTimeline(ctx context.Context, arg PruneHomeTimelineParams) (int64, error)
+	PrunePublishedOutboxEvents(ctx context.Context, publishedAt time.Time) (int64, error)
+	RemoveConversationParticipant(ctx context.Context, arg RemoveConversationParticipantParams) (int64, error)
+	RenameConversation(ctx context.Context, arg RenameConversationParams) (Conversations, error)
+	RescheduleTweet(ctx context.Context, arg RescheduleTweetParams) (ScheduledTweets, error)
+	RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) (int64, error)
+	SearchTweets(ctx context.Context, arg SearchTweetsParams) ([]SearchTweetsRow, error)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error)
+	SetConversationLastMessage(ctx context.Context, arg SetConversationLastMessageParams) error
+	SetConversationParticipantRole(ctx context.Context, arg SetConversationParticipantRoleParams) (int64, error)
+	SetProtected(ctx context.Context, arg SetProtectedParams) (Users, error)
+	TryLockOutboxRelay(ctx context.Context, lockKey int64) (bool, error)
+	UnpinTweet(ctx context.Context, arg UnpinTweetParams) (int64, error)
+	UpdateDraft(ctx context.Context, arg UpdateDraftParams) (Drafts, error)
 	UpdateEmail(ctx context.Context, arg UpdateEmailParams) (Users, error)
 	UpdateName(ctx context.Context, arg UpdateNameParams) (Users, error)
 	UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (Users, error)
+	UpdateSignedPrekey(ctx context.Context, arg UpdateSignedPrekeyParams) (Devices, error)
+	UpdateTweet(ctx context.Context, arg UpdateTweetParams) (Tweets, error)
+	UpsertDMSettings(ctx context.Context, arg UpsertDMSettingsParams) (DmSettings, error)
+	UpsertDirectConversation(ctx context.Context, directKey sql.NullString) (Conversations, error)
+	UpsertNotification(ctx context.Context, arg UpsertNotificationParams) (Notifications, error)
+	UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreferences, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankingSignals(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	reader := CreateRandomUser(t)
+	author := CreateRandomUser(t)
+	other := CreateRandomUser(t)
+
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{Username: reader.Username, FollowUser: author.Username})
+	require.NoError(t, err)
+	posted, err := dbt.CreateTweetTx(context.Background(), CreateTweetTxParams{Username: author.Username, Tweet: tweets})
+	require.NoError(t, err)
+	drainFanouts(t, dbt, 1<<30)
+
+	//the reader likes and replies, someone else retweets
+	_, err = dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{Username: reader.Username, TweetID: posted.Tweet.ID})
+	require.NoError(t, err)
+	_, err = testQueries.CreateTweet(context.Background(), CreateTweetParams{
+		Tweet:       tweets,
+		Username:    reader.Username,
+		InReplyToID: sql.NullInt64{Int64: posted.Tweet.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = testQueries.CreateTweet(context.Background(), CreateTweetParams{
+		Tweet:       tweets,
+		Username:    other.Username,
+		RetweetOfID: sql.NullInt64{Int64: posted.Tweet.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	since := time.Now().Add(-time.Hour)
+	candidates, err := dbt.ListRankingCandidates(context.Background(), ListRankingCandidatesParams{
+		Username:       reader.Username,
+		CandidateLimit: 10,
+		Since:          since,
+	})
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	require.Equal(t, posted.Tweet.ID, candidates[0].ID)
+	require.Equal(t, int32(1), candidates[0].Likes.Int32)
+	require.Equal(t, int64(1), candidates[0].ReplyCount)
+	require.Equal(t, int64(1), candidates[0].RetweetCount)
+
+	//tweets older than the window aren't candidates
+	candidates, err = dbt.ListRankingCandidates(context.Background(), ListRankingCandidatesParams{
+		Username:       reader.Username,
+		CandidateLimit: 10,
+		Since:          time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	affinities, err := dbt.ListAuthorAffinities(context.Background(), ListAuthorAffinitiesParams{
+		Username: reader.Username,
+		Since:    since,
+		Authors:  []string{author.Username, other.Username},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ListAuthorAffinitiesRow{{AuthorUsername: author.Username, Interactions: 2}}, affinities)
+}
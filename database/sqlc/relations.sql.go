@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createRelations = `-- name: CreateRelations :one
@@ -48,33 +49,89 @@ func (q *Queries) DeleteRelation(ctx context.Context, arg DeleteRelationParams)
 	return err
 }
 
-const getFollower = `-- name: GetFollower :many
+const getRelations = `-- name: GetRelations :one
 SELECT id, follower_username, followed_username, created_at FROM relations
-WHERE followed_username = $1
-ORDER BY id DESC
-LIMIT $2 OFFSET $3
+WHERE follower_username = $1 AND followed_username = $2
+LIMIT 1
 `
 
-type GetFollowerParams struct {
+type GetRelationsParams struct {
+	FollowerUsername string `json:"follower_username"`
 	FollowedUsername string `json:"followed_username"`
-	Limit            int32  `json:"limit"`
-	Offset           int32  `json:"offset"`
 }
 
-func (q *Queries) GetFollower(ctx context.Context, arg GetFollowerParams) ([]Relations, error) {
-	rows, err := q.db.QueryContext(ctx, getFollower, arg.FollowedUsername, arg.Limit, arg.Offset)
+func (q *Queries) GetRelations(ctx context.Context, arg GetRelationsParams) (Relations, error) {
+	row := q.db.QueryRowContext(ctx, getRelations, arg.FollowerUsername, arg.FollowedUsername)
+	var i Relations
+	err := row.Scan(
+		&i.ID,
+		&i.FollowerUsername,
+		&i.FollowedUsername,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listFollowers = `-- name: ListFollowers :many
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.follower_username
+  WHERE relations.followed_username = $1 AND relations.id > $2 AND relations.id < $3
+  AND NOT $4::boolean
+  ORDER BY relations.id DESC
+  LIMIT $5
+)
+UNION ALL
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.follower_username
+  WHERE relations.followed_username = $1 AND relations.id > $2 AND relations.id < $3
+  AND $4::boolean
+  ORDER BY relations.id ASC
+  LIMIT $5
+)
+ORDER BY relation_id DESC
+`
+
+type ListFollowersParams struct {
+	Username string `json:"username"`
+	AfterID  int64  `json:"after_id"`
+	BeforeID int64  `json:"before_id"`
+	Reverse  bool   `json:"reverse"`
+	PageSize int32  `json:"page_size"`
+}
+
+type ListFollowersRow struct {
+	RelationID     int64         `json:"relation_id"`
+	Username       string        `json:"username"`
+	Name           string        `json:"name"`
+	FollowersCount sql.NullInt32 `json:"followers_count"`
+	FollowingCount sql.NullInt32 `json:"following_count"`
+}
+
+func (q *Queries) ListFollowers(ctx context.Context, arg ListFollowersParams) ([]ListFollowersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowers,
+		arg.Username,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.Reverse,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Relations{}
+	items := []ListFollowersRow{}
 	for rows.Next() {
-		var i Relations
+		var i ListFollowersRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.FollowerUsername,
-			&i.FollowedUsername,
-			&i.CreatedAt,
+			&i.RelationID,
+			&i.Username,
+			&i.Name,
+			&i.FollowersCount,
+			&i.FollowingCount,
 		); err != nil {
 			return nil, err
 		}
@@ -89,33 +146,66 @@ func (q *Queries) GetFollower(ctx context.Context, arg GetFollowerParams) ([]Rel
 	return items, nil
 }
 
-const getFollowing = `-- name: GetFollowing :many
-SELECT id, follower_username, followed_username, created_at FROM relations
-WHERE follower_username = $1
-ORDER BY id DESC
-LIMIT $2 OFFSET $3
+const listFollowing = `-- name: ListFollowing :many
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.followed_username
+  WHERE relations.follower_username = $1 AND relations.id > $2 AND relations.id < $3
+  AND NOT $4::boolean
+  ORDER BY relations.id DESC
+  LIMIT $5
+)
+UNION ALL
+(
+  SELECT relations.id AS relation_id, users.username, users.name, users.followers_count, users.following_count
+  FROM relations
+  JOIN users ON users.username = relations.followed_username
+  WHERE relations.follower_username = $1 AND relations.id > $2 AND relations.id < $3
+  AND $4::boolean
+  ORDER BY relations.id ASC
+  LIMIT $5
+)
+ORDER BY relation_id DESC
 `
 
-type GetFollowingParams struct {
-	FollowerUsername string `json:"follower_username"`
-	Limit            int32  `json:"limit"`
-	Offset           int32  `json:"offset"`
+type ListFollowingParams struct {
+	Username string `json:"username"`
+	AfterID  int64  `json:"after_id"`
+	BeforeID int64  `json:"before_id"`
+	Reverse  bool   `json:"reverse"`
+	PageSize int32  `json:"page_size"`
+}
+
+type ListFollowingRow struct {
+	RelationID     int64         `json:"relation_id"`
+	Username       string        `json:"username"`
+	Name           string        `json:"name"`
+	FollowersCount sql.NullInt32 `json:"followers_count"`
+	FollowingCount sql.NullInt32 `json:"following_count"`
 }
 
-func (q *Queries) GetFollowing(ctx context.Context, arg GetFollowingParams) ([]Relations, error) {
-	rows, err := q.db.QueryContext(ctx, getFollowing, arg.FollowerUsername, arg.Limit, arg.Offset)
+func (q *Queries) ListFollowing(ctx context.Context, arg ListFollowingParams) ([]ListFollowingRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowing,
+		arg.Username,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.Reverse,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Relations{}
+	items := []ListFollowingRow{}
 	for rows.Next() {
-		var i Relations
+		var i ListFollowingRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.FollowerUsername,
-			&i.FollowedUsername,
-			&i.CreatedAt,
+			&i.RelationID,
+			&i.Username,
+			&i.Name,
+			&i.FollowersCount,
+			&i.FollowingCount,
 		); err != nil {
 			return nil, err
 		}
@@ -129,26 +219,3 @@ func (q *Queries) GetFollowing(ctx context.Context, arg GetFollowingParams) ([]R
 	}
 	return items, nil
 }
-
-const getRelations = `-- name: GetRelations :one
-SELECT id, follower_username, followed_username, created_at FROM relations
-WHERE follower_username = $1 AND followed_username = $2
-LIMIT 1
-`
-
-type GetRelationsParams struct {
-	FollowerUsername string `json:"follower_username"`
-	FollowedUsername string `json:"followed_username"`
-}
-
-func (q *Queries) GetRelations(ctx context.Context, arg GetRelationsParams) (Relations, error) {
-	row := q.db.QueryRowContext(ctx, getRelations, arg.FollowerUsername, arg.FollowedUsername)
-	var i Relations
-	err := row.Scan(
-		&i.ID,
-		&i.FollowerUsername,
-		&i.FollowedUsername,
-		&i.CreatedAt,
-	)
-	return i, err
-}
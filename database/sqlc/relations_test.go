@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -76,12 +77,12 @@ func TestGetFollowers(t *testing.T) {
 		require.NotEmpty(t, relation)
 	}
 
-	getFollowersArg := GetFollowerParams{
-		FollowedUsername: followedAccount.Username,
-		Limit: 5,
-		Offset: 0,
+	getFollowersArg := ListFollowersParams{
+		Username: followedAccount.Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 5,
 	}
-	followerList, err := testQueries.GetFollower(context.Background(), getFollowersArg)
+	followerList, err := testQueries.ListFollowers(context.Background(), getFollowersArg)
 
 	require.NoError(t, err)
 	require.NotEmpty(t, followerList)
@@ -89,10 +90,23 @@ func TestGetFollowers(t *testing.T) {
 	
 	i := 4
 	for _, rel := range followerList {
-		require.Equal(t, followedAccount.Username, rel.FollowedUsername)
-		require.Equal(t, listFollowerAccount[i].Username, rel.FollowerUsername)
+		require.Equal(t, listFollowerAccount[i].Username, rel.Username)
 		i--
 	}
+
+	//walking back from the oldest returns the newer ones, still newest first
+	getFollowersArg = ListFollowersParams{
+		Username: followedAccount.Username,
+		AfterID: followerList[4].RelationID,
+		BeforeID: math.MaxInt64,
+		Reverse: true,
+		PageSize: 2,
+	}
+	newer, err := testQueries.ListFollowers(context.Background(), getFollowersArg)
+	require.NoError(t, err)
+	require.Len(t, newer, 2)
+	require.Equal(t, followerList[2].RelationID, newer[0].RelationID)
+	require.Equal(t, followerList[3].RelationID, newer[1].RelationID)
 }
 
 func TestGetFollowing(t *testing.T) {
@@ -111,12 +125,12 @@ func TestGetFollowing(t *testing.T) {
 		require.NotEmpty(t, relation)
 	}
 
-	getFollowingArg := GetFollowingParams{
-		FollowerUsername: followerAccount.Username,
-		Limit: 5,
-		Offset: 0,
+	getFollowingArg := ListFollowingParams{
+		Username: followerAccount.Username,
+		BeforeID: math.MaxInt64,
+		PageSize: 5,
 	}
-	followingList, err := testQueries.GetFollowing(context.Background(), getFollowingArg)
+	followingList, err := testQueries.ListFollowing(context.Background(), getFollowingArg)
 
 	require.NoError(t, err)
 	require.NotEmpty(t, followingList)
@@ -124,8 +138,7 @@ func TestGetFollowing(t *testing.T) {
 	
 	i := 4
 	for _, rel := range followingList {
-		require.Equal(t, followerAccount.Username, rel.FollowerUsername)
-		require.Equal(t, listFollowedAccount[i].Username, rel.FollowedUsername)
+		require.Equal(t, listFollowedAccount[i].Username, rel.Username)
 		i--
 	}
 }
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrNoDueScheduledTweet = errors.New("no scheduled tweet is due")
+
+type PublishScheduledTweetTxResult struct {
+	Scheduled ScheduledTweets     `json:"scheduled"`
+	Published CreateTweetTxResult `json:"published"`
+	// Failed is set when the scheduled tweet can never be published, it's kept with its error
+	Failed bool `json:"failed"`
+}
+
+// PublishScheduledTweetTx publishes the oldest due scheduled tweet and removes it from the queue.
+// The row is locked with SKIP LOCKED so every replica running the scheduler picks a different one.
+func (dbt *DBTransaction) PublishScheduledTweetTx(c context.Context) (PublishScheduledTweetTxResult, error) {
+	var res PublishScheduledTweetTxResult
+
+	err := dbt.execTransaction(c, func(q *Queries) error {
+		scheduled, err := q.GetDueScheduledTweetForUpdate(c)
+		if err != nil {
+			return err
+		}
+		res.Scheduled = scheduled
+
+		res.Published, err = publishTweet(c, q, CreateTweetTxParams{
+			Username: scheduled.Username,
+			Tweet:    scheduled.Tweet,
+			MediaIDs: scheduled.MediaIds,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteScheduledTweet(c, scheduled.ID)
+	})
+
+	switch err {
+	case sql.ErrNoRows:
+		return res, ErrNoDueScheduledTweet
+	case ErrMediaUnavailable:
+		//the publish was rolled back, park it so it isn't retried forever
+		res.Failed = true
+		res.Scheduled, err = dbt.MarkScheduledTweetFailed(c, MarkScheduledTweetFailedParams{
+			Error: err.Error(),
+			ID:    res.Scheduled.ID,
+		})
+		return res, err
+	}
+
+	return res, err
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+func CreateRandomScheduledTweet(t *testing.T, user Users, publishAt time.Time, mediaIDs []int64) ScheduledTweets {
+	arg := CreateScheduledTweetParams{
+		Username:  user.Username,
+		Tweet:     util.GetRandomString(20),
+		MediaIds:  mediaIDs,
+		PublishAt: publishAt,
+	}
+
+	scheduled, err := testQueries.CreateScheduledTweet(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, scheduled.ID)
+	require.Equal(t, arg.Tweet, scheduled.Tweet)
+	require.Equal(t, len(mediaIDs), len(scheduled.MediaIds))
+	require.False(t, scheduled.FailedAt.Valid)
+
+	return scheduled
+}
+
+// drain publishes everything that's due, including rows left over by other tests
+func drainScheduledTweets(t *testing.T, dbt Transaction) map[int64]PublishScheduledTweetTxResult {
+	results := map[int64]PublishScheduledTweetTxResult{}
+	for {
+		res, err := dbt.PublishScheduledTweetTx(context.Background())
+		if err == ErrNoDueScheduledTweet {
+			return results
+		}
+		require.NoError(t, err)
+		results[res.Scheduled.ID] = res
+	}
+}
+
+func TestPublishScheduledTweetTx(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	user := CreateRandomUser(t)
+	media := CreateRandomMedia(t, user)
+
+	due := CreateRandomScheduledTweet(t, user, time.Now().Add(-time.Second), []int64{media.ID})
+	later := CreateRandomScheduledTweet(t, user, time.Now().Add(time.Hour), []int64{})
+
+	results := drainScheduledTweets(t, dbt)
+	res, ok := results[due.ID]
+	require.True(t, ok)
+	require.False(t, res.Failed)
+	require.Equal(t, due.Tweet, res.Published.Tweet.Tweet)
+	require.Equal(t, user.Username, res.Published.Tweet.Username)
+	require.Len(t, res.Published.Media, 1)
+
+	_, ok = results[later.ID]
+	require.False(t, ok)
+
+	pending, err := dbt.ListScheduledTweets(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, later.ID, pending[0].ID)
+}
+
+func TestPublishScheduledTweetTxUnavailableMedia(t *testing.T) {
+	dbt := NewTransaction(testDB)
+	user := CreateRandomUser(t)
+	media := CreateRandomMedia(t, CreateRandomUser(t))
+
+	due := CreateRandomScheduledTweet(t, user, time.Now().Add(-time.Second), []int64{media.ID})
+
+	results := drainScheduledTweets(t, dbt)
+	res, ok := results[due.ID]
+	require.True(t, ok)
+	require.True(t, res.Failed)
+	require.True(t, res.Scheduled.FailedAt.Valid)
+	require.NotEmpty(t, res.Scheduled.Error)
+
+	//failed rows stay visible to the owner and aren't picked up again
+	require.NotContains(t, drainScheduledTweets(t, dbt), due.ID)
+
+	//rescheduling clears the failure
+	rescheduled, err := dbt.RescheduleTweet(context.Background(), RescheduleTweetParams{
+		PublishAt: time.Now().Add(time.Hour),
+		ID:        due.ID,
+		Username:  user.Username,
+	})
+	require.NoError(t, err)
+	require.False(t, rescheduled.FailedAt.Valid)
+	require.Empty(t, rescheduled.Error)
+}
+
+func TestCancelScheduledTweet(t *testing.T) {
+	user := CreateRandomUser(t)
+	scheduled := CreateRandomScheduledTweet(t, user, time.Now().Add(time.Hour), []int64{})
+
+	//other users can't cancel it
+	deleted, err := testQueries.CancelScheduledTweet(context.Background(), CancelScheduledTweetParams{
+		ID:       scheduled.ID,
+		Username: CreateRandomUser(t).Username,
+	})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	deleted, err = testQueries.CancelScheduledTweet(context.Background(), CancelScheduledTweetParams{
+		ID:       scheduled.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
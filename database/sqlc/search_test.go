@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+func findTweets(t *testing.T, viewer string, arg SearchTweetsParams) []int64 {
+	arg.Viewer = viewer
+	arg.Until = time.Now().Add(time.Hour)
+	arg.BeforeRank = math.MaxFloat32
+	arg.BeforeID = math.MaxInt64
+	arg.PageSize = 10
+
+	rows, err := testQueries.SearchTweets(context.Background(), arg)
+	require.NoError(t, err)
+
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids
+}
+
+func TestSearchTweets(t *testing.T) {
+	author := CreateRandomUser(t)
+	viewer := CreateRandomUser(t)
+	// a word no other test tweets, to keep the results to this test
+	word := "zq" + util.GetRandomString(10)
+
+	create := func(text string) Tweets {
+		tweet, err := testQueries.CreateTweet(context.Background(), CreateTweetParams{Tweet: text, Username: author.Username})
+		require.NoError(t, err)
+		return tweet
+	}
+	plain := create(word + " searching tweets")
+	tagged := create("searched with #" + word)
+	phrase := create(word + " tweets searching")
+
+	// stemmed words match hashtags too, only hashtags match a #tag
+	ids := findTweets(t, viewer.Username, SearchTweetsParams{Terms: word + " search"})
+	require.ElementsMatch(t, []int64{plain.ID, tagged.ID, phrase.ID}, ids)
+
+	ids = findTweets(t, viewer.Username, SearchTweetsParams{Hashtags: "'#" + word + "'"})
+	require.Equal(t, []int64{tagged.ID}, ids)
+
+	ids = findTweets(t, viewer.Username, SearchTweetsParams{Terms: `"` + word + ` searching tweets"`})
+	require.Equal(t, []int64{plain.ID}, ids)
+
+	// the search vector follows edits
+	_, err := testDB.Exec("UPDATE tweets SET tweet = $1 WHERE id = $2", "nothing to find", plain.ID)
+	require.NoError(t, err)
+	ids = findTweets(t, viewer.Username, SearchTweetsParams{Terms: word})
+	require.ElementsMatch(t, []int64{tagged.ID, phrase.ID}, ids)
+
+	ids = findTweets(t, viewer.Username, SearchTweetsParams{MatchAll: true, FromUsername: author.Username, MinLikes: 1})
+	require.Empty(t, ids)
+
+	// the search row goes with its tweet
+	require.NoError(t, testQueries.DeleteTweet(context.Background(), phrase.ID))
+	var indexed int
+	require.NoError(t, testDB.QueryRow("SELECT count(*) FROM tweet_search WHERE tweet_id = $1", phrase.ID).Scan(&indexed))
+	require.Zero(t, indexed)
+
+	err = NewTransaction(testDB).BlockTx(context.Background(), BlockTxParams{BlockerUsername: author.Username, BlockedUsername: viewer.Username})
+	require.NoError(t, err)
+	ids = findTweets(t, viewer.Username, SearchTweetsParams{Terms: word})
+	require.Empty(t, ids)
+}
+
+func TestSearchUsers(t *testing.T) {
+	viewer := CreateRandomUser(t)
+	prefix := "zq" + util.GetRandomString(6)
+
+	create := func(username, name string) Users {
+		user, err := testQueries.CreateUser(context.Background(), CreateUserParams{
+			Username:       username,
+			HashedPassword: util.GetRandomString(6),
+			Name:           name,
+			Email:          util.GetRandomEmail(),
+		})
+		require.NoError(t, err)
+		return user
+	}
+	exact := create(prefix, "someone")
+	longer := create(prefix+"_longer", "someone else")
+	followed := create(prefix+"_followed", "followed one")
+	named := create(util.GetRandomString(8), "a "+prefix+" fan")
+
+	dbt := NewTransaction(testDB)
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{Username: viewer.Username, FollowUser: followed.Username})
+	require.NoError(t, err)
+
+	rows, err := testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Query:          prefix,
+		Prefix:         prefix + "%",
+		Viewer:         viewer.Username,
+		CandidateLimit: 100,
+		PageSize:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	// the exact username first, then following beats a plain prefix match
+	require.Equal(t, exact.Username, rows[0].Username)
+	require.Equal(t, followed.Username, rows[1].Username)
+	require.True(t, rows[1].Following)
+	require.Equal(t, longer.Username, rows[2].Username)
+	require.Equal(t, named.Username, rows[3].Username)
+
+	// follows and followers starting with the query make it past a full cutoff
+	follower := create(prefix+"_zfan", "a follower")
+	_, err = dbt.FollowTx(context.Background(), FollowInputArgs{Username: follower.Username, FollowUser: viewer.Username})
+	require.NoError(t, err)
+	rows, err = testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Query:          prefix,
+		Prefix:         prefix + "%",
+		Viewer:         viewer.Username,
+		CandidateLimit: 2,
+		PageSize:       10,
+	})
+	require.NoError(t, err)
+	// the follower ties with longer on similarity and loses the cutoff on username
+	require.Len(t, rows, 3)
+	require.Equal(t, exact.Username, rows[0].Username)
+	require.Equal(t, followed.Username, rows[1].Username)
+	require.Equal(t, follower.Username, rows[2].Username)
+	require.True(t, rows[2].FollowedBy)
+
+	// blocked users don't show up
+	err = dbt.BlockTx(context.Background(), BlockTxParams{BlockerUsername: viewer.Username, BlockedUsername: exact.Username})
+	require.NoError(t, err)
+	rows, err = testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Query:          prefix,
+		Prefix:         prefix + "%",
+		Viewer:         viewer.Username,
+		CandidateLimit: 100,
+		PageSize:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+}
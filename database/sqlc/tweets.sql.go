@@ -7,22 +7,30 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createTweet = `-- name: CreateTweet :one
 INSERT INTO tweets
-(tweet, username)
-VALUES ($1,$2)
-RETURNING id, tweet, username, likes, created_at
+(tweet, username, in_reply_to_id, retweet_of_id)
+VALUES ($1,$2,$3,$4)
+RETURNING id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id
 `
 
 type CreateTweetParams struct {
-	Tweet    string `json:"tweet"`
-	Username string `json:"username"`
+	Tweet       string        `json:"tweet"`
+	Username    string        `json:"username"`
+	InReplyToID sql.NullInt64 `json:"in_reply_to_id"`
+	RetweetOfID sql.NullInt64 `json:"retweet_of_id"`
 }
 
 func (q *Queries) CreateTweet(ctx context.Context, arg CreateTweetParams) (Tweets, error) {
-	row := q.db.QueryRowContext(ctx, createTweet, arg.Tweet, arg.Username)
+	row := q.db.QueryRowContext(ctx, createTweet,
+		arg.Tweet,
+		arg.Username,
+		arg.InReplyToID,
+		arg.RetweetOfID,
+	)
 	var i Tweets
 	err := row.Scan(
 		&i.ID,
@@ -30,6 +38,9 @@ func (q *Queries) CreateTweet(ctx context.Context, arg CreateTweetParams) (Tweet
 		&i.Username,
 		&i.Likes,
 		&i.CreatedAt,
+		&i.EditedAt,
+		&i.InReplyToID,
+		&i.RetweetOfID,
 	)
 	return i, err
 }
@@ -38,7 +49,7 @@ const decrementLike = `-- name: DecrementLike :one
 UPDATE tweets SET
 likes = likes - 1
 WHERE id = $1
-RETURNING id, tweet, username, likes, created_at
+RETURNING id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id
 `
 
 func (q *Queries) DecrementLike(ctx context.Context, id int64) (Tweets, error) {
@@ -50,6 +61,9 @@ func (q *Queries) DecrementLike(ctx context.Context, id int64) (Tweets, error) {
 		&i.Username,
 		&i.Likes,
 		&i.CreatedAt,
+		&i.EditedAt,
+		&i.InReplyToID,
+		&i.RetweetOfID,
 	)
 	return i, err
 }
@@ -64,21 +78,123 @@ func (q *Queries) DeleteTweet(ctx context.Context, id int64) error {
 	return err
 }
 
-const getListTweets = `-- name: GetListTweets :many
-SELECT id, tweet, username, likes, created_at FROM tweets
-WHERE username = $1
+const getTweet = `-- name: GetTweet :one
+SELECT id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id FROM tweets
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTweet(ctx context.Context, id int64) (Tweets, error) {
+	row := q.db.QueryRowContext(ctx, getTweet, id)
+	var i Tweets
+	err := row.Scan(
+		&i.ID,
+		&i.Tweet,
+		&i.Username,
+		&i.Likes,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.InReplyToID,
+		&i.RetweetOfID,
+	)
+	return i, err
+}
+
+const getTweetForUpdate = `-- name: GetTweetForUpdate :one
+SELECT id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id FROM tweets
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetTweetForUpdate(ctx context.Context, id int64) (Tweets, error) {
+	row := q.db.QueryRowContext(ctx, getTweetForUpdate, id)
+	var i Tweets
+	err := row.Scan(
+		&i.ID,
+		&i.Tweet,
+		&i.Username,
+		&i.Likes,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.InReplyToID,
+		&i.RetweetOfID,
+	)
+	return i, err
+}
+
+const incrementLike = `-- name: IncrementLike :one
+UPDATE tweets SET
+likes = likes + 1
+WHERE id = $1
+RETURNING id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id
+`
+
+func (q *Queries) IncrementLike(ctx context.Context, id int64) (Tweets, error) {
+	row := q.db.QueryRowContext(ctx, incrementLike, id)
+	var i Tweets
+	err := row.Scan(
+		&i.ID,
+		&i.Tweet,
+		&i.Username,
+		&i.Likes,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.InReplyToID,
+		&i.RetweetOfID,
+	)
+	return i, err
+}
+
+const listUserTweets = `-- name: ListUserTweets :many
+(
+  SELECT id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id
+  FROM tweets
+  WHERE username = $1
+  AND ($2::boolean OR in_reply_to_id IS NULL)
+  AND ($3::boolean OR retweet_of_id IS NULL)
+  AND (NOT $4::boolean OR EXISTS (SELECT 1 FROM media WHERE media.tweet_id = tweets.id))
+  AND id > $5 AND id < $6
+  AND NOT $7::boolean
+  ORDER BY id DESC
+  LIMIT $8
+)
+UNION ALL
+(
+  SELECT id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id
+  FROM tweets
+  WHERE username = $1
+  AND ($2::boolean OR in_reply_to_id IS NULL)
+  AND ($3::boolean OR retweet_of_id IS NULL)
+  AND (NOT $4::boolean OR EXISTS (SELECT 1 FROM media WHERE media.tweet_id = tweets.id))
+  AND id > $5 AND id < $6
+  AND $7::boolean
+  ORDER BY id ASC
+  LIMIT $8
+)
 ORDER BY id DESC
-LIMIT $2 OFFSET $3
 `
 
-type GetListTweetsParams struct {
-	Username string `json:"username"`
-	Limit    int32  `json:"limit"`
-	Offset   int32  `json:"offset"`
+type ListUserTweetsParams struct {
+	Username        string `json:"username"`
+	IncludeReplies  bool   `json:"include_replies"`
+	IncludeRetweets bool   `json:"include_retweets"`
+	MediaOnly       bool   `json:"media_only"`
+	AfterID         int64  `json:"after_id"`
+	BeforeID        int64  `json:"before_id"`
+	Reverse         bool   `json:"reverse"`
+	PageSize        int32  `json:"page_size"`
 }
 
-func (q *Queries) GetListTweets(ctx context.Context, arg GetListTweetsParams) ([]Tweets, error) {
-	rows, err := q.db.QueryContext(ctx, getListTweets, arg.Username, arg.Limit, arg.Offset)
+func (q *Queries) ListUserTweets(ctx context.Context, arg ListUserTweetsParams) ([]Tweets, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTweets,
+		arg.Username,
+		arg.IncludeReplies,
+		arg.IncludeRetweets,
+		arg.MediaOnly,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.Reverse,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +208,9 @@ func (q *Queries) GetListTweets(ctx context.Context, arg GetListTweetsParams) ([
 			&i.Username,
 			&i.Likes,
 			&i.CreatedAt,
+			&i.EditedAt,
+			&i.InReplyToID,
+			&i.RetweetOfID,
 		); err != nil {
 			return nil, err
 		}
@@ -106,33 +225,20 @@ func (q *Queries) GetListTweets(ctx context.Context, arg GetListTweetsParams) ([
 	return items, nil
 }
 
-const getTweet = `-- name: GetTweet :one
-SELECT id, tweet, username, likes, created_at FROM tweets
-WHERE id = $1 LIMIT 1
+const updateTweet = `-- name: UpdateTweet :one
+UPDATE tweets SET
+tweet = $1, edited_at = now()
+WHERE id = $2
+RETURNING id, tweet, username, likes, created_at, edited_at, in_reply_to_id, retweet_of_id
 `
 
-func (q *Queries) GetTweet(ctx context.Context, id int64) (Tweets, error) {
-	row := q.db.QueryRowContext(ctx, getTweet, id)
-	var i Tweets
-	err := row.Scan(
-		&i.ID,
-		&i.Tweet,
-		&i.Username,
-		&i.Likes,
-		&i.CreatedAt,
-	)
-	return i, err
+type UpdateTweetParams struct {
+	Tweet string `json:"tweet"`
+	ID    int64  `json:"id"`
 }
 
-const incrementLike = `-- name: IncrementLike :one
-UPDATE tweets SET
-likes = likes + 1
-WHERE id = $1
-RETURNING id, tweet, username, likes, created_at
-`
-
-func (q *Queries) IncrementLike(ctx context.Context, id int64) (Tweets, error) {
-	row := q.db.QueryRowContext(ctx, incrementLike, id)
+func (q *Queries) UpdateTweet(ctx context.Context, arg UpdateTweetParams) (Tweets, error) {
+	row := q.db.QueryRowContext(ctx, updateTweet, arg.Tweet, arg.ID)
 	var i Tweets
 	err := row.Scan(
 		&i.ID,
@@ -140,6 +246,9 @@ func (q *Queries) IncrementLike(ctx context.Context, id int64) (Tweets, error) {
 		&i.Username,
 		&i.Likes,
 		&i.CreatedAt,
+		&i.EditedAt,
+		&i.InReplyToID,
+		&i.RetweetOfID,
 	)
 	return i, err
 }
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -52,7 +53,7 @@ func TestDeleteTweet(t *testing.T) {
 	require.Empty(t, getTweet)
 }
 
-func TestGetListTweets(t *testing.T) {
+func TestListUserTweets(t *testing.T) {
 	user := CreateRandomUser(t)
 
 	var recentTweets []string
@@ -69,12 +70,12 @@ func TestGetListTweets(t *testing.T) {
 		require.NotEmpty(t, tweeet)
 	}
 
-	getTweetArg := GetListTweetsParams{
+	getTweetArg := ListUserTweetsParams{
 		Username: user.Username,
-		Limit: 5,
-		Offset: 0,
+		BeforeID: math.MaxInt64,
+		PageSize: 5,
 	}
-	GetTweets, err := testQueries.GetListTweets(context.Background(), getTweetArg)
+	GetTweets, err := testQueries.ListUserTweets(context.Background(), getTweetArg)
 	require.NotEmpty(t, GetTweets)
 	require.NoError(t, err)
 
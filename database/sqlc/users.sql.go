@@ -7,13 +7,14 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users
 (username, email, hashed_password, name)
 VALUES ($1,$2,$3,$4)
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 type CreateUserParams struct {
@@ -40,6 +41,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (Users,
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
@@ -48,7 +51,7 @@ const decrementFollower = `-- name: DecrementFollower :one
 UPDATE users SET
 followers_count = followers_count - 1
 WHERE username = $1
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 func (q *Queries) DecrementFollower(ctx context.Context, username string) (Users, error) {
@@ -63,6 +66,8 @@ func (q *Queries) DecrementFollower(ctx context.Context, username string) (Users
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
@@ -71,7 +76,7 @@ const decrementFollowing = `-- name: DecrementFollowing :one
 UPDATE users SET
 following_count = following_count - 1
 WHERE username = $1
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 func (q *Queries) DecrementFollowing(ctx context.Context, username string) (Users, error) {
@@ -86,12 +91,14 @@ func (q *Queries) DecrementFollowing(ctx context.Context, username string) (User
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at FROM users
+SELECT username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -107,6 +114,32 @@ func (q *Queries) GetUser(ctx context.Context, username string) (Users, error) {
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, username string) (Users, error) {
+	row := q.db.QueryRowContext(ctx, getUserForUpdate, username)
+	var i Users
+	err := row.Scan(
+		&i.Username,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Name,
+		&i.FollowersCount,
+		&i.FollowingCount,
+		&i.ChangedPasswordAt,
+		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
@@ -115,7 +148,7 @@ const incrementFollower = `-- name: IncrementFollower :one
 UPDATE users SET
 followers_count = followers_count + 1
 WHERE username = $1
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 func (q *Queries) IncrementFollower(ctx context.Context, username string) (Users, error) {
@@ -130,6 +163,8 @@ func (q *Queries) IncrementFollower(ctx context.Context, username string) (Users
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
@@ -138,7 +173,7 @@ const incrementFollowing = `-- name: IncrementFollowing :one
 UPDATE users SET
 following_count = following_count + 1
 WHERE username = $1
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 func (q *Queries) IncrementFollowing(ctx context.Context, username string) (Users, error) {
@@ -153,15 +188,96 @@ func (q *Queries) IncrementFollowing(ctx context.Context, username string) (User
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
+	)
+	return i, err
+}
+
+const pinTweet = `-- name: PinTweet :one
+UPDATE users SET
+pinned_tweet_id = $1
+WHERE username = $2
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
+`
+
+type PinTweetParams struct {
+	PinnedTweetID sql.NullInt64 `json:"pinned_tweet_id"`
+	Username      string        `json:"username"`
+}
+
+func (q *Queries) PinTweet(ctx context.Context, arg PinTweetParams) (Users, error) {
+	row := q.db.QueryRowContext(ctx, pinTweet, arg.PinnedTweetID, arg.Username)
+	var i Users
+	err := row.Scan(
+		&i.Username,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Name,
+		&i.FollowersCount,
+		&i.FollowingCount,
+		&i.ChangedPasswordAt,
+		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
+	)
+	return i, err
+}
+
+const setProtected = `-- name: SetProtected :one
+UPDATE users SET
+protected = $1
+WHERE username = $2
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
+`
+
+type SetProtectedParams struct {
+	Protected bool   `json:"protected"`
+	Username  string `json:"username"`
+}
+
+func (q *Queries) SetProtected(ctx context.Context, arg SetProtectedParams) (Users, error) {
+	row := q.db.QueryRowContext(ctx, setProtected, arg.Protected, arg.Username)
+	var i Users
+	err := row.Scan(
+		&i.Username,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Name,
+		&i.FollowersCount,
+		&i.FollowingCount,
+		&i.ChangedPasswordAt,
+		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
 
+const unpinTweet = `-- name: UnpinTweet :execrows
+UPDATE users SET
+pinned_tweet_id = NULL
+WHERE username = $1 AND pinned_tweet_id = $2
+`
+
+type UnpinTweetParams struct {
+	Username      string        `json:"username"`
+	PinnedTweetID sql.NullInt64 `json:"pinned_tweet_id"`
+}
+
+func (q *Queries) UnpinTweet(ctx context.Context, arg UnpinTweetParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unpinTweet, arg.Username, arg.PinnedTweetID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateEmail = `-- name: UpdateEmail :one
 UPDATE users SET 
 email = $1
 WHERE username = $2
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 type UpdateEmailParams struct {
@@ -181,6 +297,8 @@ func (q *Queries) UpdateEmail(ctx context.Context, arg UpdateEmailParams) (Users
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
@@ -189,7 +307,7 @@ const updateName = `-- name: UpdateName :one
 UPDATE users SET
 name = $1
 WHERE username = $2
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 type UpdateNameParams struct {
@@ -209,6 +327,8 @@ func (q *Queries) UpdateName(ctx context.Context, arg UpdateNameParams) (Users,
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
@@ -217,7 +337,7 @@ const updatePassword = `-- name: UpdatePassword :one
 UPDATE users SET
 hashed_password = $1
 WHERE username = $2
-RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at
+RETURNING username, email, hashed_password, name, followers_count, following_count, changed_password_at, created_at, pinned_tweet_id, protected
 `
 
 type UpdatePasswordParams struct {
@@ -237,6 +357,8 @@ func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams)
 		&i.FollowingCount,
 		&i.ChangedPasswordAt,
 		&i.CreatedAt,
+		&i.PinnedTweetID,
+		&i.Protected,
 	)
 	return i, err
 }
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Webhook events
+const (
+	WebhookTweetCreated  = "tweet.created"
+	WebhookFollowCreated = "follow.created"
+	WebhookLikeCreated   = "like.created"
+)
+
+// WebhookEvents are the events a webhook can subscribe to
+var WebhookEvents = []string{WebhookTweetCreated, WebhookFollowCreated, WebhookLikeCreated}
+
+// WebhookPayload is the body posted to webhooks
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type TweetWebhookData struct {
+	ID          int64     `json:"id"`
+	Username    string    `json:"username"`
+	Tweet       string    `json:"tweet"`
+	InReplyToID *int64    `json:"in_reply_to_id,omitempty"`
+	RetweetOfID *int64    `json:"retweet_of_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newTweetWebhookData(tweet Tweets) TweetWebhookData {
+	data := TweetWebhookData{
+		ID:        tweet.ID,
+		Username:  tweet.Username,
+		Tweet:     tweet.Tweet,
+		CreatedAt: tweet.CreatedAt,
+	}
+	if tweet.InReplyToID.Valid {
+		data.InReplyToID = &tweet.InReplyToID.Int64
+	}
+	if tweet.RetweetOfID.Valid {
+		data.RetweetOfID = &tweet.RetweetOfID.Int64
+	}
+	return data
+}
+
+type FollowWebhookData struct {
+	Follower string `json:"follower"`
+	Followed string `json:"followed"`
+}
+
+type LikeWebhookData struct {
+	Username    string `json:"username"`
+	TweetID     int64  `json:"tweet_id"`
+	TweetAuthor string `json:"tweet_author"`
+}
+
+// enqueueWebhooks queues the event for the webhooks of every user involved, in the
+// transaction that made it happen so no event is lost or sent for a rollback
+func enqueueWebhooks(c context.Context, q *Queries, event string, usernames []string, data interface{}) error {
+	payload, err := json.Marshal(WebhookPayload{
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.EnqueueWebhookDeliveries(c, EnqueueWebhookDeliveriesParams{
+		Event:     event,
+		Payload:   payload,
+		Usernames: usernames,
+	})
+	return err
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhook(t *testing.T, username string, events ...string) Webhooks {
+	hook, err := testQueries.CreateWebhook(context.Background(), CreateWebhookParams{
+		Username: username,
+		Url:      "https://example.com/hooks",
+		Secret:   "secret",
+		Events:   events,
+	})
+	require.NoError(t, err)
+	require.Equal(t, events, hook.Events)
+	return hook
+}
+
+// claimOwnDeliveries claims every due delivery and keeps the ones of hook
+func claimOwnDeliveries(t *testing.T, hook Webhooks) []ClaimWebhookDeliveriesRow {
+	claimed, err := testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(time.Minute),
+		BatchSize:  1000,
+	})
+	require.NoError(t, err)
+
+	var own []ClaimWebhookDeliveriesRow
+	for _, delivery := range claimed {
+		if delivery.WebhookID == hook.ID {
+			own = append(own, delivery)
+		}
+	}
+	return own
+}
+
+func TestLikeEnqueuesWebhook(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	tweet := CreateTweet(t)
+	liker := CreateRandomUser(t)
+	authorHook := createRandomWebhook(t, tweet.Username, WebhookLikeCreated)
+	likerHook := createRandomWebhook(t, liker.Username, WebhookFollowCreated)
+
+	_, err := dbt.LikeTweetTx(context.Background(), CreateLikeRelationParams{
+		Username: liker.Username,
+		TweetID:  tweet.ID,
+	})
+	require.NoError(t, err)
+
+	claimed := claimOwnDeliveries(t, authorHook)
+	require.Len(t, claimed, 1)
+	require.Equal(t, WebhookLikeCreated, claimed[0].Event)
+
+	var payload struct {
+		Event string          `json:"event"`
+		Data  LikeWebhookData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(claimed[0].Payload, &payload))
+	require.Equal(t, LikeWebhookData{Username: liker.Username, TweetID: tweet.ID, TweetAuthor: tweet.Username}, payload.Data)
+
+	// the liker only subscribed to follows
+	require.Empty(t, claimOwnDeliveries(t, likerHook))
+
+	// claimed deliveries stay hidden until the lease runs out
+	require.Empty(t, claimOwnDeliveries(t, authorHook))
+}
+
+func TestDeadWebhookDeliveries(t *testing.T) {
+	dbt := NewTransaction(testDB)
+
+	user := CreateRandomUser(t)
+	follower := CreateRandomUser(t)
+	hook := createRandomWebhook(t, user.Username, WebhookFollowCreated)
+
+	_, err := dbt.FollowTx(context.Background(), FollowInputArgs{
+		Username:   follower.Username,
+		FollowUser: user.Username,
+	})
+	require.NoError(t, err)
+
+	claimed := claimOwnDeliveries(t, hook)
+	require.Len(t, claimed, 1)
+
+	err = testQueries.FailWebhookDelivery(context.Background(), FailWebhookDeliveryParams{
+		LastStatus:    sql.NullInt32{Int32: 500, Valid: true},
+		LastError:     sql.NullString{String: "webhook receiver answered 500", Valid: true},
+		NextAttemptAt: time.Now(),
+		Dead:          true,
+		ID:            claimed[0].ID,
+	})
+	require.NoError(t, err)
+
+	dead, err := testQueries.ListDeadWebhookDeliveries(context.Background(), ListDeadWebhookDeliveriesParams{
+		WebhookID: hook.ID,
+		BeforeID:  math.MaxInt64,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Equal(t, int32(1), dead[0].Attempts)
+	require.True(t, dead[0].DeadAt.Valid)
+
+	// dead deliveries aren't claimed again until they're retried
+	require.Empty(t, claimOwnDeliveries(t, hook))
+
+	retried, err := testQueries.RetryWebhookDelivery(context.Background(), RetryWebhookDeliveryParams{
+		ID:        dead[0].ID,
+		WebhookID: hook.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), retried)
+
+	claimed = claimOwnDeliveries(t, hook)
+	require.Len(t, claimed, 1)
+	require.Equal(t, int32(0), claimed[0].Attempts)
+}
@@ -0,0 +1,51 @@
+// Package e2ee checks the public keys devices publish for end-to-end encrypted
+// messages. The server only ever sees public keys and ciphertext, private keys
+// never leave the devices.
+package e2ee
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// KeySize is the size of X25519 public keys
+const KeySize = curve25519.PointSize
+
+var (
+	ErrInvalidKey        = errors.New("invalid X25519 public key")
+	ErrInvalidSigningKey = errors.New("invalid Ed25519 public key")
+	ErrBadSignature      = errors.New("signed prekey signature doesn't verify")
+)
+
+// probe is any scalar, a point multiplied by it only comes out as zero when the
+// point has a low order
+var probe = curve25519.Basepoint
+
+// ValidateKey checks an X25519 public key is usable in a key agreement. Low order
+// points are turned down since every shared secret derived from them is the same.
+func ValidateKey(key []byte) error {
+	if len(key) != KeySize {
+		return ErrInvalidKey
+	}
+	if _, err := curve25519.X25519(probe, key); err != nil {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// VerifySignedPrekey checks the signed prekey is the X25519 key the device signed
+// with its Ed25519 signing key. The signature covers the raw 32 bytes of the key.
+func VerifySignedPrekey(signingKey, prekey, signature []byte) error {
+	if len(signingKey) != ed25519.PublicKeySize {
+		return ErrInvalidSigningKey
+	}
+	if err := ValidateKey(prekey); err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(signingKey), prekey, signature) {
+		return ErrBadSignature
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package e2ee
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+)
+
+func newKey(t *testing.T) []byte {
+	private := make([]byte, curve25519.ScalarSize)
+	_, err := rand.Read(private)
+	require.NoError(t, err)
+
+	public, err := curve25519.X25519(private, curve25519.Basepoint)
+	require.NoError(t, err)
+	return public
+}
+
+func TestValidateKey(t *testing.T) {
+	require.NoError(t, ValidateKey(newKey(t)))
+
+	require.ErrorIs(t, ValidateKey(newKey(t)[:31]), ErrInvalidKey)
+	// u = 0 and u = 1 are both low order points
+	require.ErrorIs(t, ValidateKey(make([]byte, KeySize)), ErrInvalidKey)
+	lowOrder := make([]byte, KeySize)
+	lowOrder[0] = 1
+	require.ErrorIs(t, ValidateKey(lowOrder), ErrInvalidKey)
+}
+
+func TestVerifySignedPrekey(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	prekey := newKey(t)
+	signature := ed25519.Sign(private, prekey)
+	require.NoError(t, VerifySignedPrekey(public, prekey, signature))
+
+	require.ErrorIs(t, VerifySignedPrekey(public, newKey(t), signature), ErrBadSignature)
+	require.ErrorIs(t, VerifySignedPrekey(public[:16], prekey, signature), ErrInvalidSigningKey)
+	require.ErrorIs(t, VerifySignedPrekey(public, prekey[:16], signature), ErrInvalidKey)
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifySignedPrekey(other, prekey, signature), ErrBadSignature)
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	// quiet hours are read in the users' time zones, even on hosts without tzdata
+	_ "time/tzdata"
 
 	"github.com/ahmadfarhanstwn/twitter_wannabe/controllers"
 	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/outbox"
 	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/webhook"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/worker"
 )
 
 func main() {
@@ -23,13 +33,73 @@ func main() {
 	}
 
 	transaction := database.NewTransaction(conn)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	server, err := controllers.NewServer(config, transaction)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	err = server.Start(config.Server_Address)
+	scheduler := worker.NewScheduler(transaction, config.Scheduler_Interval, server.PublishTweet)
+	go scheduler.Start(ctx)
+
+	pollCloser := worker.NewPollCloser(transaction, config.Poll_Close_Interval)
+	go pollCloser.Start(ctx)
+
+	timelineFanout := worker.NewTimelineFanout(transaction, config.Timeline_Fanout_Interval, config.Celebrity_Follower_Threshold,
+		config.Timeline_Retention)
+	go timelineFanout.Start(ctx)
+
+	webhookDispatcher := worker.NewWebhookDispatcher(transaction, webhook.NewSender(config.Webhook_Timeout), config.Webhook_Interval,
+		config.Webhook_Max_Attempts, config.Webhook_Backoff, config.Webhook_Max_Backoff)
+	go webhookDispatcher.Start(ctx)
+
+	eventPublisher, err := newEventPublisher(config)
 	if err != nil {
 		log.Fatal(err)
 	}
+	outboxRelay := worker.NewOutboxRelay(transaction, eventPublisher, config.Outbox_Interval, config.Outbox_Retention)
+	go outboxRelay.Start(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Start(config.Server_Address)
+	}()
+
+	select {
+	case err = <-serveErr:
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case <-ctx.Done():
+	}
+
+	fmt.Println("shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Shutdown_Timeout)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newEventPublisher builds the outbox publisher OUTBOX_PUBLISHER names
+func newEventPublisher(config util.Config) (outbox.EventPublisher, error) {
+	switch config.Outbox_Publisher {
+	case util.OutboxPublisherNATS:
+		conn, err := outbox.DialNATS(config.Outbox_Broker_URL, config.Outbox_Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("outbox : connecting to nats : %w", err)
+		}
+		return outbox.NewNATSPublisher(conn, config.Outbox_Topic), nil
+	case util.OutboxPublisherKafka:
+		writer := outbox.NewKafkaRESTWriter(&http.Client{Timeout: config.Outbox_Timeout}, config.Outbox_Broker_URL)
+		return outbox.NewKafkaPublisher(writer, config.Outbox_Topic), nil
+	default:
+		log.Println("WARNING outbox : OUTBOX_PUBLISHER=memory has no subscribers, events are marked published without leaving the process")
+		return outbox.NewMemoryPublisher(), nil
+	}
 }
\ No newline at end of file
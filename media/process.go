@@ -0,0 +1,178 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ThumbnailSize  = 320
+	ThumbnailMime  = "image/jpeg"
+	maxImagePixels = 8192 * 8192
+	jpegQuality    = 90
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported media type")
+	ErrInvalidImage    = errors.New("image can't be decoded")
+)
+
+// allowed mime types mapped to the extension used for the stored blob
+var allowedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"video/mp4":  ".mp4",
+	"video/webm": ".webm",
+}
+
+type Processed struct {
+	MimeType  string
+	Extension string
+	Data      []byte
+	Width     int
+	Height    int
+	// Thumbnail is always a jpeg, empty for non image media
+	Thumbnail []byte
+}
+
+func IsImage(mimeType string) bool {
+	return mimeType == "image/jpeg" || mimeType == "image/png" || mimeType == "image/gif"
+}
+
+// Process sniffs the real type of the upload (the client supplied header can't be trusted),
+// re-encodes images so EXIF and other metadata are dropped, and builds a thumbnail
+func Process(data []byte) (*Processed, error) {
+	mimeType := http.DetectContentType(data)
+	ext, ok := allowedTypes[mimeType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	res := &Processed{
+		MimeType:  mimeType,
+		Extension: ext,
+		Data:      data,
+	}
+	if !IsImage(mimeType) {
+		return res, nil
+	}
+
+	//refuse decompression bombs before allocating the full image
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, ErrInvalidImage
+	}
+	res.Width = config.Width
+	res.Height = config.Height
+
+	var first image.Image
+	var out bytes.Buffer
+	switch mimeType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrInvalidImage
+		}
+		if err = jpeg.Encode(&out, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		first = img
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrInvalidImage
+		}
+		if err = png.Encode(&out, img); err != nil {
+			return nil, err
+		}
+		first = img
+	case "image/gif":
+		//keep every frame so animations survive, comments and xmp extensions are dropped
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil || len(g.Image) == 0 {
+			return nil, ErrInvalidImage
+		}
+		if err = gif.EncodeAll(&out, g); err != nil {
+			return nil, err
+		}
+		first = g.Image[0]
+	}
+	res.Data = out.Bytes()
+
+	var thumb bytes.Buffer
+	if err = jpeg.Encode(&thumb, Thumbnail(first, ThumbnailSize), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	res.Thumbnail = thumb.Bytes()
+
+	return res, nil
+}
+
+// Thumbnail scales img down so its longest side is at most size, averaging every source
+// pixel covered by a destination pixel. Transparent areas are flattened on white.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, h*size/w
+		} else {
+			tw, th = w*size/h, size
+		}
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		sy0 := bounds.Min.Y + y*h/th
+		sy1 := bounds.Min.Y + (y+1)*h/th
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < tw; x++ {
+			sx0 := bounds.Min.X + x*w/tw
+			sx1 := bounds.Min.X + (x+1)*w/tw
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, b, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					//premultiplied, so adding the missing alpha as white flattens it
+					white := 0xffff - uint64(ca)
+					r += uint64(cr) + white
+					g += uint64(cg) + white
+					b += uint64(cb) + white
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: 0xffff,
+			})
+		}
+	}
+
+	return dst
+}
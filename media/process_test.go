@@ -0,0 +1,82 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+// jpeg with an APP1 (EXIF) segment right after SOI
+func jpegWithExif(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, randomImage(800, 400), nil))
+	data := buf.Bytes()
+
+	exif := append([]byte("Exif\x00\x00"), []byte("GPS 52.52N 13.40E")...)
+	segment := []byte{0xff, 0xe1, byte((len(exif) + 2) >> 8), byte(len(exif) + 2)}
+	segment = append(segment, exif...)
+
+	res := append([]byte{}, data[:2]...)
+	res = append(res, segment...)
+	return append(res, data[2:]...)
+}
+
+func TestProcessJpegStripsExif(t *testing.T) {
+	data := jpegWithExif(t)
+	require.True(t, bytes.Contains(data, []byte("Exif")))
+
+	res, err := Process(data)
+	require.NoError(t, err)
+	require.Equal(t, "image/jpeg", res.MimeType)
+	require.Equal(t, ".jpg", res.Extension)
+	require.Equal(t, 800, res.Width)
+	require.Equal(t, 400, res.Height)
+	require.False(t, bytes.Contains(res.Data, []byte("Exif")))
+	require.False(t, bytes.Contains(res.Data, []byte("GPS")))
+
+	thumb, err := jpeg.Decode(bytes.NewReader(res.Thumbnail))
+	require.NoError(t, err)
+	require.Equal(t, ThumbnailSize, thumb.Bounds().Dx())
+	require.Equal(t, ThumbnailSize/2, thumb.Bounds().Dy())
+}
+
+func TestProcessPng(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, randomImage(100, 50)))
+
+	res, err := Process(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "image/png", res.MimeType)
+
+	//small images keep their size
+	thumb, err := jpeg.Decode(bytes.NewReader(res.Thumbnail))
+	require.NoError(t, err)
+	require.Equal(t, 100, thumb.Bounds().Dx())
+	require.Equal(t, 50, thumb.Bounds().Dy())
+}
+
+func TestProcessUnsupported(t *testing.T) {
+	_, err := Process([]byte("#!/bin/sh\nrm -rf /"))
+	require.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestProcessCorruptImage(t *testing.T) {
+	//valid png signature followed by garbage
+	_, err := Process(append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), []byte("garbage")...))
+	require.ErrorIs(t, err, ErrInvalidImage)
+}
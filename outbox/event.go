@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Event is a domain event read from the outbox. ID grows with every event and is
+// what consumers deduplicate on.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Key       string          `json:"key"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// MessageID identifies the event on a broker
+func (e Event) MessageID() string {
+	return strconv.FormatInt(e.ID, 10)
+}
+
+// EventPublisher publishes events in the order they're given. Publish returns once the
+// event is accepted, an error makes the relay publish it again later.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Handler consumes an event, events can be delivered more than once
+type Handler func(ctx context.Context, event Event) error
+
+// Encode is the event as it's sent to brokers
+func Encode(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Decode reads an event sent by a broker adapter
+func Decode(data []byte) (Event, error) {
+	var event Event
+	err := json.Unmarshal(data, &event)
+	return event, err
+}
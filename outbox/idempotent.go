@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+)
+
+// ProcessedStore remembers which events each consumer has handled
+type ProcessedStore interface {
+	HasProcessed(ctx context.Context, consumer string, eventID int64) (bool, error)
+	MarkProcessed(ctx context.Context, consumer string, eventID int64) error
+}
+
+// Idempotent wraps handler so an event it already handled is skipped. An event is
+// marked only after handler succeeds, a failed one is handled again on redelivery.
+func Idempotent(consumer string, store ProcessedStore, handler Handler) Handler {
+	return func(ctx context.Context, event Event) error {
+		processed, err := store.HasProcessed(ctx, consumer, event.ID)
+		if err != nil {
+			return err
+		}
+		if processed {
+			return nil
+		}
+
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+		return store.MarkProcessed(ctx, consumer, event.ID)
+	}
+}
+
+type processedKey struct {
+	consumer string
+	eventID  int64
+}
+
+// MemoryProcessedStore is a ProcessedStore for in-process consumers
+type MemoryProcessedStore struct {
+	mu        sync.Mutex
+	processed map[processedKey]bool
+}
+
+func NewMemoryProcessedStore() *MemoryProcessedStore {
+	return &MemoryProcessedStore{processed: map[processedKey]bool{}}
+}
+
+func (s *MemoryProcessedStore) HasProcessed(ctx context.Context, consumer string, eventID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[processedKey{consumer, eventID}], nil
+}
+
+func (s *MemoryProcessedStore) MarkProcessed(ctx context.Context, consumer string, eventID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed[processedKey{consumer, eventID}] = true
+	return nil
+}
+
+// DBProcessedStore keeps the processed events in the processed_events table
+type DBProcessedStore struct {
+	querier database.Querier
+}
+
+func NewDBProcessedStore(querier database.Querier) *DBProcessedStore {
+	return &DBProcessedStore{querier: querier}
+}
+
+func (s *DBProcessedStore) HasProcessed(ctx context.Context, consumer string, eventID int64) (bool, error) {
+	return s.querier.HasProcessedEvent(ctx, database.HasProcessedEventParams{
+		Consumer: consumer,
+		EventID:  eventID,
+	})
+}
+
+func (s *DBProcessedStore) MarkProcessed(ctx context.Context, consumer string, eventID int64) error {
+	return s.querier.MarkEventProcessed(ctx, database.MarkEventProcessedParams{
+		Consumer: consumer,
+		EventID:  eventID,
+	})
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import "context"
+
+// KafkaHeader and KafkaMessage mirror the message types of the common Go clients
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+type KafkaMessage struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers []KafkaHeader
+}
+
+// KafkaWriter is the part of a Kafka producer the publisher needs, WriteMessages
+// returns once the messages are acknowledged
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...KafkaMessage) error
+}
+
+// KafkaPublisher writes every event to Topic, keyed by its aggregate key so the
+// events of one aggregate land on one partition in order
+type KafkaPublisher struct {
+	Writer KafkaWriter
+	Topic  string
+}
+
+func NewKafkaPublisher(writer KafkaWriter, topic string) *KafkaPublisher {
+	return &KafkaPublisher{Writer: writer, Topic: topic}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := Encode(event)
+	if err != nil {
+		return err
+	}
+
+	return p.Writer.WriteMessages(ctx, KafkaMessage{
+		Topic: p.Topic,
+		Key:   []byte(event.Key),
+		Value: data,
+		Headers: []KafkaHeader{
+			{Key: "event-id", Value: []byte(event.MessageID())},
+			{Key: "event-type", Value: []byte(event.Type)},
+		},
+	})
+}
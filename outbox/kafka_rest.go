@@ -0,0 +1,92 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const kafkaRESTContentType = "application/vnd.kafka.binary.v2+json"
+
+// KafkaRESTWriter is a KafkaWriter producing through a Kafka REST Proxy (v2 API). The
+// v2 API has no record headers, they're dropped, the encoded event carries its id
+// and type anyway.
+type KafkaRESTWriter struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+func NewKafkaRESTWriter(client *http.Client, baseURL string) *KafkaRESTWriter {
+	return &KafkaRESTWriter{Client: client, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+type kafkaRESTRecord struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type kafkaRESTResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// WriteMessages posts the messages of every topic in one request each, records are
+// base64 encoded as the binary embedded format wants
+func (w *KafkaRESTWriter) WriteMessages(ctx context.Context, msgs ...KafkaMessage) error {
+	var topics []string
+	records := map[string][]kafkaRESTRecord{}
+	for _, msg := range msgs {
+		if _, ok := records[msg.Topic]; !ok {
+			topics = append(topics, msg.Topic)
+		}
+		records[msg.Topic] = append(records[msg.Topic], kafkaRESTRecord{Key: msg.Key, Value: msg.Value})
+	}
+
+	for _, topic := range topics {
+		if err := w.produce(ctx, topic, records[topic]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *KafkaRESTWriter) produce(ctx context.Context, topic string, records []kafkaRESTRecord) error {
+	body, err := json.Marshal(map[string][]kafkaRESTRecord{"records": records})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.BaseURL+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaRESTContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kafka rest proxy : producing to %s answered %d", topic, resp.StatusCode)
+	}
+
+	var produced kafkaRESTResponse
+	if err = json.NewDecoder(resp.Body).Decode(&produced); err != nil {
+		return err
+	}
+	for _, offset := range produced.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka rest proxy : producing to %s failed : %s", topic, offset.Error)
+		}
+	}
+	return nil
+}
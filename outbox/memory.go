@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher hands events to in-process handlers as they're published
+type MemoryPublisher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{handlers: map[string][]Handler{}}
+}
+
+// Subscribe registers handler for events of eventType, every event when it's empty
+func (p *MemoryPublisher) Subscribe(eventType string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+// Publish runs the handlers one after the other and stops at the first error, the
+// ones before it see the event again when it's retried
+func (p *MemoryPublisher) Publish(ctx context.Context, event Event) error {
+	p.mu.RLock()
+	handlers := append(append([]Handler{}, p.handlers[""]...), p.handlers[event.Type]...)
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import "context"
+
+// natsMsgIDHeader is what JetStream deduplicates published messages on
+const natsMsgIDHeader = "Nats-Msg-Id"
+
+// NATSMsg has the fields of nats.Msg the publisher fills in
+type NATSMsg struct {
+	Subject string
+	Header  map[string][]string
+	Data    []byte
+}
+
+// NATSConn is the part of a NATS or JetStream connection the publisher needs, a thin
+// wrapper copying NATSMsg into nats.Msg adapts the client
+type NATSConn interface {
+	PublishMsg(msg *NATSMsg) error
+}
+
+// NATSPublisher publishes every event on SubjectPrefix.<event type>
+type NATSPublisher struct {
+	Conn          NATSConn
+	SubjectPrefix string
+}
+
+func NewNATSPublisher(conn NATSConn, subjectPrefix string) *NATSPublisher {
+	return &NATSPublisher{Conn: conn, SubjectPrefix: subjectPrefix}
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := Encode(event)
+	if err != nil {
+		return err
+	}
+
+	return p.Conn.PublishMsg(&NATSMsg{
+		Subject: p.SubjectPrefix + "." + event.Type,
+		Header:  map[string][]string{natsMsgIDHeader: {event.MessageID()}},
+		Data:    data,
+	})
+}
@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSClient is a NATSConn speaking the NATS protocol over one TCP connection. Every
+// publish is followed by a PING so it only returns once the server has processed it,
+// a broken connection is dialed again on the next publish.
+type NATSClient struct {
+	mu      sync.Mutex
+	address string
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+}
+
+// DialNATS connects to a nats://host:port URL, timeout bounds the dial and every
+// publish
+func DialNATS(rawURL string, timeout time.Duration) (*NATSClient, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "nats" || parsed.Host == "" {
+		return nil, fmt.Errorf("nats url must look like nats://host:port, got %q", rawURL)
+	}
+
+	client := &NATSClient{address: parsed.Host, timeout: timeout}
+	if err = client.connect(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *NATSClient) connect() error {
+	conn, err := net.DialTimeout("tcp", c.address, c.timeout)
+	if err != nil {
+		return err
+	}
+
+	c.conn, c.reader = conn, bufio.NewReader(conn)
+	if err = c.handshake(); err != nil {
+		c.disconnect()
+		return err
+	}
+	return nil
+}
+
+func (c *NATSClient) disconnect() {
+	c.conn.Close()
+	c.conn, c.reader = nil, nil
+}
+
+func (c *NATSClient) handshake() error {
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("nats : expected INFO, got %q", line)
+	}
+
+	_, err = c.conn.Write([]byte("CONNECT {\"verbose\":false,\"pedantic\":false,\"headers\":true}\r\nPING\r\n"))
+	if err != nil {
+		return err
+	}
+	return c.waitPong()
+}
+
+func (c *NATSClient) PublishMsg(msg *NATSMsg) error {
+	var header bytes.Buffer
+	header.WriteString("NATS/1.0\r\n")
+	for key, values := range msg.Header {
+		for _, value := range values {
+			fmt.Fprintf(&header, "%s: %s\r\n", key, value)
+		}
+	}
+	header.WriteString("\r\n")
+
+	var frame bytes.Buffer
+	fmt.Fprintf(&frame, "HPUB %s %d %d\r\n", msg.Subject, header.Len(), header.Len()+len(msg.Data))
+	frame.Write(header.Bytes())
+	frame.Write(msg.Data)
+	frame.WriteString("\r\nPING\r\n")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write(frame.Bytes()); err != nil {
+		c.disconnect()
+		return err
+	}
+
+	err := c.waitPong()
+	var serverErr natsError
+	if err != nil && !errors.As(err, &serverErr) {
+		c.disconnect()
+	}
+	return err
+}
+
+// waitPong reads until the server answers our PING, answering its own on the way.
+// It returns the -ERR the server sent before the PONG if any.
+func (c *NATSClient) waitPong() error {
+	var serverErr error
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case line == "PONG":
+			return serverErr
+		case line == "PING":
+			if _, err = c.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR") && serverErr == nil:
+			serverErr = natsError(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// natsError is an -ERR the server answered with, the connection stays usable
+type natsError string
+
+func (e natsError) Error() string {
+	return "nats : " + string(e)
+}
+
+func (c *NATSClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *NATSClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.reader = nil, nil
+	return err
+}
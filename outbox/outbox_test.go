@@ -0,0 +1,241 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(id int64, eventType string) Event {
+	return Event{
+		ID:        id,
+		Type:      eventType,
+		Key:       "follow:alice:bob",
+		Payload:   json.RawMessage(`{"follower":"alice","followed":"bob"}`),
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	publisher := NewMemoryPublisher()
+
+	var all, follows []int64
+	publisher.Subscribe("", func(ctx context.Context, event Event) error {
+		all = append(all, event.ID)
+		return nil
+	})
+	publisher.Subscribe("follow.created", func(ctx context.Context, event Event) error {
+		follows = append(follows, event.ID)
+		return nil
+	})
+
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(1, "follow.created")))
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(2, "like.created")))
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(3, "follow.created")))
+
+	require.Equal(t, []int64{1, 2, 3}, all)
+	require.Equal(t, []int64{1, 3}, follows)
+
+	errDown := errors.New("down")
+	publisher.Subscribe("like.created", func(ctx context.Context, event Event) error {
+		return errDown
+	})
+	require.Equal(t, errDown, publisher.Publish(context.Background(), testEvent(4, "like.created")))
+}
+
+type fakeNATSConn struct {
+	msgs []*NATSMsg
+}
+
+func (c *fakeNATSConn) PublishMsg(msg *NATSMsg) error {
+	c.msgs = append(c.msgs, msg)
+	return nil
+}
+
+func TestNATSPublisher(t *testing.T) {
+	conn := &fakeNATSConn{}
+	publisher := NewNATSPublisher(conn, "twitter.events")
+	event := testEvent(7, "follow.created")
+
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.Len(t, conn.msgs, 1)
+	require.Equal(t, "twitter.events.follow.created", conn.msgs[0].Subject)
+	require.Equal(t, []string{"7"}, conn.msgs[0].Header[natsMsgIDHeader])
+
+	decoded, err := Decode(conn.msgs[0].Data)
+	require.NoError(t, err)
+	require.Equal(t, event.ID, decoded.ID)
+	require.JSONEq(t, string(event.Payload), string(decoded.Payload))
+	require.True(t, event.CreatedAt.Equal(decoded.CreatedAt))
+}
+
+type fakeKafkaWriter struct {
+	msgs []KafkaMessage
+}
+
+func (w *fakeKafkaWriter) WriteMessages(ctx context.Context, msgs ...KafkaMessage) error {
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	writer := &fakeKafkaWriter{}
+	publisher := NewKafkaPublisher(writer, "events")
+	event := testEvent(9, "follow.deleted")
+
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.Len(t, writer.msgs, 1)
+	require.Equal(t, "events", writer.msgs[0].Topic)
+	require.Equal(t, []byte(event.Key), writer.msgs[0].Key)
+	require.Contains(t, writer.msgs[0].Headers, KafkaHeader{Key: "event-id", Value: []byte("9")})
+
+	decoded, err := Decode(writer.msgs[0].Value)
+	require.NoError(t, err)
+	require.Equal(t, event.Type, decoded.Type)
+}
+
+// fakeNATSServer accepts one connection and answers like a NATS server, publishes to
+// the "bad" subject get an -ERR
+func fakeNATSServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	published := make(chan string, 4)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("INFO {\"headers\":true}\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch fields[0] {
+			case "PING":
+				conn.Write([]byte("PONG\r\n"))
+			case "HPUB":
+				size, _ := strconv.Atoi(fields[3])
+				frame := make([]byte, size+2)
+				if _, err = io.ReadFull(reader, frame); err != nil {
+					return
+				}
+				if fields[1] == "bad" {
+					conn.Write([]byte("-ERR 'Permissions Violation'\r\n"))
+				}
+				published <- fields[1] + " " + string(frame[:size])
+			}
+		}
+	}()
+	return "nats://" + listener.Addr().String(), published
+}
+
+func TestNATSClient(t *testing.T) {
+	address, published := fakeNATSServer(t)
+
+	client, err := DialNATS(address, time.Second)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.PublishMsg(&NATSMsg{
+		Subject: "twitter.events.like.created",
+		Header:  map[string][]string{natsMsgIDHeader: {"5"}},
+		Data:    []byte(`{"id":5}`),
+	}))
+	require.Equal(t, "twitter.events.like.created NATS/1.0\r\nNats-Msg-Id: 5\r\n\r\n{\"id\":5}", <-published)
+
+	require.EqualError(t, client.PublishMsg(&NATSMsg{Subject: "bad", Data: []byte("{}")}), "nats : 'Permissions Violation'")
+	<-published
+
+	// the connection stays in step after an error
+	require.NoError(t, client.PublishMsg(&NATSMsg{Subject: "twitter.events.like.deleted", Data: []byte("{}")}))
+	require.Equal(t, "twitter.events.like.deleted NATS/1.0\r\n\r\n{}", <-published)
+
+	_, err = DialNATS("http://"+strings.TrimPrefix(address, "nats://"), time.Second)
+	require.Error(t, err)
+}
+
+func TestKafkaRESTWriter(t *testing.T) {
+	var records []kafkaRESTRecord
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, kafkaRESTContentType, r.Header.Get("Content-Type"))
+
+		var body struct {
+			Records []kafkaRESTRecord `json:"records"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		records = append(records, body.Records...)
+
+		if r.URL.Path == "/topics/full" {
+			w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50002,"error":"Kafka error"}]}`))
+			return
+		}
+		require.Equal(t, "/topics/events", r.URL.Path)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":12,"error_code":null,"error":null}]}`))
+	}))
+	defer proxy.Close()
+
+	writer := NewKafkaRESTWriter(proxy.Client(), proxy.URL+"/")
+	publisher := NewKafkaPublisher(writer, "events")
+	event := testEvent(9, "follow.deleted")
+
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.Len(t, records, 1)
+	require.Equal(t, []byte(event.Key), records[0].Key)
+	decoded, err := Decode(records[0].Value)
+	require.NoError(t, err)
+	require.Equal(t, event.ID, decoded.ID)
+
+	require.Error(t, NewKafkaPublisher(writer, "full").Publish(context.Background(), event))
+}
+
+func TestIdempotent(t *testing.T) {
+	store := NewMemoryProcessedStore()
+
+	handled := 0
+	fail := true
+	handler := Idempotent("counter", store, func(ctx context.Context, event Event) error {
+		if fail {
+			fail = false
+			return errors.New("try again")
+		}
+		handled++
+		return nil
+	})
+
+	event := testEvent(1, "like.created")
+
+	// a failed event isn't marked, the redelivery handles it
+	require.Error(t, handler(context.Background(), event))
+	require.NoError(t, handler(context.Background(), event))
+	require.Equal(t, 1, handled)
+
+	// later redeliveries are skipped
+	require.NoError(t, handler(context.Background(), event))
+	require.Equal(t, 1, handled)
+
+	// other consumers keep their own record
+	other := 0
+	require.NoError(t, Idempotent("other", store, func(ctx context.Context, event Event) error {
+		other++
+		return nil
+	})(context.Background(), event))
+	require.Equal(t, 1, other)
+}
@@ -0,0 +1,47 @@
+package ranking
+
+// Diversifier picks the page shown out of candidates sorted by score
+type Diversifier interface {
+	Diversify(ranked []Scored, limit int) []Scored
+}
+
+// AuthorDiversity keeps one author from taking over a page. Each further tweet of
+// an author has its score multiplied by Penalty again, and no author gets more
+// than MaxPerAuthor tweets, 0 for no cap.
+type AuthorDiversity struct {
+	MaxPerAuthor int
+	Penalty      float64
+}
+
+func (d AuthorDiversity) Diversify(ranked []Scored, limit int) []Scored {
+	picked := make([]Scored, 0, limit)
+	used := make([]bool, len(ranked))
+	perAuthor := map[string]int{}
+
+	for len(picked) < limit {
+		best := -1
+		bestScore := 0.0
+		for i, candidate := range ranked {
+			shown := perAuthor[candidate.Author]
+			if used[i] || d.MaxPerAuthor > 0 && shown >= d.MaxPerAuthor {
+				continue
+			}
+			score := candidate.Score
+			for j := 0; j < shown; j++ {
+				score *= d.Penalty
+			}
+			//ranked is sorted, the first one wins ties
+			if best == -1 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best == -1 {
+			break
+		}
+
+		used[best] = true
+		perAuthor[ranked[best].Author]++
+		picked = append(picked, ranked[best])
+	}
+	return picked
+}
@@ -0,0 +1,49 @@
+package ranking
+
+import (
+	"sort"
+	"time"
+)
+
+// Scored is a candidate with the score it was ranked by
+type Scored struct {
+	Candidate
+	Score float64 `json:"score"`
+}
+
+// Ranker orders candidates by Scorer then lets Diversifier pick the page, a nil
+// Diversifier keeps the best scores
+type Ranker struct {
+	Scorer      Scorer
+	Diversifier Diversifier
+}
+
+func NewRanker() *Ranker {
+	return &Ranker{
+		Scorer:      DefaultScorer(),
+		Diversifier: AuthorDiversity{MaxPerAuthor: 3, Penalty: 0.7},
+	}
+}
+
+// Rank returns up to limit candidates, the best first. Ties go to the newest tweet
+// so the same candidates always rank the same.
+func (r *Ranker) Rank(candidates []Candidate, now time.Time, limit int) []Scored {
+	ranked := make([]Scored, len(candidates))
+	for i, candidate := range candidates {
+		ranked[i] = Scored{Candidate: candidate, Score: r.Scorer.Score(candidate, now)}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].TweetID > ranked[j].TweetID
+	})
+
+	if r.Diversifier != nil {
+		return r.Diversifier.Diversify(ranked, limit)
+	}
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
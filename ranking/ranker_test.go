@@ -0,0 +1,39 @@
+package ranking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankerDiversity(t *testing.T) {
+	now := time.Now()
+	candidates := []Candidate{
+		{TweetID: 1, Author: "a", Likes: 9},
+		{TweetID: 2, Author: "a", Likes: 8},
+		{TweetID: 3, Author: "a", Likes: 7},
+		{TweetID: 4, Author: "b", Likes: 6},
+		{TweetID: 5, Author: "c"},
+	}
+	ids := func(page []Scored) []int64 {
+		ids := []int64{}
+		for _, scored := range page {
+			ids = append(ids, scored.TweetID)
+		}
+		return ids
+	}
+	scorer := Engagement{LikeWeight: 1}
+
+	plain := &Ranker{Scorer: scorer}
+	require.Equal(t, []int64{1, 2, 3}, ids(plain.Rank(candidates, now, 3)))
+
+	capped := &Ranker{Scorer: scorer, Diversifier: AuthorDiversity{MaxPerAuthor: 2, Penalty: 1}}
+	require.Equal(t, []int64{1, 2, 4, 5}, ids(capped.Rank(candidates, now, 10)))
+
+	penalized := &Ranker{Scorer: scorer, Diversifier: AuthorDiversity{Penalty: 0.5}}
+	require.Equal(t, []int64{1, 4, 2, 5, 3}, ids(penalized.Rank(candidates, now, 10)))
+
+	//equal scores go to the newest tweet
+	require.Equal(t, []int64{5, 4}, ids(plain.Rank([]Candidate{{TweetID: 4}, {TweetID: 5}}, now, 2)))
+}
@@ -0,0 +1,59 @@
+package ranking
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// replaySession is a recorded home timeline: the candidates a viewer had at now and
+// the page the default ranker is expected to show them
+type replaySession struct {
+	Description string      `json:"description"`
+	Now         time.Time   `json:"now"`
+	Limit       int         `json:"limit"`
+	Candidates  []Candidate `json:"candidates"`
+	Expected    []int64     `json:"expected"`
+}
+
+// TestReplay ranks the recorded sessions in testdata offline. A scorer change that
+// moves these pages should update the expected ids on purpose.
+func TestReplay(t *testing.T) {
+	files, err := filepath.Glob("testdata/replay_*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+
+		var session replaySession
+		require.NoError(t, json.Unmarshal(data, &session), file)
+
+		ranker := NewRanker()
+		page := ranker.Rank(session.Candidates, session.Now, session.Limit)
+
+		ids := make([]int64, len(page))
+		perAuthor := map[string]int{}
+		for i, scored := range page {
+			ids[i] = scored.TweetID
+			perAuthor[scored.Author]++
+		}
+		require.Equal(t, session.Expected, ids, "%v: %v", file, session.Description)
+
+		for author, count := range perAuthor {
+			require.LessOrEqual(t, count, 3, "%v: %v", file, author)
+		}
+
+		//the same input ranks the same, whatever order it comes in
+		reversed := make([]Candidate, len(session.Candidates))
+		for i, candidate := range session.Candidates {
+			reversed[len(reversed)-1-i] = candidate
+		}
+		require.Equal(t, page, ranker.Rank(reversed, session.Now, session.Limit), file)
+	}
+}
@@ -0,0 +1,90 @@
+package ranking
+
+import (
+	"math"
+	"time"
+)
+
+// Candidate is a tweet that may be shown in a ranked timeline, with the signals
+// scorers read
+type Candidate struct {
+	TweetID   int64     `json:"tweet_id"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	Likes     int64     `json:"likes"`
+	Replies   int64     `json:"replies"`
+	Retweets  int64     `json:"retweets"`
+	// Affinity is how many times the viewer recently interacted with the author
+	Affinity int64 `json:"affinity"`
+}
+
+// Scorer rates a candidate, higher is better. Scores are only compared with the
+// ones of the same scorer.
+type Scorer interface {
+	Score(candidate Candidate, now time.Time) float64
+}
+
+// ScorerFunc lets a plain function be used as a Scorer
+type ScorerFunc func(candidate Candidate, now time.Time) float64
+
+func (f ScorerFunc) Score(candidate Candidate, now time.Time) float64 {
+	return f(candidate, now)
+}
+
+// RecencyDecay halves the score of a tweet every HalfLife
+type RecencyDecay struct {
+	HalfLife time.Duration
+}
+
+func (r RecencyDecay) Score(candidate Candidate, now time.Time) float64 {
+	age := now.Sub(candidate.CreatedAt)
+	if age < 0 || r.HalfLife <= 0 {
+		return 1
+	}
+	return math.Exp2(-float64(age) / float64(r.HalfLife))
+}
+
+// Engagement grows with the weighted likes, replies and retweets of a tweet,
+// logarithmically so a viral tweet doesn't drown everything else
+type Engagement struct {
+	LikeWeight    float64
+	ReplyWeight   float64
+	RetweetWeight float64
+}
+
+func (e Engagement) Score(candidate Candidate, now time.Time) float64 {
+	weighted := e.LikeWeight*float64(candidate.Likes) +
+		e.ReplyWeight*float64(candidate.Replies) +
+		e.RetweetWeight*float64(candidate.Retweets)
+	return 1 + math.Log1p(math.Max(weighted, 0))
+}
+
+// AuthorAffinity boosts the authors the viewer interacts with
+type AuthorAffinity struct {
+	Weight float64
+}
+
+func (a AuthorAffinity) Score(candidate Candidate, now time.Time) float64 {
+	return 1 + a.Weight*math.Log1p(math.Max(float64(candidate.Affinity), 0))
+}
+
+// Product multiplies the scores of its scorers, each one scales the others
+type Product []Scorer
+
+func (p Product) Score(candidate Candidate, now time.Time) float64 {
+	score := 1.0
+	for _, scorer := range p {
+		score *= scorer.Score(candidate, now)
+	}
+	return score
+}
+
+// DefaultScorer weighs engagement and affinity by a decay of a few hours, replies
+// and retweets cost more effort than a like and count more
+func DefaultScorer() Scorer {
+	return Product{
+		RecencyDecay{HalfLife: 6 * time.Hour},
+		Engagement{LikeWeight: 1, ReplyWeight: 3, RetweetWeight: 2},
+		AuthorAffinity{Weight: 0.5},
+	}
+}
@@ -0,0 +1,42 @@
+package ranking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecencyDecay(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	decay := RecencyDecay{HalfLife: time.Hour}
+
+	require.Equal(t, 1.0, decay.Score(Candidate{CreatedAt: now}, now))
+	require.InDelta(t, 0.5, decay.Score(Candidate{CreatedAt: now.Add(-time.Hour)}, now), 1e-9)
+	require.InDelta(t, 0.25, decay.Score(Candidate{CreatedAt: now.Add(-2 * time.Hour)}, now), 1e-9)
+	//clock skew doesn't boost a tweet from the future
+	require.Equal(t, 1.0, decay.Score(Candidate{CreatedAt: now.Add(time.Minute)}, now))
+}
+
+func TestEngagement(t *testing.T) {
+	now := time.Now()
+	engagement := Engagement{LikeWeight: 1, ReplyWeight: 3, RetweetWeight: 2}
+
+	require.Equal(t, 1.0, engagement.Score(Candidate{}, now))
+	require.Equal(t, engagement.Score(Candidate{Likes: 3}, now), engagement.Score(Candidate{Replies: 1}, now))
+	require.Greater(t, engagement.Score(Candidate{Retweets: 2}, now), engagement.Score(Candidate{Likes: 3}, now))
+	//a hundred times the likes is far from a hundred times the score
+	require.Less(t, engagement.Score(Candidate{Likes: 10000}, now), 3*engagement.Score(Candidate{Likes: 100}, now))
+}
+
+func TestProduct(t *testing.T) {
+	now := time.Now()
+	constant := func(score float64) Scorer {
+		return ScorerFunc(func(Candidate, time.Time) float64 { return score })
+	}
+
+	require.Equal(t, 1.0, Product{}.Score(Candidate{}, now))
+	require.Equal(t, 6.0, Product{constant(2), constant(3)}.Score(Candidate{}, now))
+	require.Equal(t, 1.0, AuthorAffinity{Weight: 0.5}.Score(Candidate{}, now))
+	require.Greater(t, AuthorAffinity{Weight: 0.5}.Score(Candidate{Affinity: 10}, now), 2.0)
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows limit calls per key in fixed windows. Counts live in memory, every
+// server process keeps its own.
+type Limiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	counters  map[string]*counter
+	lastSweep time.Time
+	// Now is the clock windows are measured with, time.Now when nil
+	Now func() time.Time
+}
+
+type counter struct {
+	start time.Time
+	count int
+}
+
+func NewLimiter(limit int, window time.Duration) *Limiter {
+	return &Limiter{limit: limit, window: window, counters: map[string]*counter{}}
+}
+
+// Allow counts a call for key and tells whether it's within the limit, calls over
+// it aren't counted
+func (l *Limiter) Allow(key string) bool {
+	now := time.Now
+	if l.Now != nil {
+		now = l.Now
+	}
+	at := now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(at)
+
+	c, ok := l.counters[key]
+	if !ok || at.Sub(c.start) >= l.window {
+		c = &counter{start: at}
+		l.counters[key] = c
+	}
+	if c.count >= l.limit {
+		return false
+	}
+	c.count++
+	return true
+}
+
+// sweep drops the counters of windows that ended, once per window so keys seen once
+// don't pile up
+func (l *Limiter) sweep(at time.Time) {
+	if at.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = at
+	for key, c := range l.counters {
+		if at.Sub(c.start) >= l.window {
+			delete(l.counters, key)
+		}
+	}
+}
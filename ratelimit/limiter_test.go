@@ -0,0 +1,26 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewLimiter(2, time.Minute)
+	limiter.Now = func() time.Time { return now }
+
+	require.True(t, limiter.Allow("alice:bob"))
+	require.True(t, limiter.Allow("alice:bob"))
+	require.False(t, limiter.Allow("alice:bob"))
+
+	// keys are counted apart
+	require.True(t, limiter.Allow("carol:bob"))
+
+	// the next window starts over
+	now = now.Add(time.Minute)
+	require.True(t, limiter.Allow("alice:bob"))
+	require.Len(t, limiter.counters, 1)
+}
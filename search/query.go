@@ -0,0 +1,148 @@
+// Package search parses the queries of tweet search. Free text is left to
+// Postgres' websearch_to_tsquery, operators are pulled out into filters.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format of since: and until: dates, days are UTC
+const DateLayout = "2006-01-02"
+
+var (
+	// ErrEmptyQuery is a query with nothing narrowing it to some tweets, the other
+	// filters alone would scan them all
+	ErrEmptyQuery = errors.New("search query needs a word, a #hashtag or from:user")
+
+	hashtagPattern  = regexp.MustCompile(`^#([\p{L}\p{N}_]+)$`)
+	usernamePattern = regexp.MustCompile(`^@?([A-Za-z0-9_]{1,30})$`)
+
+	// maxUntil stands for no upper bound, far past any tweet
+	maxUntil = time.Date(9999, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Query is a parsed search query. Terms keeps the words, "quoted phrases", OR and
+// -excluded words as typed, Hashtags are lowercased without their #.
+type Query struct {
+	Terms    string
+	Hashtags []string
+	From     string
+	Since    time.Time
+	Until    time.Time
+	MinLikes int32
+}
+
+// Parse splits raw into terms and operators:
+//
+//	from:user         tweets of user
+//	#tag              tweets with the hashtag, not just the word
+//	since:2022-01-31  tweets from that day on
+//	until:2022-01-31  tweets before that day
+//	min_likes:10      tweets with at least 10 likes
+//
+// Words that only look like operators are searched as they are. A query needs a
+// word, a hashtag or from:, the other operators only filter.
+func Parse(raw string) (Query, error) {
+	var q Query
+	var terms []string
+
+	for _, token := range tokenize(raw) {
+		if strings.HasPrefix(token, `"`) {
+			terms = append(terms, token)
+			continue
+		}
+		if match := hashtagPattern.FindStringSubmatch(token); match != nil {
+			q.Hashtags = append(q.Hashtags, strings.ToLower(match[1]))
+			continue
+		}
+
+		name, value := token, ""
+		if i := strings.Index(token, ":"); i > 0 {
+			name, value = token[:i], token[i+1:]
+		}
+		switch strings.ToLower(name) {
+		case "from":
+			match := usernamePattern.FindStringSubmatch(value)
+			if match == nil {
+				return q, fmt.Errorf("from: needs a username, got %q", value)
+			}
+			q.From = match[1]
+		case "since", "until":
+			day, err := time.Parse(DateLayout, value)
+			if err != nil {
+				return q, fmt.Errorf("%s: needs a date like %s, got %q", name, DateLayout, value)
+			}
+			if strings.ToLower(name) == "since" {
+				q.Since = day
+			} else {
+				q.Until = day
+			}
+		case "min_likes":
+			likes, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || likes < 0 {
+				return q, fmt.Errorf("min_likes: needs a number of likes, got %q", value)
+			}
+			q.MinLikes = int32(likes)
+		default:
+			terms = append(terms, token)
+		}
+	}
+
+	q.Terms = strings.Join(terms, " ")
+	if q.Terms == "" && len(q.Hashtags) == 0 && q.From == "" {
+		return q, ErrEmptyQuery
+	}
+	return q, nil
+}
+
+// tokenize splits on spaces, keeping "quoted phrases" whole with their quotes. An
+// unterminated quote runs to the end.
+func tokenize(raw string) []string {
+	var tokens []string
+	for raw = strings.TrimSpace(raw); raw != ""; raw = strings.TrimSpace(raw) {
+		end := strings.IndexAny(raw, " \t\n")
+		if raw[0] == '"' {
+			end = strings.Index(raw[1:], `"`)
+			if end >= 0 {
+				end += 2
+			}
+		}
+		if end < 0 {
+			end = len(raw)
+		}
+		tokens = append(tokens, raw[:end])
+		raw = raw[end:]
+	}
+	return tokens
+}
+
+// MatchAll tells there's nothing to match the text against, only from: and filters
+func (q Query) MatchAll() bool {
+	return q.Terms == "" && len(q.Hashtags) == 0
+}
+
+// HashtagQuery is the tsquery matching all the hashtags, they're indexed with
+// their # so they don't match the plain word. Hashtags only hold letters, digits
+// and underscores, nothing to escape.
+func (q Query) HashtagQuery() string {
+	lexemes := make([]string, len(q.Hashtags))
+	for i, tag := range q.Hashtags {
+		lexemes[i] = "'#" + tag + "'"
+	}
+	return strings.Join(lexemes, " & ")
+}
+
+// Window is the created_at range searched, since included and until left out.
+// Bounds that weren't given are wide open.
+func (q Query) Window() (since, until time.Time) {
+	until = q.Until
+	if until.IsZero() {
+		until = maxUntil
+	}
+	return q.Since, until
+}
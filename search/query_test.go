@@ -0,0 +1,68 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`go "error handling" #Golang from:@gopher since:2022-03-01 until:2022-04-01 min_likes:5 -java`)
+	require.NoError(t, err)
+	require.Equal(t, `go "error handling" -java`, q.Terms)
+	require.Equal(t, []string{"golang"}, q.Hashtags)
+	require.Equal(t, "gopher", q.From)
+	require.Equal(t, time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC), q.Since)
+	require.Equal(t, time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC), q.Until)
+	require.Equal(t, int32(5), q.MinLikes)
+	require.False(t, q.MatchAll())
+}
+
+func TestParseOperatorsOnly(t *testing.T) {
+	q, err := Parse("from:gopher min_likes:0 since:2022-03-01")
+	require.NoError(t, err)
+	require.True(t, q.MatchAll())
+
+	since, until := q.Window()
+	require.Equal(t, q.Since, since)
+	require.True(t, until.After(time.Now()))
+}
+
+func TestParsePlainWords(t *testing.T) {
+	// words that only look like operators are searched for
+	q, err := Parse(`"unterminated phrase to:someone # http://example.com`)
+	require.NoError(t, err)
+	require.Equal(t, `"unterminated phrase to:someone # http://example.com`, q.Terms)
+	require.Empty(t, q.Hashtags)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"   ",
+		"min_likes:0",
+		"since:2022-03-01 until:2022-04-01",
+		"from:",
+		"from:not-a-username",
+		"since:yesterday",
+		"until:2022-13-01",
+		"min_likes:-1",
+		"min_likes:many",
+	} {
+		_, err := Parse(raw)
+		require.Error(t, err, raw)
+	}
+
+	for _, raw := range []string{" ", "min_likes:0 since:2022-03-01"} {
+		_, err := Parse(raw)
+		require.ErrorIs(t, err, ErrEmptyQuery, raw)
+	}
+}
+
+func TestHashtagQuery(t *testing.T) {
+	q, err := Parse("#go #Rust_lang")
+	require.NoError(t, err)
+	require.Equal(t, "'#go' & '#rust_lang'", q.HashtagQuery())
+	require.Equal(t, "", Query{}.HashtagQuery())
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ahmadfarhanstwn/twitter_wannabe/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	key := util.GetRandomString(10) + ".jpg"
+	data := []byte(util.GetRandomString(100))
+
+	err = store.Put(context.Background(), key, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	r, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	got, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, data, got)
+
+	err = store.Delete(context.Background(), key)
+	require.NoError(t, err)
+
+	_, err = store.Get(context.Background(), key)
+	require.ErrorIs(t, err, ErrBlobNotFound)
+
+	//deleting twice is fine
+	require.NoError(t, store.Delete(context.Background(), key))
+}
+
+func TestLocalBlobStoreInvalidKey(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../secret", "a/b", `a\b`} {
+		err = store.Put(context.Background(), key, bytes.NewReader([]byte("x")))
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,232 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrSlowConsumer ends a subscription whose buffer filled up, the client should
+	// reconnect from its last event id
+	ErrSlowConsumer = errors.New("subscriber is too slow, events were dropped")
+	// ErrHubClosed ends the subscriptions when the hub shuts down
+	ErrHubClosed = errors.New("hub is closed")
+)
+
+// Event is a message published on a topic. IDs increase across the whole hub so a
+// subscriber can resume after the last one it saw.
+type Event struct {
+	ID    uint64      `json:"id"`
+	Topic string      `json:"topic"`
+	Type  string      `json:"type"`
+	Data  interface{} `json:"data"`
+}
+
+// Hub is an in-process pub/sub. It keeps the last events published so subscribers
+// reconnecting shortly after a drop miss nothing.
+type Hub struct {
+	mu         sync.Mutex
+	seq        uint64
+	topics     map[string]map[*Subscription]struct{}
+	history    []Event
+	historyCap int
+	bufferSize int
+	closed     bool
+}
+
+// NewHub creates a hub giving each subscriber bufferSize events of slack and
+// keeping the last historySize events for resuming
+func NewHub(bufferSize, historySize int) *Hub {
+	return &Hub{
+		topics:     map[string]map[*Subscription]struct{}{},
+		historyCap: historySize,
+		bufferSize: bufferSize,
+	}
+}
+
+// Publish never blocks, subscribers that can't keep up are dropped with
+// ErrSlowConsumer instead of slowing down the publisher
+func (h *Hub) Publish(topic, eventType string, data interface{}) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	event := Event{ID: h.seq, Topic: topic, Type: eventType, Data: data}
+	if h.historyCap > 0 {
+		if len(h.history) == h.historyCap {
+			copy(h.history, h.history[1:])
+			h.history = h.history[:len(h.history)-1]
+		}
+		h.history = append(h.history, event)
+	}
+
+	for sub := range h.topics[topic] {
+		select {
+		case sub.events <- event:
+		default:
+			h.unsubscribe(sub, ErrSlowConsumer)
+		}
+	}
+	return event
+}
+
+// Subscribe listens to topics. A non zero lastEventID replays the retained events
+// published after it, Missed tells when some were already forgotten.
+func (h *Hub) Subscribe(topics []string, lastEventID uint64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{
+		hub:    h,
+		topics: map[string]bool{},
+		done:   make(chan struct{}),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+	if h.closed {
+		sub.events = make(chan Event)
+		sub.end(ErrHubClosed)
+		return sub
+	}
+
+	replay := []Event{}
+	if lastEventID > 0 {
+		if lastEventID > h.seq {
+			//ids from before a restart mean nothing now
+			sub.missed = true
+		} else if len(h.history) == 0 || h.history[0].ID > lastEventID+1 {
+			sub.missed = lastEventID < h.seq
+		}
+		for _, event := range h.history {
+			if event.ID > lastEventID && sub.topics[event.Topic] {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	//the replay always fits, live events get the usual slack on top
+	sub.events = make(chan Event, len(replay)+h.bufferSize)
+	for _, event := range replay {
+		sub.events <- event
+	}
+	for topic := range sub.topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = map[*Subscription]struct{}{}
+		}
+		h.topics[topic][sub] = struct{}{}
+	}
+	return sub
+}
+
+// Close ends every subscription with ErrHubClosed, later ones end right away
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.topics {
+		for sub := range subs {
+			h.unsubscribe(sub, ErrHubClosed)
+		}
+	}
+}
+
+// unsubscribe must be called with h.mu held
+func (h *Hub) unsubscribe(sub *Subscription, err error) {
+	for topic := range sub.topics {
+		delete(h.topics[topic], sub)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+	sub.end(err)
+}
+
+// Subscription receives the events of its topics until it's closed or dropped
+type Subscription struct {
+	hub    *Hub
+	topics map[string]bool
+	events chan Event
+	missed bool
+
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// Events delivers the events in publishing order
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Done is closed when the subscription ends, Err tells why. Events still buffered
+// can be read after that.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Missed is set when the events after the requested last event id weren't all
+// retained, the client should reload instead of trusting the replay
+func (s *Subscription) Missed() bool {
+	return s.missed
+}
+
+// Add listens to more topics, what they published before isn't replayed. It does
+// nothing once the subscription ended.
+func (s *Subscription) Add(topics ...string) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	for _, topic := range topics {
+		if s.topics[topic] {
+			continue
+		}
+		s.topics[topic] = true
+		if s.hub.topics[topic] == nil {
+			s.hub.topics[topic] = map[*Subscription]struct{}{}
+		}
+		s.hub.topics[topic][s] = struct{}{}
+	}
+}
+
+// Remove stops listening to topics, their events already buffered are still delivered
+func (s *Subscription) Remove(topics ...string) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	for _, topic := range topics {
+		if !s.topics[topic] {
+			continue
+		}
+		delete(s.topics, topic)
+		delete(s.hub.topics[topic], s)
+		if len(s.hub.topics[topic]) == 0 {
+			delete(s.hub.topics, topic)
+		}
+	}
+}
+
+// Close unsubscribes, it's safe to call more than once
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.unsubscribe(s, nil)
+}
+
+func (s *Subscription) end(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
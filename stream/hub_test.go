@@ -0,0 +1,139 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, sub *Subscription) Event {
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	hub := NewHub(4, 16)
+
+	sub := hub.Subscribe([]string{"tweets:alice", "followers:bob"}, 0)
+	defer sub.Close()
+	other := hub.Subscribe([]string{"tweets:carol"}, 0)
+	defer other.Close()
+
+	hub.Publish("tweets:alice", "tweet", "hello")
+	hub.Publish("tweets:carol", "tweet", "not for sub")
+	hub.Publish("followers:bob", "follow", "alice")
+
+	first := receive(t, sub)
+	require.Equal(t, Event{ID: 1, Topic: "tweets:alice", Type: "tweet", Data: "hello"}, first)
+	second := receive(t, sub)
+	require.Equal(t, uint64(3), second.ID)
+	require.Equal(t, "follow", second.Type)
+	require.Empty(t, sub.Events())
+
+	require.Equal(t, uint64(2), receive(t, other).ID)
+
+	//a closed subscription gets nothing more
+	sub.Close()
+	sub.Close()
+	require.NoError(t, sub.Err())
+	hub.Publish("tweets:alice", "tweet", "after close")
+	require.Empty(t, sub.Events())
+}
+
+func TestHubResume(t *testing.T) {
+	hub := NewHub(4, 3)
+	for i := 0; i < 4; i++ {
+		hub.Publish("tweets:alice", "tweet", i)
+	}
+	hub.Publish("tweets:carol", "tweet", "other topic")
+
+	//events 3 to 5 are retained, 2 was forgotten
+	sub := hub.Subscribe([]string{"tweets:alice"}, 2)
+	require.False(t, sub.Missed())
+	require.Equal(t, uint64(3), receive(t, sub).ID)
+	require.Equal(t, uint64(4), receive(t, sub).ID)
+	require.Empty(t, sub.Events())
+	sub.Close()
+
+	sub = hub.Subscribe([]string{"tweets:alice"}, 1)
+	require.True(t, sub.Missed())
+	require.Equal(t, uint64(3), receive(t, sub).ID)
+	sub.Close()
+
+	//caught up already
+	sub = hub.Subscribe([]string{"tweets:alice"}, 5)
+	require.False(t, sub.Missed())
+	require.Empty(t, sub.Events())
+	sub.Close()
+
+	//an id the hub never gave out, from before a restart
+	sub = hub.Subscribe([]string{"tweets:alice"}, 42)
+	require.True(t, sub.Missed())
+	sub.Close()
+}
+
+func TestHubSlowConsumer(t *testing.T) {
+	hub := NewHub(2, 8)
+	slow := hub.Subscribe([]string{"tweets:alice"}, 0)
+	fast := hub.Subscribe([]string{"tweets:alice"}, 0)
+
+	for i := 0; i < 3; i++ {
+		hub.Publish("tweets:alice", "tweet", i)
+		receive(t, fast)
+	}
+
+	//the publisher didn't wait, the slow one was dropped with what it had buffered
+	<-slow.Done()
+	require.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+	require.Len(t, slow.Events(), 2)
+
+	select {
+	case <-fast.Done():
+		t.Fatal("fast subscriber was dropped")
+	default:
+	}
+
+	//reconnecting from the last event it handled catches up from history
+	resumed := hub.Subscribe([]string{"tweets:alice"}, 1)
+	require.Equal(t, uint64(2), receive(t, resumed).ID)
+	require.Equal(t, uint64(3), receive(t, resumed).ID)
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub(2, 8)
+	sub := hub.Subscribe([]string{"tweets:alice"}, 0)
+
+	hub.Close()
+	require.ErrorIs(t, sub.Err(), ErrHubClosed)
+	require.ErrorIs(t, hub.Subscribe([]string{"tweets:alice"}, 0).Err(), ErrHubClosed)
+}
+
+func TestSubscriptionAddRemove(t *testing.T) {
+	hub := NewHub(4, 16)
+	sub := hub.Subscribe([]string{"tweets:alice"}, 0)
+	defer sub.Close()
+
+	hub.Publish("tweets:bob", "tweet", "before add")
+	sub.Add("tweets:bob", "tweets:alice")
+	hub.Publish("tweets:bob", "tweet", "after add")
+	require.Equal(t, "after add", receive(t, sub).Data)
+
+	sub.Remove("tweets:bob", "tweets:carol")
+	hub.Publish("tweets:bob", "tweet", "after remove")
+	hub.Publish("tweets:alice", "tweet", "still subscribed")
+	require.Equal(t, "still subscribed", receive(t, sub).Data)
+	require.Empty(t, sub.Events())
+
+	//an ended subscription isn't listening again
+	sub.Close()
+	sub.Add("tweets:bob")
+	hub.Publish("tweets:bob", "tweet", "after close")
+	require.Empty(t, sub.Events())
+	require.Empty(t, hub.topics)
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
@@ -12,8 +13,41 @@ type Config struct {
 	Server_Address string `mapstructure:"SERVER_ADDRESS"`
 	Access_Token string `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	Token_Duration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	Media_Path string `mapstructure:"MEDIA_PATH"`
+	Max_Media_Size int64 `mapstructure:"MAX_MEDIA_SIZE"`
+	Tweet_Edit_Window time.Duration `mapstructure:"TWEET_EDIT_WINDOW"`
+	Scheduler_Interval time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	Poll_Close_Interval time.Duration `mapstructure:"POLL_CLOSE_INTERVAL"`
+	Timeline_Fanout_Interval time.Duration `mapstructure:"TIMELINE_FANOUT_INTERVAL"`
+	Celebrity_Follower_Threshold int32 `mapstructure:"CELEBRITY_FOLLOWER_THRESHOLD"`
+	Timeline_Retention time.Duration `mapstructure:"TIMELINE_RETENTION"`
+	For_You_Window time.Duration `mapstructure:"FOR_YOU_WINDOW"`
+	Stream_Buffer_Size int `mapstructure:"STREAM_BUFFER_SIZE"`
+	Stream_History_Size int `mapstructure:"STREAM_HISTORY_SIZE"`
+	Stream_Heartbeat_Interval time.Duration `mapstructure:"STREAM_HEARTBEAT_INTERVAL"`
+	Shutdown_Timeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	Webhook_Interval time.Duration `mapstructure:"WEBHOOK_INTERVAL"`
+	Webhook_Timeout time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	Webhook_Max_Attempts int32 `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	Webhook_Backoff time.Duration `mapstructure:"WEBHOOK_BACKOFF"`
+	Webhook_Max_Backoff time.Duration `mapstructure:"WEBHOOK_MAX_BACKOFF"`
+	Outbox_Interval time.Duration `mapstructure:"OUTBOX_INTERVAL"`
+	Outbox_Retention time.Duration `mapstructure:"OUTBOX_RETENTION"`
+	Outbox_Publisher string `mapstructure:"OUTBOX_PUBLISHER"`
+	Outbox_Broker_URL string `mapstructure:"OUTBOX_BROKER_URL"`
+	Outbox_Topic string `mapstructure:"OUTBOX_TOPIC"`
+	Outbox_Timeout time.Duration `mapstructure:"OUTBOX_TIMEOUT"`
+	Prekey_Bundle_Limit int `mapstructure:"PREKEY_BUNDLE_LIMIT"`
+	Prekey_Bundle_Window time.Duration `mapstructure:"PREKEY_BUNDLE_WINDOW"`
 }
 
+// outbox publishers OUTBOX_PUBLISHER picks from
+const (
+	OutboxPublisherMemory = "memory"
+	OutboxPublisherNATS   = "nats"
+	OutboxPublisherKafka  = "kafka"
+)
+
 func LoadConfig(path string) (config Config, err error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName("app")
@@ -27,5 +61,74 @@ func LoadConfig(path string) (config Config, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	err = config.checkIntervals()
+	if err != nil {
+		return
+	}
+
+	// at zero every author would be a celebrity read by fan-in
+	if config.Celebrity_Follower_Threshold <= 0 {
+		err = fmt.Errorf("CELEBRITY_FOLLOWER_THRESHOLD must be positive, got %v", config.Celebrity_Follower_Threshold)
+		return
+	}
+
+	err = config.checkOutboxPublisher()
+	if err != nil {
+		return
+	}
+
+	if config.Prekey_Bundle_Limit <= 0 || config.Prekey_Bundle_Window <= 0 {
+		err = fmt.Errorf("PREKEY_BUNDLE_LIMIT and PREKEY_BUNDLE_WINDOW must be positive, got %v and %v",
+			config.Prekey_Bundle_Limit, config.Prekey_Bundle_Window)
+	}
 	return
+}
+
+// checkIntervals rejects intervals left unset or negative, the tickers driving the
+// workers and heartbeats panic on them, an empty retention prunes every home timeline
+// and an empty For You window finds no candidates
+func (config Config) checkIntervals() error {
+	intervals := []struct {
+		key      string
+		interval time.Duration
+	}{
+		{"SCHEDULER_INTERVAL", config.Scheduler_Interval},
+		{"POLL_CLOSE_INTERVAL", config.Poll_Close_Interval},
+		{"TIMELINE_FANOUT_INTERVAL", config.Timeline_Fanout_Interval},
+		{"TIMELINE_RETENTION", config.Timeline_Retention},
+		{"FOR_YOU_WINDOW", config.For_You_Window},
+		{"STREAM_HEARTBEAT_INTERVAL", config.Stream_Heartbeat_Interval},
+		{"WEBHOOK_INTERVAL", config.Webhook_Interval},
+		{"OUTBOX_INTERVAL", config.Outbox_Interval},
+	}
+	for _, i := range intervals {
+		if i.interval <= 0 {
+			return fmt.Errorf("%s must be a positive duration, got %v", i.key, i.interval)
+		}
+	}
+	return nil
+}
+
+// checkOutboxPublisher makes the outbox publisher an explicit choice, the relay marks
+// events published once they're handed over
+func (config Config) checkOutboxPublisher() error {
+	switch config.Outbox_Publisher {
+	case OutboxPublisherMemory:
+		return nil
+	case OutboxPublisherNATS, OutboxPublisherKafka:
+		if config.Outbox_Broker_URL == "" || config.Outbox_Topic == "" {
+			return fmt.Errorf("OUTBOX_PUBLISHER=%s needs OUTBOX_BROKER_URL and OUTBOX_TOPIC", config.Outbox_Publisher)
+		}
+		if config.Outbox_Timeout <= 0 {
+			return fmt.Errorf("OUTBOX_TIMEOUT must be a positive duration, got %v", config.Outbox_Timeout)
+		}
+		return nil
+	default:
+		return fmt.Errorf("OUTBOX_PUBLISHER must be %s, %s or %s, got %q",
+			OutboxPublisherNATS, OutboxPublisherKafka, OutboxPublisherMemory, config.Outbox_Publisher)
+	}
 }
\ No newline at end of file
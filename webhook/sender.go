@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Delivery is one event posted to one webhook
+type Delivery struct {
+	ID     int64
+	URL    string
+	Secret string
+	Event  string
+	Body   []byte
+}
+
+// StatusError is returned when the receiver answers outside 2xx
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook receiver answered %v", e.StatusCode)
+}
+
+// Sender posts signed deliveries
+type Sender struct {
+	Client *http.Client
+	// Now is the clock signatures are timestamped with, time.Now when nil
+	Now func() time.Time
+	// Resolver looks up the hosts of URLs being checked, net.DefaultResolver when nil
+	Resolver Resolver
+}
+
+// NewSender only delivers to public addresses, a redirect is a failed delivery
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{Client: newClient(timeout, PublicIP)}
+}
+
+// Send posts the delivery and returns the receiver's status code, zero when it
+// couldn't be reached. Any status outside 2xx, redirects included, is a *StatusError.
+func (s *Sender) Send(ctx context.Context, delivery Delivery) (int, error) {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	timestamp := now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "twitter-wannabe-webhooks/1")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	//drain a little so the connection can be reused, receivers have nothing to say
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &StatusError{StatusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff is how long to wait after the attempt-th failed attempt: base, doubled
+// every attempt, up to max
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempt && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
@@ -0,0 +1,171 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// loopbackSender reaches the httptest receivers NewSender would refuse
+func loopbackSender() *Sender {
+	return &Sender{Client: newClient(time.Second, func(net.IP) bool { return true })}
+}
+
+func TestSendSignsDeliveries(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event":"like.created"}`)
+
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, body, got)
+		require.True(t, Verify("secret", r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), got, time.Minute, now))
+		require.False(t, Verify("other secret", r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), got, time.Minute, now))
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	sender := loopbackSender()
+	sender.Now = func() time.Time { return now }
+	status, err := sender.Send(context.Background(), Delivery{
+		ID:     42,
+		URL:    receiver.URL,
+		Secret: "secret",
+		Event:  "like.created",
+		Body:   body,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, status)
+
+	r := <-received
+	require.Equal(t, http.MethodPost, r.Method)
+	require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	require.Equal(t, "like.created", r.Header.Get(EventHeader))
+	require.Equal(t, "42", r.Header.Get(DeliveryHeader))
+	require.Equal(t, "1654084800", r.Header.Get(TimestampHeader))
+}
+
+func TestSendFailures(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	sender := loopbackSender()
+	status, err := sender.Send(context.Background(), Delivery{URL: receiver.URL, Body: []byte("{}")})
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+
+	//nobody listening anymore
+	receiver.Close()
+	status, err = sender.Send(context.Background(), Delivery{URL: receiver.URL, Body: []byte("{}")})
+	require.Error(t, err)
+	require.Zero(t, status)
+}
+
+func TestSendRefusesNonPublicAddresses(t *testing.T) {
+	hit := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer receiver.Close()
+
+	sender := NewSender(time.Second)
+	for _, url := range []string{receiver.URL, "http://10.0.0.1/hooks", "http://169.254.169.254/latest/meta-data"} {
+		status, err := sender.Send(context.Background(), Delivery{URL: url, Body: []byte("{}")})
+		require.True(t, errors.Is(err, ErrForbiddenAddress), url)
+		require.Zero(t, status)
+	}
+	require.False(t, hit)
+}
+
+func TestSendRefusesRedirects(t *testing.T) {
+	hit := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer internal.Close()
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	status, err := loopbackSender().Send(context.Background(), Delivery{URL: receiver.URL, Body: []byte("{}")})
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.Equal(t, http.StatusTemporaryRedirect, status)
+	require.False(t, hit)
+}
+
+type staticResolver map[string][]net.IPAddr
+
+func (r staticResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestCheckURL(t *testing.T) {
+	sender := NewSender(time.Second)
+	sender.Resolver = staticResolver{
+		"hooks.example.com": {{IP: net.ParseIP("93.184.216.34")}},
+		"localhost":         {{IP: net.ParseIP("127.0.0.1")}},
+		"mixed.example.com": {{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("192.168.1.10")}},
+	}
+
+	testcases := []struct {
+		url string
+		err error
+	}{
+		{url: "https://hooks.example.com/in", err: nil},
+		{url: "http://93.184.216.34:8080/in", err: nil},
+		{url: "ftp://hooks.example.com/in", err: ErrInvalidURL},
+		{url: "http://127.0.0.1/in", err: ErrForbiddenAddress},
+		{url: "http://[::1]/in", err: ErrForbiddenAddress},
+		{url: "http://10.1.2.3/in", err: ErrForbiddenAddress},
+		{url: "http://172.16.0.1/in", err: ErrForbiddenAddress},
+		{url: "http://169.254.169.254/latest/meta-data", err: ErrForbiddenAddress},
+		{url: "http://100.64.0.1/in", err: ErrForbiddenAddress},
+		{url: "http://[::ffff:127.0.0.1]/in", err: ErrForbiddenAddress},
+		{url: "http://localhost:8080/in", err: ErrForbiddenAddress},
+		{url: "http://mixed.example.com/in", err: ErrForbiddenAddress},
+	}
+	for _, testcase := range testcases {
+		require.Equal(t, testcase.err, sender.CheckURL(context.Background(), testcase.url), testcase.url)
+	}
+
+	require.Error(t, sender.CheckURL(context.Background(), "https://unknown.example.com/in"))
+}
+
+func TestVerifyRejectsStaleTimestamps(t *testing.T) {
+	now := time.Unix(1654084800, 0)
+	body := []byte("{}")
+	signature := Sign("secret", now.Unix(), body)
+
+	require.True(t, Verify("secret", signature, "1654084800", body, 5*time.Minute, now.Add(4*time.Minute)))
+	require.False(t, Verify("secret", signature, "1654084800", body, 5*time.Minute, now.Add(6*time.Minute)))
+	require.False(t, Verify("secret", signature, "1654084801", body, 5*time.Minute, now))
+	require.False(t, Verify("secret", "md5=abc", "1654084800", body, 5*time.Minute, now))
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, time.Hour
+	require.Equal(t, 30*time.Second, Backoff(1, base, max))
+	require.Equal(t, time.Minute, Backoff(2, base, max))
+	require.Equal(t, 4*time.Minute, Backoff(4, base, max))
+	require.Equal(t, 32*time.Minute, Backoff(7, base, max))
+	require.Equal(t, time.Hour, Backoff(8, base, max))
+	require.Equal(t, time.Hour, Backoff(100, base, max))
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/outbox"
+)
+
+const outboxBatchSize = 100
+
+// OutboxRelay publishes the outbox in order. Published events are kept for retention
+// before they're pruned.
+type OutboxRelay struct {
+	transaction database.Transaction
+	publisher   outbox.EventPublisher
+	interval    time.Duration
+	retention   time.Duration
+}
+
+func NewOutboxRelay(transaction database.Transaction, publisher outbox.EventPublisher, interval, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{transaction: transaction, publisher: publisher, interval: interval, retention: retention}
+}
+
+// Start polls until ctx is cancelled
+func (r *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil {
+			log.Printf("outbox relay : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes every unpublished event and returns how many were published
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	for ctx.Err() == nil {
+		n, err := r.transaction.RelayOutboxTx(ctx, outboxBatchSize, func(row database.Outbox) error {
+			return r.publisher.Publish(ctx, outbox.Event{
+				ID:        row.ID,
+				Type:      row.EventType,
+				Key:       row.AggregateKey,
+				Payload:   row.Payload,
+				CreatedAt: row.CreatedAt,
+			})
+		})
+		published += n
+		if err != nil {
+			return published, err
+		}
+		if n < outboxBatchSize {
+			break
+		}
+	}
+
+	if _, err := r.transaction.PrunePublishedOutboxEvents(ctx, time.Now().Add(-r.retention)); err != nil {
+		return published, err
+	}
+	return published, ctx.Err()
+}
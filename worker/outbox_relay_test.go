@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/outbox"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// relayRows stubs RelayOutboxTx over rows the way the transaction does: in order,
+// stopping at the first publish error
+func relayRows(rows []database.Outbox) func(context.Context, int32, func(database.Outbox) error) (int, error) {
+	return func(_ context.Context, _ int32, publish func(database.Outbox) error) (int, error) {
+		for i, row := range rows {
+			if err := publish(row); err != nil {
+				return i, err
+			}
+		}
+		return len(rows), nil
+	}
+}
+
+func TestOutboxRelayRelayPending(t *testing.T) {
+	rows := []database.Outbox{
+		{ID: 1, EventType: database.EventFollowCreated, AggregateKey: "follow:a:b", Payload: []byte(`{}`)},
+		{ID: 2, EventType: database.EventLikeCreated, AggregateKey: "like:a:1", Payload: []byte(`{}`)},
+		{ID: 3, EventType: database.EventFollowDeleted, AggregateKey: "follow:a:b", Payload: []byte(`{}`)},
+	}
+
+	publisher := outbox.NewMemoryPublisher()
+	var got []int64
+	publisher.Subscribe("", func(ctx context.Context, event outbox.Event) error {
+		got = append(got, event.ID)
+		return nil
+	})
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Eq(int32(outboxBatchSize)), gomock.Any()).Times(1).DoAndReturn(relayRows(rows))
+	transaction.EXPECT().PrunePublishedOutboxEvents(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			require.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Second)
+			return 0, nil
+		})
+
+	relay := NewOutboxRelay(transaction, publisher, 0, time.Hour)
+	published, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, published)
+	require.Equal(t, []int64{1, 2, 3}, got)
+}
+
+func TestOutboxRelayPublishFails(t *testing.T) {
+	rows := []database.Outbox{
+		{ID: 1, EventType: database.EventLikeCreated},
+		{ID: 2, EventType: database.EventLikeDeleted},
+	}
+
+	errDown := errors.New("broker down")
+	publisher := outbox.NewMemoryPublisher()
+	publisher.Subscribe(database.EventLikeDeleted, func(ctx context.Context, event outbox.Event) error {
+		return errDown
+	})
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(relayRows(rows))
+	transaction.EXPECT().PrunePublishedOutboxEvents(gomock.Any(), gomock.Any()).Times(0)
+
+	relay := NewOutboxRelay(transaction, publisher, 0, time.Hour)
+	published, err := relay.RelayPending(context.Background())
+	require.Equal(t, errDown, err)
+	require.Equal(t, 1, published)
+}
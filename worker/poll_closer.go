@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+)
+
+// PollCloser finalizes polls once their duration is over. Tallies are live while a poll
+// is open, closing recounts them from the votes so the final result is exact.
+type PollCloser struct {
+	transaction database.Transaction
+	interval    time.Duration
+}
+
+func NewPollCloser(transaction database.Transaction, interval time.Duration) *PollCloser {
+	return &PollCloser{transaction: transaction, interval: interval}
+}
+
+// Start polls until ctx is cancelled
+func (p *PollCloser) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.CloseDue(ctx); err != nil {
+			log.Printf("poll closer : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CloseDue finalizes every poll past its closing time and returns how many were closed
+func (p *PollCloser) CloseDue(ctx context.Context) (int, error) {
+	closed := 0
+	for ctx.Err() == nil {
+		_, err := p.transaction.ClosePollTx(ctx)
+		if err == database.ErrNoDuePoll {
+			return closed, nil
+		}
+		if err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, ctx.Err()
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollCloserCloseDue(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	gomock.InOrder(
+		transaction.EXPECT().ClosePollTx(gomock.Any()).Return(database.TweetPoll{}, nil),
+		transaction.EXPECT().ClosePollTx(gomock.Any()).Return(database.TweetPoll{}, nil),
+		transaction.EXPECT().ClosePollTx(gomock.Any()).Return(database.TweetPoll{}, database.ErrNoDuePoll),
+	)
+
+	closed, err := NewPollCloser(transaction, 0).CloseDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, closed)
+}
+
+func TestPollCloserCloseDueError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().ClosePollTx(gomock.Any()).Return(database.TweetPoll{}, sql.ErrConnDone)
+
+	closed, err := NewPollCloser(transaction, 0).CloseDue(context.Background())
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Zero(t, closed)
+}
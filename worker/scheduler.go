@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+)
+
+// Scheduler publishes scheduled tweets once they're due. Every replica can run one,
+// rows are claimed with SKIP LOCKED so a tweet is never published twice.
+type Scheduler struct {
+	transaction database.Transaction
+	interval    time.Duration
+	publish     func(ctx context.Context, created database.CreateTweetTxResult)
+}
+
+// NewScheduler's publish is called with every tweet once it's committed, so it's streamed
+// and notified like one posted right away
+func NewScheduler(transaction database.Transaction, interval time.Duration, publish func(ctx context.Context, created database.CreateTweetTxResult)) *Scheduler {
+	return &Scheduler{transaction: transaction, interval: interval, publish: publish}
+}
+
+// Start polls until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PublishDue(ctx); err != nil {
+			log.Printf("scheduler : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes every scheduled tweet that is due and returns how many went out
+func (s *Scheduler) PublishDue(ctx context.Context) (int, error) {
+	published := 0
+	for ctx.Err() == nil {
+		res, err := s.transaction.PublishScheduledTweetTx(ctx)
+		if err == database.ErrNoDueScheduledTweet {
+			return published, nil
+		}
+		if err != nil {
+			return published, err
+		}
+
+		if res.Failed {
+			log.Printf("scheduler : scheduled tweet %v failed : %v", res.Scheduled.ID, res.Scheduled.Error)
+			continue
+		}
+		s.publish(ctx, res.Published)
+		published++
+	}
+	return published, ctx.Err()
+}
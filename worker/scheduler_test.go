@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerPublishDue(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	gomock.InOrder(
+		transaction.EXPECT().PublishScheduledTweetTx(gomock.Any()).Return(scheduledResult(1), nil),
+		transaction.EXPECT().PublishScheduledTweetTx(gomock.Any()).Return(database.PublishScheduledTweetTxResult{Failed: true}, nil),
+		transaction.EXPECT().PublishScheduledTweetTx(gomock.Any()).Return(scheduledResult(2), nil),
+		transaction.EXPECT().PublishScheduledTweetTx(gomock.Any()).Return(database.PublishScheduledTweetTxResult{}, database.ErrNoDueScheduledTweet),
+	)
+
+	var streamed []int64
+	published, err := NewScheduler(transaction, 0, func(ctx context.Context, created database.CreateTweetTxResult) {
+		streamed = append(streamed, created.Tweet.ID)
+	}).PublishDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, published)
+	require.Equal(t, []int64{1, 2}, streamed)
+}
+
+func TestSchedulerPublishDueError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	gomock.InOrder(
+		transaction.EXPECT().PublishScheduledTweetTx(gomock.Any()).Return(database.PublishScheduledTweetTxResult{}, nil),
+		transaction.EXPECT().PublishScheduledTweetTx(gomock.Any()).Return(database.PublishScheduledTweetTxResult{}, sql.ErrConnDone),
+	)
+
+	published, err := NewScheduler(transaction, 0, ignorePublished).PublishDue(context.Background())
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Equal(t, 1, published)
+}
+
+func TestSchedulerStopsOnCancel(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().PublishScheduledTweetTx(gomock.Any()).Times(0)
+
+	published, err := NewScheduler(transaction, 0, ignorePublished).PublishDue(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, published)
+}
+
+func scheduledResult(tweetID int64) database.PublishScheduledTweetTxResult {
+	return database.PublishScheduledTweetTxResult{Published: database.CreateTweetTxResult{Tweet: database.Tweets{ID: tweetID}}}
+}
+
+func ignorePublished(ctx context.Context, created database.CreateTweetTxResult) {}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+)
+
+// TimelineFanout copies new tweets into their author's followers' home timelines.
+// Authors with at least celebrityThreshold followers are left to the read path.
+// Home timelines only keep tweets for retention.
+type TimelineFanout struct {
+	transaction        database.Transaction
+	interval           time.Duration
+	celebrityThreshold int32
+	retention          time.Duration
+}
+
+func NewTimelineFanout(transaction database.Transaction, interval time.Duration, celebrityThreshold int32, retention time.Duration) *TimelineFanout {
+	return &TimelineFanout{transaction: transaction, interval: interval, celebrityThreshold: celebrityThreshold, retention: retention}
+}
+
+// Start polls until ctx is cancelled
+func (f *TimelineFanout) Start(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := f.FanOutPending(ctx); err != nil {
+			log.Printf("timeline fanout : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FanOutPending handles every pending tweet and returns how many were handled, then
+// prunes the timeline entries and fan-in tweets past retention
+func (f *TimelineFanout) FanOutPending(ctx context.Context) (int, error) {
+	handled := 0
+	for ctx.Err() == nil {
+		_, err := f.transaction.FanoutTweetTx(ctx, f.celebrityThreshold)
+		if err == database.ErrNoPendingFanout {
+			break
+		}
+		if err != nil {
+			return handled, err
+		}
+		handled++
+	}
+	if ctx.Err() != nil {
+		return handled, ctx.Err()
+	}
+
+	expired := time.Now().Add(-f.retention)
+	if _, err := f.transaction.PruneExpiredHomeTimelines(ctx, expired); err != nil {
+		return handled, err
+	}
+	if _, err := f.transaction.PruneExpiredFanIns(ctx, expired); err != nil {
+		return handled, err
+	}
+	return handled, nil
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineFanoutFanOutPending(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	gomock.InOrder(
+		transaction.EXPECT().FanoutTweetTx(gomock.Any(), gomock.Eq(int32(500))).Return(database.TimelineFanouts{TweetID: 1}, nil),
+		transaction.EXPECT().FanoutTweetTx(gomock.Any(), gomock.Eq(int32(500))).Return(database.TimelineFanouts{TweetID: 2, FanIn: true}, nil),
+		transaction.EXPECT().FanoutTweetTx(gomock.Any(), gomock.Eq(int32(500))).Return(database.TimelineFanouts{}, database.ErrNoPendingFanout),
+	)
+
+	start := time.Now()
+	expired := func(_ context.Context, before time.Time) (int64, error) {
+		require.WithinDuration(t, start.Add(-time.Hour), before, 5*time.Second)
+		return 1, nil
+	}
+	transaction.EXPECT().PruneExpiredHomeTimelines(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(expired)
+	transaction.EXPECT().PruneExpiredFanIns(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(expired)
+
+	handled, err := NewTimelineFanout(transaction, 0, 500, time.Hour).FanOutPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, handled)
+}
+
+func TestTimelineFanoutFanOutPendingError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().FanoutTweetTx(gomock.Any(), gomock.Any()).Return(database.TimelineFanouts{}, sql.ErrConnDone)
+	transaction.EXPECT().PruneExpiredHomeTimelines(gomock.Any(), gomock.Any()).Times(0)
+
+	handled, err := NewTimelineFanout(transaction, 0, 500, time.Hour).FanOutPending(context.Background())
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Zero(t, handled)
+}
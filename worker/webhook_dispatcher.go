@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/webhook"
+)
+
+const webhookBatchSize = 50
+
+// WebhookDispatcher posts queued webhook deliveries. Failed ones are retried with
+// exponential backoff and moved to the dead-letter list after maxAttempts.
+type WebhookDispatcher struct {
+	transaction database.Transaction
+	sender      *webhook.Sender
+	interval    time.Duration
+	maxAttempts int32
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	// lease is how long a claimed delivery is hidden from other dispatchers
+	lease time.Duration
+}
+
+func NewWebhookDispatcher(transaction database.Transaction, sender *webhook.Sender, interval time.Duration, maxAttempts int32, backoff, maxBackoff time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		transaction: transaction,
+		sender:      sender,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		maxBackoff:  maxBackoff,
+		lease:       2*sender.Client.Timeout + time.Minute,
+	}
+}
+
+// Start polls until ctx is cancelled
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverPending(ctx); err != nil {
+			log.Printf("webhook dispatcher : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverPending attempts every due delivery and returns how many were attempted
+func (d *WebhookDispatcher) DeliverPending(ctx context.Context) (int, error) {
+	attempted := 0
+	for ctx.Err() == nil {
+		deliveries, err := d.transaction.ClaimWebhookDeliveries(ctx, database.ClaimWebhookDeliveriesParams{
+			LeaseUntil: time.Now().Add(d.lease),
+			BatchSize:  webhookBatchSize,
+		})
+		if err != nil {
+			return attempted, err
+		}
+
+		for _, delivery := range deliveries {
+			if err := d.deliver(ctx, delivery); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+		if len(deliveries) < webhookBatchSize {
+			return attempted, nil
+		}
+	}
+	return attempted, ctx.Err()
+}
+
+// deliver sends one delivery and records the outcome, only failing to record it is
+// an error
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery database.ClaimWebhookDeliveriesRow) error {
+	status, err := d.sender.Send(ctx, webhook.Delivery{
+		ID:     delivery.ID,
+		URL:    delivery.Url,
+		Secret: delivery.Secret,
+		Event:  delivery.Event,
+		Body:   delivery.Payload,
+	})
+	lastStatus := sql.NullInt32{Int32: int32(status), Valid: status != 0}
+	if err == nil {
+		return d.transaction.MarkWebhookDelivered(ctx, database.MarkWebhookDeliveredParams{
+			ID:         delivery.ID,
+			LastStatus: lastStatus,
+		})
+	}
+	//shutting down isn't the receiver's fault, the lease runs out and it's retried
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		return nil
+	}
+
+	attempts := delivery.Attempts + 1
+	return d.transaction.FailWebhookDelivery(ctx, database.FailWebhookDeliveryParams{
+		LastStatus:    lastStatus,
+		LastError:     sql.NullString{String: err.Error(), Valid: true},
+		NextAttemptAt: time.Now().Add(webhook.Backoff(int(attempts), d.backoff, d.maxBackoff)),
+		Dead:          attempts >= d.maxAttempts,
+		ID:            delivery.ID,
+	})
+}
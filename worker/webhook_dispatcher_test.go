@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	dbmock "github.com/ahmadfarhanstwn/twitter_wannabe/database/mock"
+	database "github.com/ahmadfarhanstwn/twitter_wannabe/database/sqlc"
+	"github.com/ahmadfarhanstwn/twitter_wannabe/webhook"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDispatcherDeliverPending(t *testing.T) {
+	received := map[string]string{}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.True(t, webhook.Verify("secret", r.Header.Get(webhook.SignatureHeader), r.Header.Get(webhook.TimestampHeader), body, time.Minute, time.Now()))
+		received[r.Header.Get(webhook.DeliveryHeader)] = string(body)
+
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	ok := database.ClaimWebhookDeliveriesRow{ID: 1, Event: database.WebhookLikeCreated, Payload: []byte(`{"n":1}`), Url: receiver.URL + "/up", Secret: "secret"}
+	failing := database.ClaimWebhookDeliveriesRow{ID: 2, Event: database.WebhookLikeCreated, Payload: []byte(`{"n":2}`), Attempts: 1, Url: receiver.URL + "/down", Secret: "secret"}
+	dying := database.ClaimWebhookDeliveriesRow{ID: 3, Event: database.WebhookLikeCreated, Payload: []byte(`{"n":3}`), Attempts: 4, Url: receiver.URL + "/down", Secret: "secret"}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	start := time.Now()
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).
+		Return([]database.ClaimWebhookDeliveriesRow{ok, failing, dying}, nil)
+	transaction.EXPECT().MarkWebhookDelivered(gomock.Any(), gomock.Eq(database.MarkWebhookDeliveredParams{
+		ID:         1,
+		LastStatus: sql.NullInt32{Int32: http.StatusOK, Valid: true},
+	})).Times(1).Return(nil)
+	transaction.EXPECT().FailWebhookDelivery(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, arg database.FailWebhookDeliveryParams) error {
+			require.Equal(t, int32(http.StatusInternalServerError), arg.LastStatus.Int32)
+			require.True(t, arg.LastError.Valid)
+			switch arg.ID {
+			case failing.ID:
+				//second failed attempt waits twice the base backoff
+				require.False(t, arg.Dead)
+				require.WithinDuration(t, start.Add(2*time.Minute), arg.NextAttemptAt, 5*time.Second)
+			case dying.ID:
+				require.True(t, arg.Dead)
+			default:
+				t.Fatalf("unexpected delivery %v", arg.ID)
+			}
+			return nil
+		})
+
+	//the receiver listens on loopback, which NewSender refuses
+	dispatcher := NewWebhookDispatcher(transaction, &webhook.Sender{Client: receiver.Client()}, 0, 5, time.Minute, time.Hour)
+	attempted, err := dispatcher.DeliverPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, attempted)
+
+	for _, delivery := range []database.ClaimWebhookDeliveriesRow{ok, failing, dying} {
+		require.Equal(t, string(delivery.Payload), received[strconv.FormatInt(delivery.ID, 10)])
+	}
+}
+
+func TestWebhookDispatcherDeliverPendingError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	transaction := dbmock.NewMockTransaction(controller)
+	transaction.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+	attempted, err := NewWebhookDispatcher(transaction, webhook.NewSender(time.Second), 0, 5, time.Minute, time.Hour).DeliverPending(context.Background())
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Zero(t, attempted)
+}